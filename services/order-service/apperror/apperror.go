@@ -0,0 +1,116 @@
+// Package apperror defines typed application errors and writes them as JSON
+// responses with a matching status code.
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Code identifies the kind of failure in an error response
+type Code string
+
+const (
+	CodeNotFound   Code = "not_found"
+	CodeValidation Code = "validation"
+	CodeForbidden  Code = "forbidden"
+	CodeConflict   Code = "conflict"
+	CodeDownstream Code = "downstream"
+	CodeTooLarge   Code = "too_large"
+	CodeInternal   Code = "internal"
+)
+
+// Error is an error tagged with the Code that determines its HTTP status
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// newError formats the message like fmt.Errorf, so %w keeps wrapped errors
+// reachable through errors.Is and errors.As
+func newError(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// NotFound reports that the requested resource doesn't exist (404)
+func NotFound(format string, args ...interface{}) *Error {
+	return newError(CodeNotFound, format, args...)
+}
+
+// Validation reports that the request itself is invalid (400)
+func Validation(format string, args ...interface{}) *Error {
+	return newError(CodeValidation, format, args...)
+}
+
+// Forbidden reports that the caller may not act on the resource (403)
+func Forbidden(format string, args ...interface{}) *Error {
+	return newError(CodeForbidden, format, args...)
+}
+
+// Conflict reports that the request conflicts with the current state (409)
+func Conflict(format string, args ...interface{}) *Error {
+	return newError(CodeConflict, format, args...)
+}
+
+// Downstream reports that a service this one depends on failed (502)
+func Downstream(format string, args ...interface{}) *Error {
+	return newError(CodeDownstream, format, args...)
+}
+
+// TooLarge reports that the request body exceeds the size limit (413)
+func TooLarge(format string, args ...interface{}) *Error {
+	return newError(CodeTooLarge, format, args...)
+}
+
+// Status returns the HTTP status code for an error code
+func Status(code Code) int {
+	switch code {
+	case CodeNotFound:
+		return http.StatusNotFound
+	case CodeValidation:
+		return http.StatusBadRequest
+	case CodeForbidden:
+		return http.StatusForbidden
+	case CodeConflict:
+		return http.StatusConflict
+	case CodeDownstream:
+		return http.StatusBadGateway
+	case CodeTooLarge:
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ErrorResponse is the JSON body written for every error
+type ErrorResponse struct {
+	Error struct {
+		Code    Code   `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// WriteError writes err as a JSON error response. The status comes from the
+// first *Error in err's chain; untyped errors are reported as internal.
+func WriteError(w http.ResponseWriter, err error) {
+	code := CodeInternal
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		code = appErr.Code
+	}
+
+	var body ErrorResponse
+	body.Error.Code = code
+	body.Error.Message = err.Error()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(Status(code))
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,64 @@
+// Package clients wraps the APIs of the services the order service depends
+// on. Each client builds its requests, applies the timeout, retry and circuit
+// breaker policy, maps statuses to application errors and validates what it
+// decodes, so callers deal only in DTOs.
+package clients
+
+import (
+	"context"
+	"log/slog"
+	"order-service/config"
+	"order-service/dto"
+)
+
+// MaxBatchIDs is the most IDs ProductClient.GetProducts accepts in one call,
+// matching the product service's limit
+const MaxBatchIDs = 100
+
+// UserClient fetches users from the user service
+type UserClient interface {
+	// GetUser returns a user, or an error wrapping DownstreamError
+	GetUser(ctx context.Context, id uint) (*dto.UserResponse, error)
+	// Ping checks the service's health endpoint, bypassing retries and the
+	// circuit breaker
+	Ping(ctx context.Context) error
+	// BreakerState reports the state of the client's circuit breaker
+	BreakerState() string
+}
+
+// ProductClient fetches products from the product service and adjusts their
+// stock
+type ProductClient interface {
+	// GetProduct returns a product, or an error wrapping DownstreamError
+	GetProduct(ctx context.Context, id uint) (*dto.ProductResponse, error)
+	// GetProducts fetches up to MaxBatchIDs products in one call. IDs that
+	// don't exist are absent from the result.
+	GetProducts(ctx context.Context, ids []uint) (map[uint]*dto.ProductResponse, error)
+	// AdjustStock changes a product's stock by delta. It is never retried.
+	AdjustStock(ctx context.Context, id uint, delta int) error
+	// Ping checks the service's health endpoint, bypassing retries and the
+	// circuit breaker
+	Ping(ctx context.Context) error
+	// BreakerState reports the state of the client's circuit breaker
+	BreakerState() string
+}
+
+// NewUserClient creates the user service client described by cfg
+func NewUserClient(cfg config.Config) UserClient {
+	return NewUserHTTPClient(cfg.UserServiceURL, cfg)
+}
+
+// NewProductClient creates the product service client described by cfg,
+// using gRPC for reads when PRODUCT_TRANSPORT is grpc
+func NewProductClient(cfg config.Config) ProductClient {
+	httpClient := NewProductHTTPClient(cfg.ProductServiceURL, cfg)
+	if cfg.ProductTransport != "grpc" {
+		return httpClient
+	}
+	grpcClient, err := NewProductGRPCClient(cfg.ProductServiceGRPCAddr, cfg.RequireHTTPSDownstream, httpClient)
+	if err != nil {
+		slog.Error("product gRPC client unavailable, using REST", "addr", cfg.ProductServiceGRPCAddr, "error", err)
+		return httpClient
+	}
+	return grpcClient
+}
@@ -0,0 +1,234 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"order-service/apperror"
+	"order-service/config"
+	"order-service/middleware"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testConfig retries once without a noticeable delay
+func testConfig() config.Config {
+	return config.Config{
+		HTTPClientTimeout:          2 * time.Second,
+		MaxIdleConnsPerHost:        10,
+		MaxDownstreamResponseBytes: 1 << 20,
+		MaxRetries:                 1,
+		RetryBaseDelay:             time.Millisecond,
+		BreakerThreshold:           5,
+		BreakerCooldown:            time.Minute,
+	}
+}
+
+// stubServer answers every request with status and body, counting requests
+func stubServer(t *testing.T, status int, body interface{}) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+// checkDownstreamError asserts err maps to code and carries the downstream
+// status
+func checkDownstreamError(t *testing.T, err error, code apperror.Code, status int, message string) {
+	t.Helper()
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Code != code {
+		t.Fatalf("err = %v, want code %s", err, code)
+	}
+	var dErr *DownstreamError
+	if !errors.As(err, &dErr) {
+		t.Fatalf("err = %v, want a DownstreamError", err)
+	}
+	if dErr.Status != status {
+		t.Errorf("downstream status = %d, want %d", dErr.Status, status)
+	}
+	if err.Error() != message {
+		t.Errorf("message = %q, want %q", err.Error(), message)
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	server, _ := stubServer(t, http.StatusOK, map[string]interface{}{"id": 5, "name": "Ada", "email": "ada@example.com"})
+	user, err := NewUserHTTPClient(server.URL, testConfig()).GetUser(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.ID != 5 || user.Name != "Ada" {
+		t.Errorf("user = %+v, want user 5", user)
+	}
+}
+
+func TestGetUserNotFound(t *testing.T) {
+	server, hits := stubServer(t, http.StatusNotFound, map[string]string{"error": "user not found"})
+	_, err := NewUserHTTPClient(server.URL, testConfig()).GetUser(context.Background(), 5)
+	checkDownstreamError(t, err, apperror.CodeValidation, http.StatusNotFound, "user 5 does not exist")
+	if hits.Load() != 1 {
+		t.Errorf("%d requests, want a 404 not to be retried", hits.Load())
+	}
+}
+
+func TestGetUserServerError(t *testing.T) {
+	server, hits := stubServer(t, http.StatusInternalServerError, nil)
+	cfg := testConfig()
+	_, err := NewUserHTTPClient(server.URL, cfg).GetUser(context.Background(), 5)
+	checkDownstreamError(t, err, apperror.CodeDownstream, http.StatusInternalServerError, "user service unavailable")
+	if want := int32(cfg.MaxRetries + 1); hits.Load() != want {
+		t.Errorf("%d requests, want %d", hits.Load(), want)
+	}
+}
+
+func TestGetUserUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	_, err := NewUserHTTPClient(server.URL, testConfig()).GetUser(context.Background(), 5)
+	checkDownstreamError(t, err, apperror.CodeDownstream, 0, "user service unavailable")
+}
+
+func TestGetProductDefaultsCurrency(t *testing.T) {
+	server, _ := stubServer(t, http.StatusOK, map[string]interface{}{"id": 7, "name": "Lamp", "price": 20})
+	product, err := NewProductHTTPClient(server.URL, testConfig()).GetProduct(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetProduct: %v", err)
+	}
+	if product.Currency != "USD" {
+		t.Errorf("currency = %q, want USD for a product service that predates currencies", product.Currency)
+	}
+}
+
+func TestGetProductNotFound(t *testing.T) {
+	server, _ := stubServer(t, http.StatusNotFound, map[string]string{"error": "product not found"})
+	_, err := NewProductHTTPClient(server.URL, testConfig()).GetProduct(context.Background(), 7)
+	checkDownstreamError(t, err, apperror.CodeValidation, http.StatusNotFound, "product 7 does not exist")
+}
+
+func TestGetProductServerError(t *testing.T) {
+	server, _ := stubServer(t, http.StatusServiceUnavailable, nil)
+	_, err := NewProductHTTPClient(server.URL, testConfig()).GetProduct(context.Background(), 7)
+	checkDownstreamError(t, err, apperror.CodeDownstream, http.StatusServiceUnavailable, "product service unavailable")
+}
+
+func TestGetProductsMissingEndpoint(t *testing.T) {
+	// A 404 from the batch endpoint means it doesn't exist, not that the
+	// products don't
+	server, _ := stubServer(t, http.StatusNotFound, nil)
+	_, err := NewProductHTTPClient(server.URL, testConfig()).GetProducts(context.Background(), []uint{1, 2})
+	checkDownstreamError(t, err, apperror.CodeDownstream, http.StatusNotFound, "product service unavailable")
+}
+
+func TestAdjustStock(t *testing.T) {
+	var gotToken, gotBody string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotToken = r.Header.Get(middleware.ServiceTokenHeader)
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := testConfig()
+	cfg.ServiceToken = "s3cret"
+	client := NewProductHTTPClient(server.URL, cfg)
+
+	if err := client.AdjustStock(context.Background(), 7, -2); err != nil {
+		t.Fatalf("AdjustStock: %v", err)
+	}
+	if gotToken != "s3cret" {
+		t.Errorf("service token = %q, want s3cret", gotToken)
+	}
+	if gotBody != `{"delta":-2}` {
+		t.Errorf("body = %s, want the delta", gotBody)
+	}
+
+	status = http.StatusConflict
+	if err := client.AdjustStock(context.Background(), 7, -2); !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("409: err = %v, want ErrInsufficientStock", err)
+	}
+}
+
+func TestGetProductResponseTooLarge(t *testing.T) {
+	server, _ := stubServer(t, http.StatusOK, map[string]interface{}{
+		"id": 7, "name": "Lamp", "price": 20, "description": strings.Repeat("x", 2048),
+	})
+	cfg := testConfig()
+	cfg.MaxDownstreamResponseBytes = 1024
+
+	_, err := NewProductHTTPClient(server.URL, cfg).GetProduct(context.Background(), 7)
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Code != apperror.CodeDownstream {
+		t.Fatalf("err = %v, want a downstream error", err)
+	}
+	if !strings.Contains(err.Error(), "exceeds limit of 1024 bytes") {
+		t.Errorf("message = %q, want it to name the limit", err.Error())
+	}
+
+	cfg.MaxDownstreamResponseBytes = 1 << 20
+	if _, err := NewProductHTTPClient(server.URL, cfg).GetProduct(context.Background(), 7); err != nil {
+		t.Errorf("under the limit: %v", err)
+	}
+}
+
+func TestDownstreamResponseMissingFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    map[string]interface{}
+		fetch   func(url string) error
+		message string
+	}{
+		{
+			name: "user without id",
+			body: map[string]interface{}{"name": "Ada", "email": "ada@example.com"},
+			fetch: func(url string) error {
+				_, err := NewUserHTTPClient(url, testConfig()).GetUser(context.Background(), 5)
+				return err
+			},
+			message: "invalid user response: missing id",
+		},
+		{
+			name: "user with another id",
+			body: map[string]interface{}{"id": 6, "name": "Ada", "email": "ada@example.com"},
+			fetch: func(url string) error {
+				_, err := NewUserHTTPClient(url, testConfig()).GetUser(context.Background(), 5)
+				return err
+			},
+			message: "invalid user response: id 6 does not match requested id 5",
+		},
+		{
+			name: "product without id",
+			body: map[string]interface{}{"name": "Lamp", "price": 20},
+			fetch: func(url string) error {
+				_, err := NewProductHTTPClient(url, testConfig()).GetProduct(context.Background(), 7)
+				return err
+			},
+			message: "invalid product response: missing id",
+		},
+	}
+	for _, tt := range tests {
+		server, _ := stubServer(t, http.StatusOK, tt.body)
+		err := tt.fetch(server.URL)
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || appErr.Code != apperror.CodeDownstream {
+			t.Errorf("%s: err = %v, want a downstream error", tt.name, err)
+			continue
+		}
+		if err.Error() != tt.message {
+			t.Errorf("%s: message = %q, want %q", tt.name, err.Error(), tt.message)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"order-service/apperror"
+	"order-service/config"
+	"order-service/internal/breaker"
+	"order-service/logging"
+	"order-service/middleware"
+	"time"
+)
+
+// downstream holds what every HTTP client needs to call one service
+type downstream struct {
+	service          string // "user" or "product", as used in errors
+	baseURL          string
+	httpClient       *http.Client
+	maxResponseBytes int64
+	maxRetries       int
+	retryBaseDelay   time.Duration
+	breaker          *breaker.Breaker
+}
+
+func newDownstream(service, baseURL string, cfg config.Config) downstream {
+	return downstream{
+		service:          service,
+		baseURL:          baseURL,
+		httpClient:       newHTTPClient(cfg.HTTPClientTimeout, cfg.MaxIdleConnsPerHost),
+		maxResponseBytes: cfg.MaxDownstreamResponseBytes,
+		maxRetries:       cfg.MaxRetries,
+		retryBaseDelay:   cfg.RetryBaseDelay,
+		breaker:          breaker.New(cfg.BreakerThreshold, cfg.BreakerCooldown),
+	}
+}
+
+// newHTTPClient builds the client for one downstream service. Its transport
+// keeps idle connections open so that bursts of orders reuse them instead of
+// dialing (and leaving a TIME_WAIT socket behind) for every request.
+func newHTTPClient(timeout time.Duration, maxIdleConnsPerHost int) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = false
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	if transport.MaxIdleConns < transport.MaxIdleConnsPerHost*2 {
+		transport.MaxIdleConns = transport.MaxIdleConnsPerHost * 2
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// BreakerState reports the state of the service's circuit breaker
+func (d *downstream) BreakerState() string {
+	return d.breaker.State().String()
+}
+
+// Ping checks the service's /health endpoint once, bypassing retries and the
+// circuit breaker so probes report the service's actual state
+func (d *downstream) Ping(ctx context.Context) error {
+	req, err := newRequest(ctx, http.MethodGet, d.baseURL+"/health", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// getJSON fetches path and decodes the 200 response into v. Any other status
+// is returned as an error wrapping DownstreamError for id.
+func (d *downstream) getJSON(ctx context.Context, path string, id uint, v interface{}) error {
+	url := d.baseURL + path
+
+	resp, err := d.get(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("%s service returned status %d", d.service, resp.StatusCode)
+		logFailure(ctx, url, resp.StatusCode, err)
+		return NewError(d.service, id, resp.StatusCode, err)
+	}
+
+	if err := d.decode(resp.Body, v); err != nil {
+		return apperror.Downstream("failed to decode %s: %v", d.service, err)
+	}
+	return nil
+}
+
+// get performs a GET through the circuit breaker. Connection failures,
+// timeouts and 5xx responses count as breaker failures and are returned as an
+// unavailable DownstreamError; any other response is returned for the caller
+// to handle.
+func (d *downstream) get(ctx context.Context, url string) (*http.Response, error) {
+	var (
+		resp   *http.Response
+		status int
+	)
+	err := d.breaker.Execute(func() error {
+		r, attempts, err := d.getWithRetry(ctx, url)
+		if err != nil {
+			if isTimeout(err) {
+				return fmt.Errorf("%s service timed out after %s", d.service, d.httpClient.Timeout)
+			}
+			return fmt.Errorf("failed to fetch %s after %d attempts: %v", d.service, attempts, err)
+		}
+		if r.StatusCode >= http.StatusInternalServerError {
+			status = r.StatusCode
+			drainAndClose(r)
+			return fmt.Errorf("%s service returned status %d after %d attempts", d.service, r.StatusCode, attempts)
+		}
+		resp = r
+		return nil
+	})
+	if err != nil {
+		logFailure(ctx, url, status, err)
+		return nil, NewError(d.service, 0, status, err)
+	}
+	return resp, nil
+}
+
+// getWithRetry issues a GET, retrying connection errors and 5xx responses with
+// exponential backoff plus jitter. 4xx responses are deterministic and are
+// returned immediately, as are client timeouts since the timeout already
+// bounds the call. Retrying stops once ctx is done. It returns the final
+// response or error together with the number of attempts made.
+func (d *downstream) getWithRetry(ctx context.Context, url string) (*http.Response, int, error) {
+	delay := d.retryBaseDelay
+	attempts := 0
+	for {
+		attempts++
+		req, err := newRequest(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, attempts, err
+		}
+		resp, err := d.httpClient.Do(req)
+
+		retryable := false
+		switch {
+		case err != nil:
+			retryable = !isTimeout(err)
+		case resp.StatusCode >= http.StatusInternalServerError:
+			retryable = true
+		}
+
+		if !retryable || attempts > d.maxRetries {
+			return resp, attempts, err
+		}
+
+		// Drain and close the failed response so the connection can be reused
+		if resp != nil {
+			drainAndClose(resp)
+		}
+
+		if err := d.backoff(ctx, &delay, url, attempts); err != nil {
+			return nil, attempts, err
+		}
+	}
+}
+
+// backoff waits before the next attempt, doubling delay for the one after,
+// and returns ctx's error if it is done first
+func (d *downstream) backoff(ctx context.Context, delay *time.Duration, target string, attempts int) error {
+	wait := *delay + time.Duration(rand.Int63n(int64(*delay)/2+1))
+	logging.PrintfContext(ctx, "Downstream call to %s failed (attempt %d/%d), retrying in %s", target, attempts, d.maxRetries+1, wait)
+	select {
+	case <-time.After(wait):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	*delay *= 2
+	return nil
+}
+
+// decode decodes a JSON body into v, refusing to read more than the
+// configured limit so an oversized response can't exhaust memory
+func (d *downstream) decode(body io.Reader, v interface{}) error {
+	limit := d.maxResponseBytes
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > limit {
+		return fmt.Errorf("response body exceeds limit of %d bytes", limit)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// newRequest builds a request to another service bound to ctx, forwarding the
+// request ID so the whole call chain shares one ID
+func newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if id := middleware.RequestIDFromContext(ctx); id != "" {
+		req.Header.Set(middleware.RequestIDHeader, id)
+	}
+	return req, nil
+}
+
+// drainAndClose discards any unread body before closing it; the transport only
+// returns a connection to the idle pool once its body has been read to EOF.
+func drainAndClose(resp *http.Response) {
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// logFailure logs a failed downstream call at warn. The status is omitted
+// when no response was received.
+func logFailure(ctx context.Context, target string, status int, err error) {
+	args := []interface{}{"url", target, "error", err}
+	if status != 0 {
+		args = append(args, "status", status)
+	}
+	slog.WarnContext(ctx, "downstream fetch failed", args...)
+}
+
+// isTimeout reports whether err was caused by the HTTP client timing out
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,47 @@
+package clients
+
+import (
+	"fmt"
+	"net/http"
+	"order-service/apperror"
+)
+
+// DownstreamError is a failed fetch from the user or product service. Status
+// is the HTTP status the service answered with (or its equivalent for gRPC),
+// or 0 when no response was received (connection failure, timeout or open
+// circuit).
+type DownstreamError struct {
+	Service string // "user" or "product"
+	ID      uint   // the requested ID, when there was a single one
+	Status  int
+	Err     error
+}
+
+// Error describes the failure as the client should see it; the underlying
+// cause is logged where the call fails
+func (e *DownstreamError) Error() string {
+	if e.NotFound() {
+		return fmt.Sprintf("%s %d does not exist", e.Service, e.ID)
+	}
+	return e.Service + " service unavailable"
+}
+
+func (e *DownstreamError) Unwrap() error { return e.Err }
+
+// NotFound reports whether the service answered that the requested entity
+// doesn't exist. A 404 without an ID (such as from a batch endpoint) means
+// the endpoint itself is missing, so it counts as unavailable.
+func (e *DownstreamError) NotFound() bool {
+	return e.Status == http.StatusNotFound && e.ID != 0
+}
+
+// NewError tags a failed fetch with the code it maps to: referencing a user
+// or product that doesn't exist is a bad request (400), while any other
+// failure means the service is unavailable (502)
+func NewError(service string, id uint, status int, err error) error {
+	dErr := &DownstreamError{Service: service, ID: id, Status: status, Err: err}
+	if dErr.NotFound() {
+		return apperror.Validation("%w", dErr)
+	}
+	return apperror.Downstream("%w", dErr)
+}
@@ -0,0 +1,172 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"order-service/apperror"
+	"order-service/config"
+	"order-service/dto"
+	"order-service/internal/breaker"
+	"order-service/middleware"
+	"strconv"
+	"strings"
+)
+
+// ErrInsufficientStock is returned when the product service can't cover the
+// requested quantity
+var ErrInsufficientStock = apperror.Conflict("insufficient stock")
+
+// ProductHTTPClient calls the product service's REST API. Stock adjustments
+// carry serviceToken, which the product service requires on that endpoint.
+type ProductHTTPClient struct {
+	downstream
+	serviceToken string
+}
+
+// NewProductHTTPClient creates a client for the product service at baseURL
+// with the timeout, retry and circuit breaker settings from cfg
+func NewProductHTTPClient(baseURL string, cfg config.Config) *ProductHTTPClient {
+	return &ProductHTTPClient{downstream: newDownstream("product", baseURL, cfg), serviceToken: cfg.ServiceToken}
+}
+
+// GetProduct fetches a product with GET /products?id=
+func (c *ProductHTTPClient) GetProduct(ctx context.Context, id uint) (*dto.ProductResponse, error) {
+	var product dto.ProductResponse
+	if err := c.getJSON(ctx, fmt.Sprintf("/products?id=%d", id), id, &product); err != nil {
+		return nil, err
+	}
+
+	if err := validateProduct(&product, id); err != nil {
+		return nil, apperror.Downstream("invalid product response: %v", err)
+	}
+	return &product, nil
+}
+
+// GetProducts fetches products with GET /products/batch and validates every
+// product in the response
+func (c *ProductHTTPClient) GetProducts(ctx context.Context, ids []uint) (map[uint]*dto.ProductResponse, error) {
+	idStrs := make([]string, len(ids))
+	for i, id := range ids {
+		idStrs[i] = strconv.FormatUint(uint64(id), 10)
+	}
+
+	var list []dto.ProductResponse
+	if err := c.getJSON(ctx, "/products/batch?ids="+strings.Join(idStrs, ","), 0, &list); err != nil {
+		return nil, err
+	}
+
+	products := make([]*dto.ProductResponse, len(list))
+	for i := range list {
+		products[i] = &list[i]
+	}
+	return collectProducts(ids, products)
+}
+
+// AdjustStock changes a product's stock by delta with POST /products/stock,
+// authenticated with the service token. The call is not retried: a POST that
+// timed out may still have been applied, and retrying it could adjust stock
+// twice.
+func (c *ProductHTTPClient) AdjustStock(ctx context.Context, id uint, delta int) error {
+	url := fmt.Sprintf("%s/products/stock?id=%d", c.baseURL, id)
+	body, err := json.Marshal(map[string]int{"delta": delta})
+	if err != nil {
+		return err
+	}
+
+	var status int
+	var message string
+	err = c.breaker.Execute(func() error {
+		req, err := newRequest(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if c.serviceToken != "" {
+			req.Header.Set(middleware.ServiceTokenHeader, c.serviceToken)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if isTimeout(err) {
+				return fmt.Errorf("product service timed out after %s", c.httpClient.Timeout)
+			}
+			return fmt.Errorf("failed to adjust stock: %v", err)
+		}
+		defer resp.Body.Close()
+
+		status = resp.StatusCode
+		if status != http.StatusOK {
+			b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			message = string(bytes.TrimSpace(b))
+		} else {
+			io.Copy(io.Discard, resp.Body)
+		}
+		if status >= http.StatusInternalServerError {
+			return fmt.Errorf("product service returned status %d", status)
+		}
+		return nil
+	})
+	if errors.Is(err, breaker.ErrOpen) {
+		return apperror.Downstream("product service unavailable: %v", err)
+	}
+	if err != nil {
+		return apperror.Downstream("%w", err)
+	}
+
+	switch status {
+	case http.StatusOK:
+		return nil
+	case http.StatusConflict:
+		return fmt.Errorf("%w for product %d", ErrInsufficientStock, id)
+	default:
+		return apperror.Validation("product service returned status %d adjusting stock: %s", status, message)
+	}
+}
+
+// collectProducts checks a batch response against the requested IDs and
+// keys it by ID
+func collectProducts(ids []uint, products []*dto.ProductResponse) (map[uint]*dto.ProductResponse, error) {
+	requested := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		requested[id] = true
+	}
+
+	found := make(map[uint]*dto.ProductResponse, len(products))
+	for _, product := range products {
+		if !requested[product.ID] {
+			return nil, apperror.Downstream("invalid product response: unexpected product %d", product.ID)
+		}
+		if err := validateProduct(product, product.ID); err != nil {
+			return nil, apperror.Downstream("invalid product response: %v", err)
+		}
+		found[product.ID] = product
+	}
+	return found, nil
+}
+
+// validateProduct checks that a decoded product carries the fields the order
+// service relies on and is the product that was requested. A product without
+// a currency comes from a product service that predates them and is priced
+// in USD.
+func validateProduct(product *dto.ProductResponse, productID uint) error {
+	if product.Currency == "" {
+		product.Currency = "USD"
+	}
+
+	switch {
+	case product.ID == 0:
+		return errors.New("missing id")
+	case product.ID != productID:
+		return fmt.Errorf("id %d does not match requested id %d", product.ID, productID)
+	case product.Name == "":
+		return errors.New("missing name")
+	case product.Price <= 0:
+		return errors.New("missing price")
+	}
+	return nil
+}
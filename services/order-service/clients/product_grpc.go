@@ -0,0 +1,195 @@
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"order-service/apperror"
+	"order-service/dto"
+	"order-service/middleware"
+	pb "order-service/proto/productpb"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ProductGRPCClient reads products through the product service's gRPC API.
+// Stock adjustments and health checks still go over REST, and both
+// transports share one circuit breaker.
+type ProductGRPCClient struct {
+	*ProductHTTPClient
+	rpc pb.ProductServiceClient
+}
+
+// NewProductGRPCClient creates a client for the gRPC API at addr, falling
+// back on rest for the calls gRPC doesn't cover. The connection uses TLS when
+// requireTLS is set and is established lazily on the first call.
+func NewProductGRPCClient(addr string, requireTLS bool, rest *ProductHTTPClient) (*ProductGRPCClient, error) {
+	creds := insecure.NewCredentials()
+	if requireTLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	return &ProductGRPCClient{ProductHTTPClient: rest, rpc: pb.NewProductServiceClient(conn)}, nil
+}
+
+// GetProduct fetches a product with the GetProduct RPC
+func (c *ProductGRPCClient) GetProduct(ctx context.Context, id uint) (*dto.ProductResponse, error) {
+	var resp *pb.ProductResponse
+	err := c.call(ctx, "GetProduct", func(ctx context.Context) (err error) {
+		resp, err = c.rpc.GetProduct(ctx, &pb.GetProductRequest{Id: uint32(id)})
+		return err
+	})
+	if err != nil {
+		return nil, grpcError(id, err)
+	}
+
+	product, err := productFromProto(resp)
+	if err == nil {
+		err = validateProduct(product, id)
+	}
+	if err != nil {
+		return nil, apperror.Downstream("invalid product response: %v", err)
+	}
+	return product, nil
+}
+
+// GetProducts fetches products with the GetProductsByIDs RPC
+func (c *ProductGRPCClient) GetProducts(ctx context.Context, ids []uint) (map[uint]*dto.ProductResponse, error) {
+	req := &pb.GetProductsByIDsRequest{Ids: make([]uint32, len(ids))}
+	for i, id := range ids {
+		req.Ids[i] = uint32(id)
+	}
+
+	var resp *pb.GetProductsByIDsResponse
+	err := c.call(ctx, "GetProductsByIDs", func(ctx context.Context) (err error) {
+		resp, err = c.rpc.GetProductsByIDs(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, grpcError(0, err)
+	}
+
+	products := make([]*dto.ProductResponse, 0, len(resp.GetProducts()))
+	for _, msg := range resp.GetProducts() {
+		product, err := productFromProto(msg)
+		if err != nil {
+			return nil, apperror.Downstream("invalid product response: %v", err)
+		}
+		products = append(products, product)
+	}
+	return collectProducts(ids, products)
+}
+
+// call runs an RPC through the circuit breaker with the same timeout and
+// retry policy as REST calls. Only Unavailable is retried, and only errors
+// that mean the service is unhealthy count as breaker failures; answers such
+// as NotFound are returned as they are.
+func (c *ProductGRPCClient) call(ctx context.Context, method string, rpc func(ctx context.Context) error) error {
+	if id := middleware.RequestIDFromContext(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, middleware.RequestIDHeader, id)
+	}
+	timeout := c.httpClient.Timeout
+
+	var answer error
+	err := c.breaker.Execute(func() error {
+		delay := c.retryBaseDelay
+		for attempts := 1; ; attempts++ {
+			callCtx, cancel := context.WithTimeout(ctx, timeout)
+			err := rpc(callCtx)
+			cancel()
+
+			switch status.Code(err) {
+			case codes.OK:
+				return nil
+			case codes.NotFound, codes.InvalidArgument:
+				answer = err
+				return nil
+			case codes.DeadlineExceeded:
+				return fmt.Errorf("product service timed out after %s", timeout)
+			case codes.Unavailable:
+				if attempts > c.maxRetries {
+					return fmt.Errorf("failed to fetch product after %d attempts: %v", attempts, err)
+				}
+			default:
+				return err
+			}
+
+			if err := c.backoff(ctx, &delay, "product gRPC "+method, attempts); err != nil {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		logFailure(ctx, "grpc:"+method, 0, err)
+		return err
+	}
+	return answer
+}
+
+// grpcError maps a failed RPC to the DownstreamError a REST call would
+// return, so callers handle both transports alike
+func grpcError(productID uint, err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return NewError("product", productID, http.StatusNotFound, err)
+	case codes.InvalidArgument:
+		return NewError("product", productID, http.StatusBadRequest, err)
+	default:
+		return NewError("product", 0, 0, err)
+	}
+}
+
+// productFromProto converts a gRPC product message to the DTO REST calls
+// decode into
+func productFromProto(msg *pb.ProductResponse) (*dto.ProductResponse, error) {
+	product := &dto.ProductResponse{
+		ID:          uint(msg.GetId()),
+		Name:        msg.GetName(),
+		Description: msg.GetDescription(),
+		Price:       msg.GetPrice(),
+		Currency:    msg.GetCurrency(),
+		Category:    msg.GetCategory(),
+		WeightGrams: int(msg.GetWeightGrams()),
+		DimensionsCM: dto.Dimensions{
+			Length: msg.GetDimensionsCm().GetLength(),
+			Width:  msg.GetDimensionsCm().GetWidth(),
+			Height: msg.GetDimensionsCm().GetHeight(),
+		},
+	}
+
+	var errs []error
+	parse := func(field, value string, required bool) *time.Time {
+		if value == "" {
+			if required {
+				errs = append(errs, fmt.Errorf("missing %s", field))
+			}
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %v", field, err))
+			return nil
+		}
+		return &t
+	}
+	product.AvailableFrom = parse("available_from", msg.GetAvailableFrom(), false)
+	product.AvailableUntil = parse("available_until", msg.GetAvailableUntil(), false)
+	if t := parse("created_at", msg.GetCreatedAt(), true); t != nil {
+		product.CreatedAt = *t
+	}
+	if t := parse("updated_at", msg.GetUpdatedAt(), true); t != nil {
+		product.UpdatedAt = *t
+	}
+	return product, errors.Join(errs...)
+}
@@ -0,0 +1,60 @@
+package clients
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"order-service/apperror"
+	pb "order-service/proto/productpb"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcProducts is a gRPC product service that knows product 7 only
+type grpcProducts struct {
+	pb.UnimplementedProductServiceServer
+}
+
+func (grpcProducts) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.ProductResponse, error) {
+	if req.GetId() != 7 {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	return &pb.ProductResponse{Id: 7, Name: "Lamp", Price: 20, CreatedAt: now, UpdatedAt: now}, nil
+}
+
+func TestProductGRPCClientGetProduct(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	pb.RegisterProductServiceServer(server, grpcProducts{})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	client, err := NewProductGRPCClient(lis.Addr().String(), false, NewProductHTTPClient("", testConfig()))
+	if err != nil {
+		t.Fatalf("NewProductGRPCClient: %v", err)
+	}
+	ctx := context.Background()
+
+	product, err := client.GetProduct(ctx, 7)
+	if err != nil {
+		t.Fatalf("GetProduct: %v", err)
+	}
+	if product.ID != 7 || product.Name != "Lamp" || product.Price != 20 {
+		t.Errorf("product = %+v, want the Lamp", product)
+	}
+
+	_, err = client.GetProduct(ctx, 8)
+	checkDownstreamError(t, err, apperror.CodeValidation, http.StatusNotFound, "product 8 does not exist")
+
+	server.Stop()
+	_, err = client.GetProduct(ctx, 7)
+	checkDownstreamError(t, err, apperror.CodeDownstream, 0, "product service unavailable")
+}
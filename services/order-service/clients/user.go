@@ -0,0 +1,50 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/apperror"
+	"order-service/config"
+	"order-service/dto"
+)
+
+// UserHTTPClient calls the user service's REST API
+type UserHTTPClient struct {
+	downstream
+}
+
+// NewUserHTTPClient creates a client for the user service at baseURL with
+// the timeout, retry and circuit breaker settings from cfg
+func NewUserHTTPClient(baseURL string, cfg config.Config) *UserHTTPClient {
+	return &UserHTTPClient{downstream: newDownstream("user", baseURL, cfg)}
+}
+
+// GetUser fetches a user with GET /users?id=
+func (c *UserHTTPClient) GetUser(ctx context.Context, id uint) (*dto.UserResponse, error) {
+	var user dto.UserResponse
+	if err := c.getJSON(ctx, fmt.Sprintf("/users?id=%d", id), id, &user); err != nil {
+		return nil, err
+	}
+
+	if err := validateUser(&user, id); err != nil {
+		return nil, apperror.Downstream("invalid user response: %v", err)
+	}
+	return &user, nil
+}
+
+// validateUser checks that a decoded user carries the fields the order
+// service relies on and is the user that was requested
+func validateUser(user *dto.UserResponse, userID uint) error {
+	switch {
+	case user.ID == 0:
+		return errors.New("missing id")
+	case user.ID != userID:
+		return fmt.Errorf("id %d does not match requested id %d", user.ID, userID)
+	case user.Name == "":
+		return errors.New("missing name")
+	case user.Email == "":
+		return errors.New("missing email")
+	}
+	return nil
+}
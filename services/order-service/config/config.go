@@ -0,0 +1,286 @@
+// Package config loads the service's settings from environment variables
+// once at startup. Unset variables take their defaults; malformed ones are
+// reported together by Load so the service can refuse to start.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Config holds the order service's settings
+type Config struct {
+	Port     int    // PORT
+	LogLevel string // LOG_LEVEL: debug, info, warn or error
+	Database Database
+
+	// PIIFields names the fields masked in log lines besides email addresses
+	// (PII_FIELDS, comma-separated)
+	PIIFields []string
+
+	// Downstream services
+	UserServiceURL             string        // USER_SERVICE_URL
+	ProductServiceURL          string        // PRODUCT_SERVICE_URL
+	RequireHTTPSDownstream     bool          // REQUIRE_HTTPS_DOWNSTREAM
+	ProductTransport           string        // PRODUCT_TRANSPORT: http or grpc
+	ProductServiceGRPCAddr     string        // PRODUCT_SERVICE_GRPC_ADDR, host:port
+	ServiceToken               string        // SERVICE_TOKEN, sent with stock adjustments
+	HTTPClientTimeout          time.Duration // HTTP_CLIENT_TIMEOUT
+	MaxIdleConnsPerHost        int           // HTTP_MAX_IDLE_CONNS_PER_HOST
+	MaxDownstreamResponseBytes int64         // MAX_DOWNSTREAM_RESPONSE_BYTES
+	MaxRetries                 int           // DOWNSTREAM_MAX_RETRIES
+	RetryBaseDelay             time.Duration // DOWNSTREAM_RETRY_BASE_DELAY
+	BreakerThreshold           int           // BREAKER_FAILURE_THRESHOLD
+	BreakerCooldown            time.Duration // BREAKER_COOLDOWN
+	EnrichmentConcurrency      int           // ENRICHMENT_CONCURRENCY
+	EnrichmentStaleFallback    bool          // ENRICHMENT_STALE_FALLBACK
+
+	HTTP HTTP
+
+	Events Events
+
+	CacheControlOrders string // CACHE_CONTROL_ORDERS
+	CacheControlHealth string // CACHE_CONTROL_HEALTH
+
+	// ShippingRates is the rate table for shipping estimates
+	// (SHIPPING_RATE_TABLE); nil uses the built-in rates
+	ShippingRates []ShippingRate
+
+	// TimestampMaxFuture is how far ahead of now an input timestamp may be
+	// (TIMESTAMP_MAX_FUTURE)
+	TimestampMaxFuture time.Duration
+
+	// ShutdownTimeout bounds how long in-flight requests and queued events
+	// may take to finish on SIGINT or SIGTERM (SHUTDOWN_TIMEOUT)
+	ShutdownTimeout time.Duration
+}
+
+// Load reads the configuration from the environment
+func Load() (Config, error) {
+	var l loader
+	cfg := Config{
+		Port:     l.port("PORT", 8082),
+		LogLevel: l.logLevel("LOG_LEVEL"),
+		Database: l.database("order_service"),
+
+		PIIFields: l.list("PII_FIELDS"),
+
+		UserServiceURL:             l.url("USER_SERVICE_URL", "http://localhost:8080"),
+		ProductServiceURL:          l.url("PRODUCT_SERVICE_URL", "http://localhost:8081"),
+		RequireHTTPSDownstream:     l.bool("REQUIRE_HTTPS_DOWNSTREAM", false),
+		ProductTransport:           l.oneOf("PRODUCT_TRANSPORT", "http", "http", "grpc"),
+		ProductServiceGRPCAddr:     l.string("PRODUCT_SERVICE_GRPC_ADDR", "localhost:9081"),
+		ServiceToken:               l.string("SERVICE_TOKEN", ""),
+		HTTPClientTimeout:          l.duration("HTTP_CLIENT_TIMEOUT", 5*time.Second),
+		MaxIdleConnsPerHost:        l.int("HTTP_MAX_IDLE_CONNS_PER_HOST", 100, 1),
+		MaxDownstreamResponseBytes: int64(l.int("MAX_DOWNSTREAM_RESPONSE_BYTES", 1<<20, 1)),
+		MaxRetries:                 l.int("DOWNSTREAM_MAX_RETRIES", 3, 0),
+		RetryBaseDelay:             l.duration("DOWNSTREAM_RETRY_BASE_DELAY", 100*time.Millisecond),
+		BreakerThreshold:           l.int("BREAKER_FAILURE_THRESHOLD", 5, 1),
+		BreakerCooldown:            l.duration("BREAKER_COOLDOWN", 30*time.Second),
+		EnrichmentConcurrency:      l.int("ENRICHMENT_CONCURRENCY", 4, 1),
+		EnrichmentStaleFallback:    l.bool("ENRICHMENT_STALE_FALLBACK", false),
+
+		HTTP: l.http(),
+
+		Events: l.events(),
+
+		CacheControlOrders: l.string("CACHE_CONTROL_ORDERS", "no-store"),
+		CacheControlHealth: l.string("CACHE_CONTROL_HEALTH", "no-store"),
+
+		ShippingRates:      l.shippingRates("SHIPPING_RATE_TABLE"),
+		TimestampMaxFuture: l.duration("TIMESTAMP_MAX_FUTURE", 24*time.Hour),
+
+		ShutdownTimeout: l.duration("SHUTDOWN_TIMEOUT", 10*time.Second),
+	}
+
+	if cfg.ProductTransport == "grpc" {
+		if _, _, err := net.SplitHostPort(cfg.ProductServiceGRPCAddr); err != nil {
+			l.fail("PRODUCT_SERVICE_GRPC_ADDR", cfg.ProductServiceGRPCAddr, "must be host:port")
+		}
+	}
+
+	// PII travels to the user service, so plaintext is refused when required
+	if cfg.RequireHTTPSDownstream {
+		for key, url := range map[string]string{
+			"USER_SERVICE_URL":    cfg.UserServiceURL,
+			"PRODUCT_SERVICE_URL": cfg.ProductServiceURL,
+		} {
+			if !strings.HasPrefix(url, "https://") {
+				l.fail(key, url, "must use https:// when REQUIRE_HTTPS_DOWNSTREAM is enabled")
+			}
+		}
+		// The product service's gRPC API is served without TLS
+		if cfg.ProductTransport == "grpc" {
+			l.fail("PRODUCT_TRANSPORT", cfg.ProductTransport, "must be http when REQUIRE_HTTPS_DOWNSTREAM is enabled, as gRPC is served without TLS")
+		}
+	}
+
+	return cfg, l.err()
+}
+
+// Database holds the database connection settings. The DB_HOST family of
+// variables configures PostgreSQL; SQLitePath is used only with sqlite.
+type Database struct {
+	Driver     string // DB_DRIVER: postgres or sqlite
+	Host       string // DB_HOST
+	Port       int    // DB_PORT
+	User       string // DB_USER
+	Password   string // DB_PASSWORD
+	Name       string // DB_NAME
+	SSLMode    string // DB_SSLMODE
+	SQLitePath string // DB_SQLITE_PATH: a file, or :memory:
+
+	// ConnectAttempts is how many times to try connecting at startup
+	// (DB_CONNECT_ATTEMPTS), ConnectRetryDelay the wait between attempts
+	// (DB_CONNECT_RETRY_DELAY)
+	ConnectAttempts   int
+	ConnectRetryDelay time.Duration
+
+	MaxOpenConns    int           // DB_MAX_OPEN_CONNS
+	MaxIdleConns    int           // DB_MAX_IDLE_CONNS
+	ConnMaxLifetime time.Duration // DB_CONN_MAX_LIFETIME
+}
+
+// DSN returns the connection string for the configured driver
+func (d Database) DSN() string {
+	if d.Driver == "sqlite" {
+		return d.SQLitePath
+	}
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode)
+}
+
+// HTTP holds the settings of the HTTP server and its middleware
+type HTTP struct {
+	RequireIdempotencyKey bool   // REQUIRE_IDEMPOTENCY_KEY
+	FeatureFlags          string // FEATURE_FLAGS, parsed by middleware.ParseFlags
+	JWTSecret             string // JWT_SECRET; empty disables authentication
+	MaxHeaderBytes        int    // MAX_HEADER_BYTES
+	MaxBodyBytes          int64  // MAX_BODY_BYTES
+	MaxRepeatedValues     int    // MAX_REPEATED_VALUES
+	AllowedOrigins        string // ALLOWED_ORIGINS
+	TrailingSlashMode     string // TRAILING_SLASH_MODE: rewrite or redirect
+
+	// RateLimitRPS is the per-client rate (RATE_LIMIT_RPS); zero disables
+	// rate limiting. RateLimitBurst defaults to the rate rounded up.
+	RateLimitRPS        float64
+	RateLimitBurst      int  // RATE_LIMIT_BURST
+	RateLimitTrustProxy bool // RATE_LIMIT_TRUST_PROXY
+}
+
+func (l *loader) http() HTTP {
+	cfg := HTTP{
+		RequireIdempotencyKey: l.bool("REQUIRE_IDEMPOTENCY_KEY", false),
+		FeatureFlags:          l.string("FEATURE_FLAGS", ""),
+		JWTSecret:             l.string("JWT_SECRET", ""),
+		MaxHeaderBytes:        l.int("MAX_HEADER_BYTES", http.DefaultMaxHeaderBytes, 1),
+		MaxBodyBytes:          int64(l.int("MAX_BODY_BYTES", 1<<20, 1)),
+		MaxRepeatedValues:     l.int("MAX_REPEATED_VALUES", 20, 1),
+		AllowedOrigins:        l.string("ALLOWED_ORIGINS", ""),
+		TrailingSlashMode:     l.oneOf("TRAILING_SLASH_MODE", "rewrite", "rewrite", "redirect"),
+		RateLimitRPS:          l.rate("RATE_LIMIT_RPS"),
+		RateLimitTrustProxy:   l.bool("RATE_LIMIT_TRUST_PROXY", false),
+	}
+	cfg.RateLimitBurst = l.int("RATE_LIMIT_BURST", int(math.Ceil(cfg.RateLimitRPS)), 1)
+	return cfg
+}
+
+// ShippingRate is one tier of the shipping rate table. A tier applies to
+// parcels up to MaxGrams billable weight; MaxGrams 0 means no upper bound.
+type ShippingRate struct {
+	MaxGrams int     `json:"max_grams"`
+	Base     float64 `json:"base"`
+	PerKg    float64 `json:"per_kg"`
+}
+
+// shippingRates reads a JSON array of {"max_grams", "base", "per_kg"} tiers
+// and orders it by weight, with the unbounded tier (if any) last. Unset is
+// nil.
+func (l *loader) shippingRates(key string) []ShippingRate {
+	value := l.string(key, "")
+	if value == "" {
+		return nil
+	}
+
+	var rates []ShippingRate
+	if err := json.Unmarshal([]byte(value), &rates); err != nil || len(rates) == 0 {
+		l.fail(key, value, "must be a non-empty JSON array of {\"max_grams\", \"base\", \"per_kg\"} tiers")
+		return nil
+	}
+	for _, r := range rates {
+		if r.MaxGrams < 0 || r.Base < 0 || r.PerKg < 0 {
+			l.fail(key, value, "must not contain negative values")
+			return nil
+		}
+	}
+
+	sort.SliceStable(rates, func(i, j int) bool {
+		if rates[i].MaxGrams == 0 || rates[j].MaxGrams == 0 {
+			return rates[j].MaxGrams == 0 && rates[i].MaxGrams != 0
+		}
+		return rates[i].MaxGrams < rates[j].MaxGrams
+	})
+	return rates
+}
+
+// Events selects where order events are published
+type Events struct {
+	Publisher    string   // EVENT_PUBLISHER: none, stdout or kafka
+	KafkaBrokers []string // KAFKA_BROKERS, comma-separated host:port
+	KafkaTopic   string   // KAFKA_TOPIC
+	BufferSize   int      // EVENT_BUFFER_SIZE, events queued before dropping
+}
+
+func (l *loader) events() Events {
+	cfg := Events{
+		Publisher:    l.oneOf("EVENT_PUBLISHER", "none", "none", "stdout", "kafka"),
+		KafkaBrokers: l.list("KAFKA_BROKERS"),
+		KafkaTopic:   l.string("KAFKA_TOPIC", "order-events"),
+		BufferSize:   l.int("EVENT_BUFFER_SIZE", 1000, 1),
+	}
+	if cfg.Publisher == "kafka" && len(cfg.KafkaBrokers) == 0 {
+		l.fail("KAFKA_BROKERS", "", "is required when EVENT_PUBLISHER is kafka")
+	}
+	return cfg
+}
+
+func (l *loader) database(defaultName string) Database {
+	return Database{
+		Driver:     l.oneOf("DB_DRIVER", "postgres", "postgres", "sqlite"),
+		Host:       l.string("DB_HOST", "localhost"),
+		Port:       l.port("DB_PORT", 5432),
+		User:       l.string("DB_USER", "postgres"),
+		Password:   l.string("DB_PASSWORD", "password"),
+		Name:       l.string("DB_NAME", defaultName),
+		SSLMode:    l.oneOf("DB_SSLMODE", "disable", "disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
+		SQLitePath: l.string("DB_SQLITE_PATH", defaultName+".db"),
+
+		ConnectAttempts:   l.int("DB_CONNECT_ATTEMPTS", 10, 1),
+		ConnectRetryDelay: l.duration("DB_CONNECT_RETRY_DELAY", 2*time.Second),
+
+		MaxOpenConns:    l.int("DB_MAX_OPEN_CONNS", 25, 1),
+		MaxIdleConns:    l.int("DB_MAX_IDLE_CONNS", 5, 0),
+		ConnMaxLifetime: l.duration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+	}
+}
+
+// errInvalid wraps every error Load reports
+var errInvalid = errors.New("invalid configuration")
+
+// fail records that key has a malformed value
+func (l *loader) fail(key, value, reason string) {
+	l.errs = append(l.errs, fmt.Errorf("%w: %s=%q %s", errInvalid, key, value, reason))
+}
+
+// err joins everything recorded by fail, or returns nil
+func (l *loader) err() error {
+	return errors.Join(l.errs...)
+}
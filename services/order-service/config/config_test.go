@@ -0,0 +1,161 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Port != 8082 || cfg.UserServiceURL != "http://localhost:8080" || cfg.ProductServiceURL != "http://localhost:8081" {
+		t.Errorf("port = %d, URLs = %s and %s, want 8082 and the local services", cfg.Port, cfg.UserServiceURL, cfg.ProductServiceURL)
+	}
+	if cfg.HTTPClientTimeout != 5*time.Second || cfg.MaxRetries != 3 || cfg.BreakerThreshold != 5 {
+		t.Errorf("timeout = %s, retries = %d, breaker threshold = %d, want 5s, 3 and 5", cfg.HTTPClientTimeout, cfg.MaxRetries, cfg.BreakerThreshold)
+	}
+	if cfg.ShippingRates != nil {
+		t.Errorf("shipping rates = %v, want nil for the built-in table", cfg.ShippingRates)
+	}
+	if cfg.TimestampMaxFuture != 24*time.Hour {
+		t.Errorf("timestamp max future = %s, want 24h", cfg.TimestampMaxFuture)
+	}
+	if cfg.PIIFields != nil {
+		t.Errorf("PII fields = %q, want none", cfg.PIIFields)
+	}
+}
+
+func TestLoadReportsEveryMalformedValue(t *testing.T) {
+	t.Setenv("PORT", "eighty")
+	t.Setenv("USER_SERVICE_URL", "users.internal")
+	t.Setenv("HTTP_CLIENT_TIMEOUT", "soon")
+	t.Setenv("DOWNSTREAM_MAX_RETRIES", "-1")
+
+	_, err := Load()
+	if !errors.Is(err, errInvalid) {
+		t.Fatalf("err = %v, want an invalid configuration", err)
+	}
+	for _, key := range []string{"PORT", "USER_SERVICE_URL", "HTTP_CLIENT_TIMEOUT", "DOWNSTREAM_MAX_RETRIES"} {
+		if !strings.Contains(err.Error(), key+"=") {
+			t.Errorf("error %q does not name %s", err, key)
+		}
+	}
+}
+
+func TestLoadRequireHTTPSDownstream(t *testing.T) {
+	t.Setenv("REQUIRE_HTTPS_DOWNSTREAM", "true")
+	t.Setenv("USER_SERVICE_URL", "https://users.internal")
+	t.Setenv("PRODUCT_SERVICE_URL", "https://products.internal")
+	if _, err := Load(); err != nil {
+		t.Fatalf("https URLs: %v", err)
+	}
+
+	t.Setenv("PRODUCT_SERVICE_URL", "http://products.internal")
+	if _, err := Load(); !errors.Is(err, errInvalid) {
+		t.Errorf("http URL: err = %v, want an invalid configuration", err)
+	}
+
+	t.Setenv("PRODUCT_SERVICE_URL", "https://products.internal")
+	t.Setenv("PRODUCT_TRANSPORT", "grpc")
+	if _, err := Load(); !errors.Is(err, errInvalid) {
+		t.Errorf("gRPC transport: err = %v, want an invalid configuration", err)
+	}
+
+	t.Setenv("REQUIRE_HTTPS_DOWNSTREAM", "false")
+	t.Setenv("PRODUCT_SERVICE_URL", "http://products.internal")
+	if _, err := Load(); err != nil {
+		t.Errorf("not required: %v", err)
+	}
+}
+
+func TestLoadDatabaseDriver(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Database.Driver != "postgres" || !strings.HasPrefix(cfg.Database.DSN(), "host=localhost ") {
+		t.Errorf("driver = %q, DSN = %q, want postgres on localhost", cfg.Database.Driver, cfg.Database.DSN())
+	}
+
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_SQLITE_PATH", ":memory:")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load with sqlite: %v", err)
+	}
+	if cfg.Database.DSN() != ":memory:" {
+		t.Errorf("DSN = %q, want the SQLite path", cfg.Database.DSN())
+	}
+
+	t.Setenv("DB_DRIVER", "mysql")
+	if _, err := Load(); !errors.Is(err, errInvalid) {
+		t.Errorf("unknown driver: err = %v, want an invalid configuration", err)
+	}
+}
+
+func TestLoadDatabaseConnectRetry(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Database.ConnectAttempts != 10 || cfg.Database.ConnectRetryDelay != 2*time.Second {
+		t.Errorf("attempts = %d, delay = %s, want 10 and 2s", cfg.Database.ConnectAttempts, cfg.Database.ConnectRetryDelay)
+	}
+	if d := cfg.Database; d.MaxOpenConns != 25 || d.MaxIdleConns != 5 || d.ConnMaxLifetime != 30*time.Minute {
+		t.Errorf("pool = %d open, %d idle, %s lifetime, want 25, 5 and 30m", d.MaxOpenConns, d.MaxIdleConns, d.ConnMaxLifetime)
+	}
+
+	t.Setenv("DB_CONNECT_ATTEMPTS", "0")
+	if _, err := Load(); !errors.Is(err, errInvalid) {
+		t.Errorf("zero attempts: err = %v, want an invalid configuration", err)
+	}
+	t.Setenv("DB_CONNECT_ATTEMPTS", "10")
+
+	t.Setenv("DB_MAX_OPEN_CONNS", "0")
+	if _, err := Load(); !errors.Is(err, errInvalid) {
+		t.Errorf("zero open connections: err = %v, want an invalid configuration", err)
+	}
+}
+
+func TestLoadFeatureSettings(t *testing.T) {
+	t.Setenv("SHIPPING_RATE_TABLE", `[{"max_grams":0,"base":20},{"max_grams":500,"base":3,"per_kg":1}]`)
+	t.Setenv("TIMESTAMP_MAX_FUTURE", "1h")
+	t.Setenv("PII_FIELDS", "phone, address,")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	wantRates := []ShippingRate{{MaxGrams: 500, Base: 3, PerKg: 1}, {MaxGrams: 0, Base: 20}}
+	if !reflect.DeepEqual(cfg.ShippingRates, wantRates) {
+		t.Errorf("shipping rates = %v, want %v with the unbounded tier last", cfg.ShippingRates, wantRates)
+	}
+	if cfg.TimestampMaxFuture != time.Hour {
+		t.Errorf("timestamp max future = %s, want 1h", cfg.TimestampMaxFuture)
+	}
+	if want := []string{"phone", "address"}; !reflect.DeepEqual(cfg.PIIFields, want) {
+		t.Errorf("PII fields = %q, want %q", cfg.PIIFields, want)
+	}
+}
+
+func TestLoadRejectsMalformedFeatureSettings(t *testing.T) {
+	tests := []struct{ key, value string }{
+		{"SHIPPING_RATE_TABLE", "cheap"},
+		{"SHIPPING_RATE_TABLE", "[]"},
+		{"SHIPPING_RATE_TABLE", `[{"max_grams":500,"base":-1}]`},
+		{"TIMESTAMP_MAX_FUTURE", "tomorrow"},
+		{"TIMESTAMP_MAX_FUTURE", "-1h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			if _, err := Load(); !errors.Is(err, errInvalid) {
+				t.Errorf("err = %v, want an invalid configuration", err)
+			}
+		})
+	}
+}
@@ -1,13 +1,14 @@
 package database
 
 import (
-	"fmt"
 	"log"
-	"os"
+	"time"
 
+	"order-service/config"
 	"order-service/models"
 
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -15,31 +16,85 @@ import (
 // DB is the global database instance
 var DB *gorm.DB
 
-// ConnectDB establishes connection to PostgreSQL database
-func ConnectDB() {
+// gormLogger reports only errors and slow queries, through the standard log
+// package so they come out as JSON like every other line. Queries are logged
+// with placeholders instead of their values, which can be personal data.
+var gormLogger = logger.New(log.Default(), logger.Config{
+	SlowThreshold:             200 * time.Millisecond,
+	LogLevel:                  logger.Warn,
+	IgnoreRecordNotFoundError: true,
+	ParameterizedQueries:      true,
+})
+
+// ConnectDB opens the database selected by cfg.Driver: PostgreSQL, or SQLite
+// for local development without a database server. A database that isn't
+// accepting connections yet, as when it starts alongside the service, is
+// retried up to cfg.ConnectAttempts times before the service gives up.
+func ConnectDB(cfg config.Database) {
 	var err error
+	for attempt := 1; ; attempt++ {
+		DB, err = open(cfg)
+		if err == nil {
+			break
+		}
+		if attempt >= cfg.ConnectAttempts {
+			log.Fatalf("Failed to connect to database after %d attempts: %v", attempt, err)
+		}
+		log.Printf("Database not ready (attempt %d/%d), retrying in %s: %v", attempt, cfg.ConnectAttempts, cfg.ConnectRetryDelay, err)
+		time.Sleep(cfg.ConnectRetryDelay)
+	}
+
+	log.Printf("Database connected successfully (%s)", cfg.Driver)
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		log.Fatal("Failed to configure database:", err)
+	}
+	// database/sql caps idle connections at the open limit, which SQLite
+	// lowers to one
+	maxOpen := sqlDB.Stats().MaxOpenConnections
+	log.Printf("Database pool: max open %d, max idle %d, max lifetime %s",
+		maxOpen, min(cfg.MaxIdleConns, maxOpen), cfg.ConnMaxLifetime)
+}
+
+// open connects once, sizes the connection pool and pings the database to
+// confirm the connection is usable
+func open(cfg config.Database) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case "sqlite":
+		dialector = sqlite.Open(cfg.DSN())
+	default:
+		dialector = postgres.Open(cfg.DSN())
+	}
 
-	// Get database configuration from environment variables
-	host := getEnv("DB_HOST", "localhost")
-	port := getEnv("DB_PORT", "5432")
-	user := getEnv("DB_USER", "postgres")
-	password := getEnv("DB_PASSWORD", "password")
-	dbname := getEnv("DB_NAME", "order_service")
-	sslmode := getEnv("DB_SSLMODE", "disable")
-
-	// Create DSN (Data Source Name)
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		host, port, user, password, dbname, sslmode)
-
-	// Connect to database
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	// TranslateError maps each driver's constraint errors to GORM's, such as
+	// gorm.ErrDuplicatedKey, so services don't depend on the dialect
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger:         gormLogger,
+		TranslateError: true,
 	})
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return nil, err
 	}
 
-	log.Println("Database connected successfully")
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	if cfg.Driver == "sqlite" {
+		// Every connection to :memory: opens a separate empty database, and
+		// SQLite serializes writes anyway, so use a single connection
+		sqlDB.SetMaxOpenConns(1)
+	}
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
 }
 
 // MigrateDB runs database migrations
@@ -50,11 +105,3 @@ func MigrateDB() {
 	}
 	log.Println("Database migration completed")
 }
-
-// getEnv gets environment variable with fallback to default value
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"bytes"
+	"log"
+	"order-service/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	db, err := open(config.Database{Driver: "sqlite", SQLitePath: ":memory:", MaxOpenConns: 25, MaxIdleConns: 5})
+	if err != nil {
+		t.Fatalf("open :memory: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	if n := sqlDB.Stats().MaxOpenConnections; n != 1 {
+		t.Errorf("SQLite pool allows %d open connections, want 1", n)
+	}
+
+	unreachable := filepath.Join(t.TempDir(), "missing", "orders.db")
+	if _, err := open(config.Database{Driver: "sqlite", SQLitePath: unreachable}); err == nil {
+		t.Error("open of an unreachable database succeeded, want an error to retry on")
+	}
+}
+
+func TestGORMLogHidesValues(t *testing.T) {
+	db, err := open(config.Database{Driver: "sqlite", SQLitePath: ":memory:", MaxOpenConns: 1})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	var email string
+	if err := db.Raw("SELECT ?", "ada@example.com").Scan(&email).Error; err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("a fast query was logged: %s", buf.String())
+	}
+
+	db.Exec("SELECT * FROM missing WHERE email = ?", "ada@example.com")
+	if out := buf.String(); !strings.Contains(out, "missing") || strings.Contains(out, "ada@example.com") {
+		t.Errorf("failed query logged as %q, want the error without its values", out)
+	}
+}
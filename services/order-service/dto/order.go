@@ -2,30 +2,89 @@ package dto
 
 import "time"
 
-// CreateOrderRequest represents the request payload for creating an order
+// CreateOrderRequest represents the request payload for creating an order.
+// Quantity is optional: omitted or null means 1, while an explicit 0 is invalid.
 type CreateOrderRequest struct {
-	UserID    uint `json:"user_id" validate:"required"`
-	ProductID uint `json:"product_id" validate:"required"`
+	UserID    uint  `json:"user_id" validate:"required"`
+	ProductID uint  `json:"product_id" validate:"required"`
+	Quantity  *uint `json:"quantity,omitempty" validate:"omitempty,gt=0"`
+}
+
+// QuantityOrDefault returns the requested quantity, defaulting to 1 when the
+// field was omitted or null
+func (r CreateOrderRequest) QuantityOrDefault() uint {
+	if r.Quantity == nil {
+		return 1
+	}
+	return *r.Quantity
+}
+
+// OrderFilter narrows the orders returned by a listing or search. Nil and
+// zero fields don't filter; From and To are both inclusive.
+type OrderFilter struct {
+	UserID    *uint
+	ProductID *uint
+	Status    string
+	MinTotal  *float64
+	MaxTotal  *float64
+	From      time.Time
+	To        time.Time
+}
+
+// Pagination selects a window of a listing
+type Pagination struct {
+	Limit  int
+	Offset int
+}
+
+// OrderListResponse is a page of orders with the information clients need
+// to render pagination controls
+type OrderListResponse struct {
+	Items  []OrderResponse `json:"items"`
+	Total  int64           `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+// UpdateOrderRequest represents the request payload for changing a pending
+// order. Omitted fields keep their current value.
+type UpdateOrderRequest struct {
+	ProductID *uint `json:"product_id,omitempty" validate:"omitempty,gt=0"`
+	Quantity  *uint `json:"quantity,omitempty" validate:"omitempty,gt=0"`
+}
+
+// UpdateOrderStatusRequest represents the request payload for changing an order's status
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" validate:"required"`
 }
 
 // OrderResponse represents the response payload for order operations
 type OrderResponse struct {
-	ID        uint      `json:"id"`
-	UserID    uint      `json:"user_id"`
-	ProductID uint      `json:"product_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uint      `json:"id"`
+	UserID     uint      `json:"user_id"`
+	ProductID  uint      `json:"product_id"`
+	Quantity   uint      `json:"quantity"`
+	TotalPrice float64   `json:"total_price"`
+	Currency   string    `json:"currency"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 // OrderWithDetailsResponse represents order with full user and product details
 type OrderWithDetailsResponse struct {
-	ID        uint             `json:"id"`
-	UserID    uint             `json:"user_id"`
-	ProductID uint             `json:"product_id"`
-	User      *UserResponse    `json:"user,omitempty"`
-	Product   *ProductResponse `json:"product,omitempty"`
-	CreatedAt time.Time        `json:"created_at"`
-	UpdatedAt time.Time        `json:"updated_at"`
+	ID               uint             `json:"id"`
+	UserID           uint             `json:"user_id"`
+	ProductID        uint             `json:"product_id"`
+	Quantity         uint             `json:"quantity"`
+	TotalPrice       float64          `json:"total_price"`
+	Currency         string           `json:"currency"`
+	Status           string           `json:"status"`
+	User             *UserResponse    `json:"user,omitempty"`
+	Product          *ProductResponse `json:"product,omitempty"`
+	TotalWeightGrams int              `json:"total_weight_grams"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
 }
 
 // UserResponse represents user data from user service
@@ -39,11 +98,107 @@ type UserResponse struct {
 
 // ProductResponse represents product data from product service
 type ProductResponse struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Category    string    `json:"category"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           uint       `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Price        float64    `json:"price"`
+	Currency     string     `json:"currency"`
+	Category     string     `json:"category"`
+	WeightGrams  int        `json:"weight_grams"`
+	DimensionsCM Dimensions `json:"dimensions_cm"`
+	// AvailableFrom and AvailableUntil bound when the product can be ordered;
+	// nil means unbounded on that side
+	AvailableFrom  *time.Time `json:"available_from,omitempty"`
+	AvailableUntil *time.Time `json:"available_until,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	// Stale is set when the product service was unavailable and this is the
+	// last copy the order service fetched successfully
+	Stale bool `json:"stale,omitempty"`
+}
+
+// Dimensions represents the physical size of a product in centimeters
+type Dimensions struct {
+	Length float64 `json:"length"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// ServiceHealth reports the health of a single service
+type ServiceHealth struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SystemHealthResponse aggregates the health of all services
+type SystemHealthResponse struct {
+	Status   string                   `json:"status"`
+	Services map[string]ServiceHealth `json:"services"`
+}
+
+// ReadinessResponse reports whether the service can serve traffic and the
+// status of each dependency it checked
+type ReadinessResponse struct {
+	Status       string                   `json:"status"`
+	Dependencies map[string]ServiceHealth `json:"dependencies"`
+}
+
+// BatchOrderResult reports the outcome of one entry in a batch create
+type BatchOrderResult struct {
+	Index int                       `json:"index"`
+	Order *OrderWithDetailsResponse `json:"order,omitempty"`
+	Error string                    `json:"error,omitempty"`
+}
+
+// ThroughputPoint is the number of orders created in one bucket
+type ThroughputPoint struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count"`
+}
+
+// ThroughputResponse is a continuous time series of order counts
+type ThroughputResponse struct {
+	Bucket string            `json:"bucket"`
+	From   time.Time         `json:"from"`
+	To     time.Time         `json:"to"`
+	Points []ThroughputPoint `json:"points"`
+}
+
+// ShippingEstimateResponse is the cost breakdown for shipping an order
+type ShippingEstimateResponse struct {
+	OrderID                uint    `json:"order_id"`
+	Destination            string  `json:"destination"`
+	WeightGrams            int     `json:"weight_grams"`
+	DimensionalWeightGrams int     `json:"dimensional_weight_grams"`
+	BillableWeightGrams    int     `json:"billable_weight_grams"`
+	Base                   float64 `json:"base"`
+	PerKg                  float64 `json:"per_kg"`
+	WeightCost             float64 `json:"weight_cost"`
+	Total                  float64 `json:"total"`
+}
+
+// LifetimeValueResponse summarizes what a user has spent over their delivered
+// orders
+type LifetimeValueResponse struct {
+	UserID            uint       `json:"user_id"`
+	LifetimeValue     float64    `json:"lifetime_value"`
+	CompletedOrders   int64      `json:"completed_orders"`
+	AverageOrderValue float64    `json:"average_order_value"`
+	OrdersPerMonth    float64    `json:"orders_per_month"`
+	FirstOrderAt      *time.Time `json:"first_order_at,omitempty"`
+	LastOrderAt       *time.Time `json:"last_order_at,omitempty"`
+}
+
+// SalesSummaryResponse aggregates the non-cancelled orders in a time range
+type SalesSummaryResponse struct {
+	OrderCount   int64          `json:"order_count"`
+	TotalRevenue float64        `json:"total_revenue"`
+	Products     []ProductSales `json:"products"`
+}
+
+// ProductSales is one product's share of a sales summary
+type ProductSales struct {
+	ProductID uint    `json:"product_id"`
+	UnitsSold int64   `json:"units_sold"`
+	Revenue   float64 `json:"revenue"`
 }
@@ -0,0 +1,116 @@
+// Package events publishes domain events raised by the order service so
+// other systems can react to them, e.g. to send a confirmation email when
+// an order is created. OrderService only sees the Publisher interface; the
+// implementation is chosen in main.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"order-service/config"
+	"os"
+	"sync"
+	"time"
+)
+
+// metrics counts published, failed and dropped events; expvar serves it at
+// /debug/vars
+var metrics = expvar.NewMap("events")
+
+// OrderCreated is raised once an order has been stored
+const OrderCreated = "order.created"
+
+// Event is the envelope every published event is written in
+type Event struct {
+	Name       string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+	// Key groups related events, such as those of one order, so brokers
+	// that partition by key deliver them in order
+	Key string `json:"-"`
+}
+
+// OrderCreatedData is the payload of OrderCreated
+type OrderCreatedData struct {
+	OrderID   uint      `json:"order_id"`
+	UserID    uint      `json:"user_id"`
+	ProductID uint      `json:"product_id"`
+	Quantity  uint      `json:"quantity"`
+	Total     float64   `json:"total"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Publisher delivers events to their consumers. Implementations must be safe
+// for concurrent use; errors are logged by the caller, never returned to
+// clients, since the order has already been stored.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Closer is implemented by publishers that buffer events and must flush
+// them before the process exits
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// Close flushes p if it buffers events, waiting at most until ctx is done
+func Close(ctx context.Context, p Publisher) error {
+	if c, ok := p.(Closer); ok {
+		return c.Close(ctx)
+	}
+	return nil
+}
+
+// NewPublisher returns the publisher selected by EVENT_PUBLISHER: "none"
+// discards events, "stdout" writes them as JSON lines and "kafka" sends them
+// to KAFKA_TOPIC on KAFKA_BROKERS
+func NewPublisher(cfg config.Events) (Publisher, error) {
+	switch cfg.Publisher {
+	case "", "none":
+		return NopPublisher{}, nil
+	case "stdout":
+		return NewJSONPublisher(os.Stdout), nil
+	case "kafka":
+		return NewKafkaPublisher(cfg.KafkaBrokers, cfg.KafkaTopic, cfg.BufferSize), nil
+	default:
+		return nil, fmt.Errorf("unknown event publisher %q", cfg.Publisher)
+	}
+}
+
+// NopPublisher discards every event
+type NopPublisher struct{}
+
+// Publish does nothing
+func (NopPublisher) Publish(ctx context.Context, event Event) error { return nil }
+
+// JSONPublisher writes each event to w as a single line of JSON
+type JSONPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONPublisher creates a publisher writing to w
+func NewJSONPublisher(w io.Writer) *JSONPublisher {
+	return &JSONPublisher{w: w}
+}
+
+// Publish writes event as one JSON line; lines from concurrent calls never
+// interleave
+func (p *JSONPublisher) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.w.Write(append(body, '\n')); err != nil {
+		metrics.Add("failed", 1)
+		return err
+	}
+	metrics.Add("published", 1)
+	return nil
+}
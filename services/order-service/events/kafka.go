@@ -0,0 +1,139 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ErrQueueFull is returned when the Kafka publisher's buffer is full and the
+// event has been dropped
+var ErrQueueFull = errors.New("event queue full")
+
+// ErrPublisherClosed is returned for events published after Close
+var ErrPublisherClosed = errors.New("event publisher closed")
+
+// maxKafkaBatch caps how many queued events are sent in one write
+const maxKafkaBatch = 100
+
+// KafkaPublisher sends events to a Kafka topic without blocking the caller:
+// Publish only enqueues, and a background worker drains the queue to the
+// brokers. Close flushes what is still queued.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+	queue  chan Event
+	done   chan struct{}
+
+	// mu guards closed so Publish never sends on a closed queue
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewKafkaPublisher starts a publisher writing to topic on brokers, buffering
+// up to bufferSize events
+func NewKafkaPublisher(brokers []string, topic string, bufferSize int) *KafkaPublisher {
+	p := &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{}, // events with the same key keep their order
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond,
+			WriteTimeout: 10 * time.Second,
+		},
+		queue: make(chan Event, bufferSize),
+		done:  make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Publish queues event for delivery. When the queue is full the event is
+// dropped and ErrQueueFull returned rather than making the caller wait.
+func (p *KafkaPublisher) Publish(ctx context.Context, event Event) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		metrics.Add("dropped", 1)
+		return ErrPublisherClosed
+	}
+	select {
+	case p.queue <- event:
+		return nil
+	default:
+		metrics.Add("dropped", 1)
+		return ErrQueueFull
+	}
+}
+
+// run sends queued events until the queue is closed and drained, batching
+// whatever has accumulated while the previous write was in flight
+func (p *KafkaPublisher) run() {
+	defer close(p.done)
+	batch := make([]Event, 0, maxKafkaBatch)
+	for event := range p.queue {
+		batch = append(batch[:0], event)
+	fill:
+		for len(batch) < maxKafkaBatch {
+			select {
+			case next, ok := <-p.queue:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, next)
+			default:
+				break fill
+			}
+		}
+		p.write(batch)
+	}
+}
+
+// write sends one batch, logging and counting the outcome. Failed events are
+// not retried beyond the writer's own attempts.
+func (p *KafkaPublisher) write(batch []Event) {
+	msgs := make([]kafka.Message, 0, len(batch))
+	for _, event := range batch {
+		value, err := json.Marshal(event)
+		if err != nil {
+			metrics.Add("failed", 1)
+			slog.Error("failed to encode event", "event", event.Name, "error", err)
+			continue
+		}
+		msgs = append(msgs, kafka.Message{Key: []byte(event.Key), Value: value, Time: event.OccurredAt})
+	}
+	if len(msgs) == 0 {
+		return
+	}
+
+	if err := p.writer.WriteMessages(context.Background(), msgs...); err != nil {
+		metrics.Add("failed", int64(len(msgs)))
+		slog.Error("failed to publish events", "count", len(msgs), "topic", p.writer.Topic, "error", err)
+		return
+	}
+	metrics.Add("published", int64(len(msgs)))
+}
+
+// Close stops accepting events and waits for the queued ones to be sent, up
+// to ctx's deadline, before closing the connection to the brokers
+func (p *KafkaPublisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		slog.Warn("event queue not drained before shutdown", "pending", len(p.queue))
+		return errors.Join(ctx.Err(), p.writer.Close())
+	}
+	return p.writer.Close()
+}
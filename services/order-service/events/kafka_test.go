@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// counter returns the current value of one of the event metrics
+func counter(name string) int64 {
+	if v, ok := metrics.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestKafkaPublisherQueue(t *testing.T) {
+	// No worker drains the queue, so its capacity is all there is
+	p := &KafkaPublisher{
+		writer: &kafka.Writer{Addr: kafka.TCP("127.0.0.1:1"), Topic: "orders"},
+		queue:  make(chan Event, 1),
+		done:   make(chan struct{}),
+	}
+	ctx := context.Background()
+	dropped := counter("dropped")
+
+	if err := p.Publish(ctx, Event{Name: OrderCreated}); err != nil {
+		t.Fatalf("first Publish: %v", err)
+	}
+	if err := p.Publish(ctx, Event{Name: OrderCreated}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Publish to a full queue: err = %v, want ErrQueueFull", err)
+	}
+
+	// The queued event can't be sent, so Close gives up at the deadline
+	closeCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := p.Close(closeCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close: err = %v, want the deadline", err)
+	}
+
+	if err := p.Publish(ctx, Event{Name: OrderCreated}); !errors.Is(err, ErrPublisherClosed) {
+		t.Errorf("Publish after Close: err = %v, want ErrPublisherClosed", err)
+	}
+	if got := counter("dropped") - dropped; got != 2 {
+		t.Errorf("%d events counted as dropped, want 2", got)
+	}
+}
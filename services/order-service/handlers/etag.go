@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"order-service/apperror"
+	"strings"
+)
+
+// writeJSONWithETag writes v as JSON with an ETag hashed from its encoding,
+// so the tag changes whenever anything in the response does, UpdatedAt
+// included. A request whose If-None-Match already names the tag gets 304 Not
+// Modified with no body.
+func writeJSONWithETag(w http.ResponseWriter, r *http.Request, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
+
+// etagMatches reports whether an If-None-Match header names etag, using the
+// weak comparison RFC 9110 prescribes for it
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"net/http"
+	"order-service/apperror"
+	"order-service/dto"
+	"order-service/openapi"
+)
+
+// errorResponses documents error statuses, all answered with the JSON error
+// body written by apperror.WriteError
+func errorResponses(statuses ...int) []openapi.Response {
+	responses := make([]openapi.Response, len(statuses))
+	for i, status := range statuses {
+		responses[i] = openapi.Response{Status: status, Body: apperror.ErrorResponse{}}
+	}
+	return responses
+}
+
+// okResponses documents a 200 response with body
+func okResponses(body interface{}, errors ...int) []openapi.Response {
+	return append([]openapi.Response{{Status: http.StatusOK, Body: body}}, errorResponses(errors...)...)
+}
+
+var (
+	idParam        = openapi.Param{Name: "id", Type: "integer", Required: true, Description: "Order ID"}
+	pathID         = openapi.Param{Name: "id", In: "path", Type: "integer", Description: "Order ID"}
+	tzParam        = openapi.Query("tz", "string", "IANA zone to render timestamps in, e.g. America/New_York")
+	limitParam     = openapi.Query("limit", "integer", "Page size, default 20, max 100")
+	offsetParam    = openapi.Query("offset", "integer", "Number of orders to skip")
+	ifNoneMatch    = openapi.Param{Name: "If-None-Match", In: "header", Type: "string", Description: "ETag of a cached copy; answered with 304 if unchanged"}
+	idempotencyKey = openapi.Param{Name: "Idempotency-Key", In: "header", Type: "string", Description: "Required when REQUIRE_IDEMPOTENCY_KEY is enabled; repeated keys are not deduplicated"}
+)
+
+// Operations describes every route the order service registers. Add new
+// routes here alongside their registration in main so GET /openapi.json
+// stays complete.
+func Operations() []openapi.Operation {
+	userIDParam := openapi.Param{Name: "user_id", Type: "integer", Required: true, Description: "User ID"}
+
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/orders",
+			Summary: "Get an order with user and product details by ?id=, or a page of orders",
+			Params: []openapi.Param{
+				openapi.Query("id", "integer", "Order ID; omit to list orders"),
+				openapi.Query("user_id", "integer", "Only orders of this user"),
+				openapi.Query("product_id", "integer", "Only orders of this product"),
+				openapi.Query("from", "string", "RFC 3339, inclusive"),
+				openapi.Query("to", "string", "RFC 3339, inclusive"),
+				limitParam, offsetParam, tzParam, ifNoneMatch,
+			},
+			Responses: append(okResponses(openapi.OneOf(dto.OrderWithDetailsResponse{}, dto.OrderListResponse{}), 400, 404, 502),
+				openapi.Response{Status: http.StatusNotModified}),
+		},
+		{
+			Method: http.MethodPost, Path: "/orders", Auth: true,
+			Summary: "Create an order",
+			Params:  []openapi.Param{idempotencyKey},
+			Body:    dto.CreateOrderRequest{},
+			Responses: append([]openapi.Response{{Status: http.StatusCreated, Body: dto.OrderWithDetailsResponse{}}},
+				errorResponses(400, 401, 403, 409, 413, 502)...),
+		},
+		{
+			Method: http.MethodPut, Path: "/orders", Auth: true,
+			Summary:   "Change the product or quantity of a pending order",
+			Params:    []openapi.Param{idParam},
+			Body:      dto.UpdateOrderRequest{},
+			Responses: okResponses(dto.OrderResponse{}, 400, 401, 403, 404, 409, 413, 502),
+		},
+		{
+			Method: http.MethodDelete, Path: "/orders", Auth: true,
+			Summary: "Delete an order",
+			Params:  []openapi.Param{idParam},
+			Responses: append([]openapi.Response{{Status: http.StatusNoContent}},
+				errorResponses(400, 401, 403, 404)...),
+		},
+		{
+			Method: http.MethodGet, Path: "/orders/{id}",
+			Summary:   "Get an order with user and product details",
+			Params:    []openapi.Param{pathID, tzParam, ifNoneMatch},
+			Responses: append(okResponses(dto.OrderWithDetailsResponse{}, 400, 404, 502), openapi.Response{Status: http.StatusNotModified}),
+		},
+		{
+			Method: http.MethodPut, Path: "/orders/{id}", Auth: true,
+			Summary:   "Change the product or quantity of a pending order",
+			Params:    []openapi.Param{pathID},
+			Body:      dto.UpdateOrderRequest{},
+			Responses: okResponses(dto.OrderResponse{}, 400, 401, 403, 404, 409, 413, 502),
+		},
+		{
+			Method: http.MethodDelete, Path: "/orders/{id}", Auth: true,
+			Summary: "Delete an order",
+			Params:  []openapi.Param{pathID},
+			Responses: append([]openapi.Response{{Status: http.StatusNoContent}},
+				errorResponses(400, 401, 403, 404)...),
+		},
+		{
+			Method: http.MethodGet, Path: "/orders/search",
+			Summary: "Search orders by any combination of criteria",
+			Params: []openapi.Param{
+				openapi.Query("user_id", "integer", ""),
+				openapi.Query("product_id", "integer", ""),
+				openapi.Query("status", "string", "pending, paid, shipped, delivered or cancelled"),
+				openapi.Query("min_total", "number", ""),
+				openapi.Query("max_total", "number", ""),
+				openapi.Query("from", "string", "RFC 3339, inclusive"),
+				openapi.Query("to", "string", "RFC 3339, inclusive"),
+				openapi.Query("sort", "string", "id, created_at or total_price, prefixed with - for descending"),
+				limitParam, offsetParam, tzParam,
+			},
+			Responses: okResponses(dto.OrderListResponse{}, 400),
+		},
+		{
+			Method: http.MethodGet, Path: "/orders/by-user",
+			Summary:   "All of a user's orders with user and product details",
+			Params:    []openapi.Param{userIDParam, tzParam},
+			Responses: okResponses([]dto.OrderWithDetailsResponse{}, 400, 502),
+		},
+		{
+			Method: http.MethodGet, Path: "/orders/ltv",
+			Summary:   "A user's lifetime value over delivered orders",
+			Params:    []openapi.Param{userIDParam, tzParam},
+			Responses: okResponses(dto.LifetimeValueResponse{}, 400, 502),
+		},
+		{
+			Method: http.MethodGet, Path: "/orders/summary",
+			Summary: "Order count, revenue and units sold per product, excluding cancelled orders",
+			Params: []openapi.Param{
+				openapi.Query("from", "string", "RFC 3339, inclusive"),
+				openapi.Query("to", "string", "RFC 3339, inclusive"),
+			},
+			Responses: okResponses(dto.SalesSummaryResponse{}, 400),
+		},
+		{
+			Method: http.MethodPatch, Path: "/orders/status", Auth: true,
+			Summary:   "Change an order's status",
+			Params:    []openapi.Param{idParam},
+			Body:      dto.UpdateOrderStatusRequest{},
+			Responses: okResponses(dto.OrderResponse{}, 400, 401, 404, 409, 413),
+		},
+		{
+			Method: http.MethodPost, Path: "/orders/batch", Auth: true,
+			Summary: "Create up to 100 orders atomically",
+			Params:  []openapi.Param{idempotencyKey},
+			Body:    []dto.CreateOrderRequest{},
+			Responses: append([]openapi.Response{
+				{Status: http.StatusCreated, Body: []dto.BatchOrderResult{}},
+				{Status: http.StatusBadRequest, Description: "Per-index errors for the invalid entries", Body: []dto.BatchOrderResult{}},
+			}, errorResponses(401, 403, 413, 502)...),
+		},
+		{
+			Method: http.MethodGet, Path: "/orders/{id}/shipping-estimate",
+			Summary: "Estimate the cost of shipping an order",
+			Params: []openapi.Param{
+				pathID,
+				{Name: "destination", Type: "string", Required: true, Description: "5-digit ZIP or ZIP+4"},
+			},
+			Responses: okResponses(dto.ShippingEstimateResponse{}, 400, 404, 502),
+		},
+		{
+			Method: http.MethodGet, Path: "/orders/throughput",
+			Summary: "Order counts per bucket over a time range",
+			Params: []openapi.Param{
+				openapi.Query("bucket", "string", "1h or 1d"),
+				openapi.Query("from", "string", "RFC 3339"),
+				openapi.Query("to", "string", "RFC 3339"),
+			},
+			Responses: okResponses(dto.ThroughputResponse{}, 400),
+		},
+		{
+			Method: http.MethodGet, Path: "/health",
+			Summary:   "Liveness check with downstream circuit breaker states",
+			Responses: okResponses(map[string]interface{}{}),
+		},
+		{
+			Method: http.MethodGet, Path: "/health/ready",
+			Summary: "Readiness of the user service, product service and database",
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ReadinessResponse{}},
+				{Status: http.StatusServiceUnavailable, Body: dto.ReadinessResponse{}},
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/system/health",
+			Summary:   "Aggregated health of the order, user and product services",
+			Responses: okResponses(dto.SystemHealthResponse{}),
+		},
+		{
+			Method: http.MethodGet, Path: "/openapi.json",
+			Summary:   "This document",
+			Responses: []openapi.Response{{Status: http.StatusOK, Description: "OpenAPI 3.0 document"}},
+		},
+	}
+}
@@ -2,20 +2,30 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
+	"order-service/apperror"
 	"order-service/dto"
+	"order-service/logging"
+	"order-service/middleware"
 	"order-service/services"
 	"strconv"
+	"time"
 )
 
 // OrderHandler handles HTTP requests for order operations
 type OrderHandler struct {
 	orderService *services.OrderService
+
+	// timestampMaxFuture is how far ahead of now an input timestamp may be
+	timestampMaxFuture time.Duration
 }
 
-// NewOrderHandler creates a new order handler
-func NewOrderHandler(orderService *services.OrderService) *OrderHandler {
-	return &OrderHandler{orderService: orderService}
+// NewOrderHandler creates a new order handler that rejects input timestamps
+// more than timestampMaxFuture ahead of now
+func NewOrderHandler(orderService *services.OrderService, timestampMaxFuture time.Duration) *OrderHandler {
+	return &OrderHandler{orderService: orderService, timestampMaxFuture: timestampMaxFuture}
 }
 
 // CreateOrder handles POST /orders
@@ -26,19 +36,31 @@ func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.CreateOrderRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	// An authenticated caller orders for themselves and no one else
+	if err := ownOrder(r, &req); err != nil {
+		apperror.WriteError(w, err)
 		return
 	}
 
 	if req.UserID <= 0 || req.ProductID <= 0 {
-		http.Error(w, "Valid user_id and product_id are required", http.StatusBadRequest)
+		apperror.WriteError(w, apperror.Validation("Valid user_id and product_id are required"))
+		return
+	}
+
+	if req.Quantity != nil && *req.Quantity == 0 {
+		apperror.WriteError(w, apperror.Validation("Quantity must be at least 1"))
 		return
 	}
 
-	order, err := h.orderService.CreateOrder(req)
+	order, err := h.orderService.CreateOrder(r.Context(), req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		logging.PrintfContext(r.Context(), "Failed to create order for user %d, product %d: %v", req.UserID, req.ProductID, err)
+		apperror.WriteError(w, err)
 		return
 	}
 
@@ -47,22 +69,49 @@ func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(order)
 }
 
-// GetOrder handles GET /orders
+// GetOrder handles GET /orders and GET /orders/{id}
 func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	orderIDStr := r.URL.Query().Get("id")
+	loc, err := parseTimezone(r)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid timezone"))
+		return
+	}
+
+	orderIDStr := resourceID(r)
 	if orderIDStr == "" {
-		// Return all orders
-		orders, err := h.orderService.GetAllOrders()
+		// Return all orders, optionally filtered by user, product and creation time
+		var filter dto.OrderFilter
+		if filter.UserID, err = parseOptionalID(r, "user_id"); err != nil {
+			apperror.WriteError(w, apperror.Validation("Invalid user_id"))
+			return
+		}
+		if filter.ProductID, err = parseOptionalID(r, "product_id"); err != nil {
+			apperror.WriteError(w, apperror.Validation("Invalid product_id"))
+			return
+		}
+		if filter.From, filter.To, err = parseCreatedRange(r, h.timestampMaxFuture); err != nil {
+			apperror.WriteError(w, err)
+			return
+		}
+
+		page, err := parsePagination(r)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperror.WriteError(w, err)
 			return
 		}
 
+		orders, err := h.orderService.GetAllOrders(r.Context(), filter, page)
+		if err != nil {
+			apperror.WriteError(w, err)
+			return
+		}
+		localizeOrders(orders.Items, loc)
+
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(orders)
 		return
@@ -70,26 +119,546 @@ func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
 
 	orderID, err := strconv.ParseUint(orderIDStr, 10, 32)
 	if err != nil {
-		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		apperror.WriteError(w, apperror.Validation("Invalid order ID"))
+		return
+	}
+
+	order, err := h.orderService.GetOrder(r.Context(), uint(orderID))
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+	localizeOrderWithDetails(order, loc)
+
+	writeJSONWithETag(w, r, order)
+}
+
+// SearchOrders handles GET /orders/search
+func (h *OrderHandler) SearchOrders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	loc, err := parseTimezone(r)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid timezone"))
+		return
+	}
+
+	query := r.URL.Query()
+	filter := dto.OrderFilter{Status: query.Get("status")}
+	if filter.UserID, err = parseOptionalID(r, "user_id"); err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid user_id"))
+		return
+	}
+	if filter.ProductID, err = parseOptionalID(r, "product_id"); err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid product_id"))
+		return
+	}
+	if filter.MinTotal, err = parseOptionalAmount(r, "min_total"); err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid min_total, expected a non-negative number"))
+		return
+	}
+	if filter.MaxTotal, err = parseOptionalAmount(r, "max_total"); err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid max_total, expected a non-negative number"))
+		return
+	}
+	if filter.From, filter.To, err = parseCreatedRange(r, h.timestampMaxFuture); err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	page, err := parsePagination(r)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	orders, err := h.orderService.SearchOrders(r.Context(), filter, query.Get("sort"), page)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+	localizeOrders(orders.Items, loc)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// GetOrdersByUser handles GET /orders/by-user
+func (h *OrderHandler) GetOrdersByUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	loc, err := parseTimezone(r)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid timezone"))
+		return
+	}
+
+	userID, err := parseOptionalID(r, "user_id")
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid user_id"))
+		return
+	}
+	if userID == nil {
+		apperror.WriteError(w, apperror.Validation("user_id is required"))
+		return
+	}
+
+	orders, err := h.orderService.GetOrdersByUser(r.Context(), *userID)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+	for i := range orders {
+		localizeOrderWithDetails(&orders[i], loc)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// GetLifetimeValue handles GET /orders/ltv
+func (h *OrderHandler) GetLifetimeValue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	loc, err := parseTimezone(r)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid timezone"))
+		return
+	}
+
+	userID, err := parseOptionalID(r, "user_id")
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid user_id"))
+		return
+	}
+	if userID == nil {
+		apperror.WriteError(w, apperror.Validation("user_id is required"))
+		return
+	}
+
+	ltv, err := h.orderService.GetLifetimeValue(r.Context(), *userID)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+	localizeLifetimeValue(ltv, loc)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ltv)
+}
+
+// GetSalesSummary handles GET /orders/summary
+func (h *OrderHandler) GetSalesSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var filter dto.OrderFilter
+	var err error
+	if filter.From, filter.To, err = parseCreatedRange(r, h.timestampMaxFuture); err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	summary, err := h.orderService.GetSalesSummary(r.Context(), filter)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// UpdateOrder handles PUT /orders/{id} and PUT /orders?id=
+func (h *OrderHandler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	orderIDStr := resourceID(r)
+	if orderIDStr == "" {
+		apperror.WriteError(w, apperror.Validation("Order ID is required"))
+		return
+	}
+
+	orderID, err := strconv.ParseUint(orderIDStr, 10, 32)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid order ID"))
+		return
+	}
+
+	var req dto.UpdateOrderRequest
+	if err := decodeJSON(r, &req); err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	if req.ProductID == nil && req.Quantity == nil {
+		apperror.WriteError(w, apperror.Validation("product_id or quantity is required"))
+		return
+	}
+	if (req.ProductID != nil && *req.ProductID == 0) || (req.Quantity != nil && *req.Quantity == 0) {
+		apperror.WriteError(w, apperror.Validation("product_id and quantity must be at least 1"))
+		return
+	}
+
+	callerID, _ := middleware.UserIDFromContext(r.Context())
+	order, err := h.orderService.UpdateOrder(r.Context(), uint(orderID), callerID, req)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// DeleteOrder handles DELETE /orders/{id} and DELETE /orders?id=
+func (h *OrderHandler) DeleteOrder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	orderIDStr := resourceID(r)
+	if orderIDStr == "" {
+		apperror.WriteError(w, apperror.Validation("Order ID is required"))
+		return
+	}
+
+	orderID, err := strconv.ParseUint(orderIDStr, 10, 32)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid order ID"))
+		return
+	}
+
+	callerID, _ := middleware.UserIDFromContext(r.Context())
+	err = h.orderService.DeleteOrder(r.Context(), uint(orderID), callerID)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateOrderStatus handles PATCH /orders/status
+func (h *OrderHandler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	orderIDStr := r.URL.Query().Get("id")
+	if orderIDStr == "" {
+		apperror.WriteError(w, apperror.Validation("Order ID is required"))
+		return
+	}
+
+	orderID, err := strconv.ParseUint(orderIDStr, 10, 32)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid order ID"))
+		return
+	}
+
+	var req dto.UpdateOrderStatusRequest
+	if err := decodeJSON(r, &req); err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	if req.Status == "" {
+		apperror.WriteError(w, apperror.Validation("Status is required"))
+		return
+	}
+
+	order, err := h.orderService.UpdateOrderStatus(r.Context(), uint(orderID), req.Status)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// CreateOrdersBatch handles POST /orders/batch
+func (h *OrderHandler) CreateOrdersBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var reqs []dto.CreateOrderRequest
+	if err := decodeJSON(r, &reqs); err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	if len(reqs) == 0 {
+		apperror.WriteError(w, apperror.Validation("At least one order is required"))
 		return
 	}
+	if len(reqs) > services.MaxBatchSize {
+		apperror.WriteError(w, apperror.Validation("Batch size exceeds maximum of %d", services.MaxBatchSize))
+		return
+	}
+	for i := range reqs {
+		if err := ownOrder(r, &reqs[i]); err != nil {
+			apperror.WriteError(w, apperror.Forbidden("item %d: %w", i, err))
+			return
+		}
+	}
 
-	order, err := h.orderService.GetOrder(uint(orderID))
+	results, err := h.orderService.CreateOrdersBatch(r.Context(), reqs)
 	if err != nil {
-		if err.Error() == "order not found" {
-			http.Error(w, err.Error(), http.StatusNotFound)
+		if services.IsBatchInvalid(err) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(results)
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.WriteError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(order)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(results)
+}
+
+// ownOrder fills in an omitted user_id with the authenticated user and
+// rejects one naming somebody else. Without authentication req is left as is.
+func ownOrder(r *http.Request, req *dto.CreateOrderRequest) error {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		return nil
+	}
+	if req.UserID == 0 {
+		req.UserID = userID
+	}
+	if req.UserID != userID {
+		return apperror.Forbidden("user_id must be the authenticated user")
+	}
+	return nil
+}
+
+// EstimateShipping handles GET /orders/{id}/shipping-estimate
+func (h *OrderHandler) EstimateShipping(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	orderID, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid order ID"))
+		return
+	}
+
+	estimate, err := h.orderService.EstimateShipping(r.Context(), uint(orderID), r.URL.Query().Get("destination"))
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(estimate)
+}
+
+// GetThroughput handles GET /orders/throughput
+func (h *OrderHandler) GetThroughput(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bucket := r.URL.Query().Get("bucket")
+	if bucket == "" {
+		bucket = "1h"
+	}
+
+	from, err := parseTime(r.URL.Query().Get("from"))
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid from, expected RFC 3339"))
+		return
+	}
+	to, err := parseTime(r.URL.Query().Get("to"))
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid to, expected RFC 3339"))
+		return
+	}
+	if err := validateTimestamp(from, h.timestampMaxFuture); err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid from: %v", err))
+		return
+	}
+	if err := validateTimestamp(to, h.timestampMaxFuture); err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid to: %v", err))
+		return
+	}
+
+	throughput, err := h.orderService.GetThroughput(r.Context(), bucket, from, to)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(throughput)
+}
+
+const (
+	// defaultPageLimit is the page size used when ?limit= is omitted
+	defaultPageLimit = 20
+	// maxPageLimit is the largest page size a caller may request
+	maxPageLimit = 100
+)
+
+// parsePagination reads the optional ?limit= and ?offset= query parameters.
+// A limit above maxPageLimit is rejected rather than clamped so callers learn
+// the real cap.
+func parsePagination(r *http.Request) (dto.Pagination, error) {
+	page := dto.Pagination{Limit: defaultPageLimit}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return page, apperror.Validation("limit must be a positive integer")
+		}
+		if limit > maxPageLimit {
+			return page, apperror.Validation("limit must not exceed %d", maxPageLimit)
+		}
+		page.Limit = limit
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return page, apperror.Validation("offset must be a non-negative integer")
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
+
+// parseOptionalID parses an optional positive ID query parameter; a missing
+// parameter yields nil
+func parseOptionalID(r *http.Request, name string) (*uint, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return nil, fmt.Errorf("invalid %s", name)
+	}
+	result := uint(id)
+	return &result, nil
+}
+
+// parseOptionalAmount parses an optional non-negative money query parameter;
+// a missing parameter yields nil
+func parseOptionalAmount(r *http.Request, name string) (*float64, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return nil, fmt.Errorf("invalid %s", name)
+	}
+	return &amount, nil
+}
+
+// parseTime parses an optional RFC 3339 query value; empty yields the zero time
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
 }
 
-// Health handles GET /health
+// parseCreatedRange parses the optional ?from= and ?to= bounds on an order's
+// creation time, each at most maxFuture ahead of now. Either may be omitted
+// to leave that side of the range open.
+func parseCreatedRange(r *http.Request, maxFuture time.Duration) (from, to time.Time, err error) {
+	query := r.URL.Query()
+	if from, err = parseTime(query.Get("from")); err != nil {
+		return time.Time{}, time.Time{}, apperror.Validation("Invalid from, expected RFC 3339")
+	}
+	if to, err = parseTime(query.Get("to")); err != nil {
+		return time.Time{}, time.Time{}, apperror.Validation("Invalid to, expected RFC 3339")
+	}
+	if err := validateTimestamp(from, maxFuture); err != nil {
+		return time.Time{}, time.Time{}, apperror.Validation("Invalid from: %v", err)
+	}
+	if err := validateTimestamp(to, maxFuture); err != nil {
+		return time.Time{}, time.Time{}, apperror.Validation("Invalid to: %v", err)
+	}
+	return from, to, nil
+}
+
+// resourceID returns the ID a request addresses: the {id} path segment of
+// routes like GET /orders/{id}, or else the ?id= query parameter the older
+// routes take
+func resourceID(r *http.Request) string {
+	if id := r.PathValue("id"); id != "" {
+		return id
+	}
+	return r.URL.Query().Get("id")
+}
+
+// SystemHealth handles GET /system/health
+func (h *OrderHandler) SystemHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	health := h.orderService.CheckSystemHealth(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(health)
+}
+
+// Ready handles GET /health/ready, answering 503 when any dependency is down
+func (h *OrderHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	readiness := h.orderService.CheckReadiness(r.Context())
+
+	status := http.StatusOK
+	if readiness.Status != services.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(readiness)
+}
+
+// Health handles GET /health as a liveness probe: it only confirms the
+// process is serving, and reports the downstream circuit breaker states
 func (h *OrderHandler) Health(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Order Service is healthy"))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":   "Order Service is healthy",
+		"breakers": h.orderService.BreakerStates(),
+	})
 }
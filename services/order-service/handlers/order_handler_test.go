@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"order-service/apperror"
+	"order-service/clients"
+	"order-service/config"
+	"order-service/dto"
+	"order-service/events"
+	"order-service/middleware"
+	"order-service/models"
+	"order-service/services"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestHandler returns a handler over a fresh in-memory SQLite database
+// whose downstream services are answered by the given handlers
+func newTestHandler(t *testing.T, users, products http.HandlerFunc) (*OrderHandler, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:         logger.Discard,
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	// Every connection to :memory: is a separate database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.Order{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	userServer := httptest.NewServer(users)
+	t.Cleanup(userServer.Close)
+	productServer := httptest.NewServer(products)
+	t.Cleanup(productServer.Close)
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	service := services.NewOrderService(db, cfg,
+		clients.NewUserHTTPClient(userServer.URL, cfg),
+		clients.NewProductHTTPClient(productServer.URL, cfg),
+		events.NopPublisher{})
+	return NewOrderHandler(service, 24*time.Hour), db
+}
+
+// respondJSON returns a handler answering every request with body
+func respondJSON(body interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+func TestParseCreatedRange(t *testing.T) {
+	from, to, err := parseCreatedRange(httptest.NewRequest(http.MethodGet, "/orders?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00%2B02:00", nil), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("parseCreatedRange: %v", err)
+	}
+	if want := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC); !from.Equal(want) {
+		t.Errorf("from = %s, want %s", from, want)
+	}
+	if want := time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC); !to.Equal(want) {
+		t.Errorf("to = %s, want %s", to, want)
+	}
+
+	from, to, err = parseCreatedRange(httptest.NewRequest(http.MethodGet, "/orders", nil), 24*time.Hour)
+	if err != nil || !from.IsZero() || !to.IsZero() {
+		t.Errorf("no bounds: from = %s, to = %s, err = %v, want an open range", from, to, err)
+	}
+
+	for _, query := range []string{"from=yesterday", "to=2026-03-01", "from=1999-12-31T00:00:00Z"} {
+		_, _, err := parseCreatedRange(httptest.NewRequest(http.MethodGet, "/orders?"+query, nil), 24*time.Hour)
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || appErr.Code != apperror.CodeValidation {
+			t.Errorf("%s: err = %v, want a validation error", query, err)
+		}
+	}
+}
+
+func TestOrderRoutesByPath(t *testing.T) {
+	users := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":1,"name":"Ada","email":"ada@example.com"}`))
+	}
+	products := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":1,"name":"Lamp","price":20}`))
+	}
+	h, db := newTestHandler(t, users, products)
+	if err := db.Create(&models.Order{UserID: 1, ProductID: 1, Quantity: 1, TotalPrice: 20}).Error; err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orders/{id}", h.GetOrder)
+	mux.HandleFunc("DELETE /orders/{id}", h.DeleteOrder)
+	mux.HandleFunc("GET /orders/summary", h.GetSalesSummary)
+
+	tests := []struct {
+		method, target string
+		want           int
+	}{
+		{http.MethodGet, "/orders/1", http.StatusOK},
+		{http.MethodGet, "/orders/abc", http.StatusBadRequest},
+		{http.MethodGet, "/orders/summary", http.StatusOK},
+		{http.MethodDelete, "/orders/1", http.StatusNoContent},
+		{http.MethodGet, "/orders/1", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s %s: status = %d, want %d: %s", tt.method, tt.target, rec.Code, tt.want, rec.Body)
+		}
+	}
+}
+
+// signJWT mints an HS256 token for sub that expires in an hour
+func signJWT(secret []byte, sub uint) string {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims := enc.EncodeToString([]byte(fmt.Sprintf(`{"sub":"%d","exp":%d}`, sub, time.Now().Add(time.Hour).Unix())))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(header + "." + claims))
+	return header + "." + claims + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+func TestOwnOrder(t *testing.T) {
+	secret := []byte("secret")
+	tests := []struct {
+		name     string
+		auth     bool
+		userID   uint
+		wantUser uint
+		wantErr  bool
+	}{
+		{"omitted user", true, 0, 7, false},
+		{"own user", true, 7, 7, false},
+		{"another user", true, 8, 8, true},
+		{"authentication disabled", false, 8, 8, false},
+	}
+	for _, tt := range tests {
+		req := dto.CreateOrderRequest{UserID: tt.userID, ProductID: 1}
+		var err error
+		handler := func(w http.ResponseWriter, r *http.Request) { err = ownOrder(r, &req) }
+		r := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		if tt.auth {
+			r.Header.Set("Authorization", "Bearer "+signJWT(secret, 7))
+			middleware.RequireAuth(secret, handler)(httptest.NewRecorder(), r)
+		} else {
+			handler(httptest.NewRecorder(), r)
+		}
+
+		var appErr *apperror.Error
+		if tt.wantErr {
+			if !errors.As(err, &appErr) || appErr.Code != apperror.CodeForbidden {
+				t.Errorf("%s: err = %v, want forbidden", tt.name, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if req.UserID != tt.wantUser {
+			t.Errorf("%s: user_id = %d, want %d", tt.name, req.UserID, tt.wantUser)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"order-service/dto"
+	"order-service/models"
+	"testing"
+)
+
+func TestEstimateShippingRoute(t *testing.T) {
+	product := dto.ProductResponse{ID: 7, Name: "Lamp", Price: 20, Currency: "USD", WeightGrams: 3000}
+	h, db := newTestHandler(t, http.NotFound, respondJSON(product))
+	if err := db.Create(&models.Order{UserID: 1, ProductID: 7, Quantity: 1, Status: models.StatusPending}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orders/{id}/shipping-estimate", h.EstimateShipping)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/1/shipping-estimate?destination=94107", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var estimate dto.ShippingEstimateResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &estimate); err != nil {
+		t.Fatal(err)
+	}
+	if estimate.OrderID != 1 || estimate.Total != 12.49 {
+		t.Errorf("estimate = %+v, want order 1 at 12.49", estimate)
+	}
+
+	for target, want := range map[string]int{
+		"/orders/abc/shipping-estimate?destination=94107": http.StatusBadRequest,
+		"/orders/2/shipping-estimate?destination=94107":   http.StatusNotFound,
+		"/orders/1/shipping-estimate":                     http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s: status = %d, want %d", target, rec.Code, want)
+		}
+	}
+}
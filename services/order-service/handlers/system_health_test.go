@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"order-service/dto"
+	"order-service/services"
+	"testing"
+)
+
+// healthStub answers /health with 200 when up and 503 otherwise
+func healthStub(up bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}
+}
+
+func TestSystemHealth(t *testing.T) {
+	tests := []struct {
+		name                string
+		usersUp, productsUp bool
+		want                string
+	}{
+		{"all up", true, true, services.HealthUp},
+		{"user service down", false, true, services.HealthDegraded},
+		{"product service down", true, false, services.HealthDegraded},
+		{"all down", false, false, services.HealthDown},
+	}
+	for _, tt := range tests {
+		h, _ := newTestHandler(t, healthStub(tt.usersUp), healthStub(tt.productsUp))
+
+		rec := httptest.NewRecorder()
+		h.SystemHealth(rec, httptest.NewRequest(http.MethodGet, "/system/health", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", tt.name, rec.Code)
+		}
+		var got dto.SystemHealthResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if got.Status != tt.want {
+			t.Errorf("%s: overall status = %q, want %q", tt.name, got.Status, tt.want)
+		}
+		for service, up := range map[string]bool{"order-service": true, "user-service": tt.usersUp, "product-service": tt.productsUp} {
+			want := services.HealthUp
+			if !up {
+				want = services.HealthDown
+			}
+			if got.Services[service].Status != want {
+				t.Errorf("%s: %s = %+v, want %s", tt.name, service, got.Services[service], want)
+			}
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+)
+
+// minTimestamp is the earliest timestamp accepted on input; nothing in the
+// system predates it, so anything earlier is a client bug
+var minTimestamp = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// validateTimestamp rejects timestamps before 2000 or more than maxFuture
+// ahead of now. The zero time means "not given" and passes.
+func validateTimestamp(t time.Time, maxFuture time.Duration) error {
+	if t.IsZero() {
+		return nil
+	}
+	if t.Before(minTimestamp) {
+		return fmt.Errorf("must not be before %s", minTimestamp.Format(time.RFC3339))
+	}
+	if limit := time.Now().Add(maxFuture); t.After(limit) {
+		return fmt.Errorf("must not be more than %s in the future", maxFuture)
+	}
+	return nil
+}
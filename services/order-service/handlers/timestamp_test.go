@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateTimestamp(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		t     time.Time
+		valid bool
+	}{
+		{"not given", time.Time{}, true},
+		{"start of 2000", time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"before 2000", time.Date(1999, 12, 31, 23, 59, 59, 0, time.UTC), false},
+		{"epoch", time.Unix(0, 0), false},
+		{"now", now, true},
+		{"within the future allowance", now.Add(23 * time.Hour), true},
+		{"beyond the future allowance", now.Add(25 * time.Hour), false},
+		{"far future", time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		err := validateTimestamp(tt.t, 24*time.Hour)
+		if tt.valid && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: accepted, want an error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"order-service/dto"
+	"time"
+)
+
+// parseTimezone resolves the optional ?tz= query parameter. A nil location
+// means timestamps are returned as stored (UTC).
+func parseTimezone(r *http.Request) (*time.Location, error) {
+	tz := r.URL.Query().Get("tz")
+	if tz == "" {
+		return nil, nil
+	}
+	return time.LoadLocation(tz)
+}
+
+// localizeOrders converts the timestamps of every order in the slice
+func localizeOrders(orders []dto.OrderResponse, loc *time.Location) {
+	if loc == nil {
+		return
+	}
+	for i := range orders {
+		orders[i].CreatedAt = orders[i].CreatedAt.In(loc)
+		orders[i].UpdatedAt = orders[i].UpdatedAt.In(loc)
+	}
+}
+
+// localizeOrderWithDetails converts the timestamps of the order and its
+// embedded user and product to the given location
+func localizeOrderWithDetails(order *dto.OrderWithDetailsResponse, loc *time.Location) {
+	if loc == nil || order == nil {
+		return
+	}
+	order.CreatedAt = order.CreatedAt.In(loc)
+	order.UpdatedAt = order.UpdatedAt.In(loc)
+	if order.User != nil {
+		order.User.CreatedAt = order.User.CreatedAt.In(loc)
+		order.User.UpdatedAt = order.User.UpdatedAt.In(loc)
+	}
+	if order.Product != nil {
+		order.Product.CreatedAt = order.Product.CreatedAt.In(loc)
+		order.Product.UpdatedAt = order.Product.UpdatedAt.In(loc)
+	}
+}
+
+// localizeLifetimeValue converts the first and last order times to the given
+// location
+func localizeLifetimeValue(ltv *dto.LifetimeValueResponse, loc *time.Location) {
+	if loc == nil {
+		return
+	}
+	if ltv.FirstOrderAt != nil {
+		first := ltv.FirstOrderAt.In(loc)
+		ltv.FirstOrderAt = &first
+	}
+	if ltv.LastOrderAt != nil {
+		last := ltv.LastOrderAt.In(loc)
+		ltv.LastOrderAt = &last
+	}
+}
@@ -0,0 +1,140 @@
+// Package breaker implements a simple consecutive-failure circuit breaker.
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned when a call is rejected because the circuit is open
+var ErrOpen = errors.New("circuit open")
+
+// State is the state of a circuit breaker
+type State int
+
+const (
+	// Closed lets every call through
+	Closed State = iota
+	// Open rejects every call until the cooldown elapses
+	Open
+	// HalfOpen lets a single probe call through to decide whether to close
+	HalfOpen
+)
+
+// String returns the lowercase name of the state
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Breaker opens after a number of consecutive failures and stays open for a
+// cooldown window, after which a single probe decides whether to close again
+type Breaker struct {
+	mu        sync.Mutex
+	state     State
+	failures  int
+	threshold int
+	cooldown  time.Duration
+	openedAt  time.Time
+	probing   bool
+}
+
+// New creates a closed breaker that opens after threshold consecutive
+// failures and stays open for cooldown
+func New(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// Execute runs fn if the breaker allows it and records the outcome. It returns
+// ErrOpen without calling fn when the circuit is open.
+func (b *Breaker) Execute(fn func() error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	err := fn()
+	if err != nil {
+		b.recordFailure()
+	} else {
+		b.recordSuccess()
+	}
+	return err
+}
+
+// State returns the current state, moving an open breaker whose cooldown has
+// elapsed to half-open
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open && time.Since(b.openedAt) >= b.cooldown {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// allow decides whether a call may proceed
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrOpen
+		}
+		b.state = HalfOpen
+		b.probing = true
+		return nil
+	case HalfOpen:
+		if b.probing {
+			return ErrOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// recordSuccess closes the breaker and resets the failure count
+func (b *Breaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = Closed
+	b.failures = 0
+	b.probing = false
+}
+
+// recordFailure counts a failure, opening the breaker when the threshold is
+// reached or when a half-open probe fails
+func (b *Breaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if b.state == HalfOpen {
+		b.state = Open
+		b.openedAt = time.Now()
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.state = Open
+		b.openedAt = time.Now()
+	}
+}
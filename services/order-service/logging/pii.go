@@ -0,0 +1,64 @@
+// Package logging provides log helpers that keep PII out of log output.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"order-service/middleware"
+	"regexp"
+	"strings"
+)
+
+var emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+\-]+)@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+// piiFieldPatterns match "field":"value" and field=value pairs for every
+// field passed to Setup
+var piiFieldPatterns []*regexp.Regexp
+
+// compileFieldPatterns builds the patterns used to redact configured fields
+func compileFieldPatterns(fields []string) []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, field := range fields {
+		name := regexp.QuoteMeta(field)
+		patterns = append(patterns,
+			regexp.MustCompile(`("`+name+`"\s*:\s*")([^"]*)(")`),
+			regexp.MustCompile(`(\b`+name+`=)("[^"]*"|\S+)()`),
+		)
+	}
+	return patterns
+}
+
+// MaskEmail masks the local part of an email address, keeping its first
+// character: john@example.com becomes j***@example.com
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return email
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// Mask returns s with every email address and configured PII field masked
+func Mask(s string) string {
+	s = emailPattern.ReplaceAllStringFunc(s, MaskEmail)
+	for _, pattern := range piiFieldPatterns {
+		s = pattern.ReplaceAllString(s, "${1}***${3}")
+	}
+	return s
+}
+
+// Printf logs a formatted message with PII masked
+func Printf(format string, args ...interface{}) {
+	log.Print(Mask(fmt.Sprintf(format, args...)))
+}
+
+// PrintfContext is Printf with the request ID carried by ctx, if any,
+// prefixed so log lines can be correlated across services
+func PrintfContext(ctx context.Context, format string, args ...interface{}) {
+	if id := middleware.RequestIDFromContext(ctx); id != "" {
+		format = "[request_id=%s] " + format
+		args = append([]interface{}{id}, args...)
+	}
+	Printf(format, args...)
+}
@@ -1,39 +1,179 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"order-service/clients"
+	"order-service/config"
 	"order-service/database"
+	"order-service/events"
 	"order-service/handlers"
+	"order-service/logging"
+	"order-service/middleware"
+	"order-service/openapi"
 	"order-service/services"
+	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata" // embed zone data so ?tz= works in minimal images
 )
 
 func main() {
+	// Every setting comes from the environment; refuse to start on a
+	// malformed one rather than run with a surprising default
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// JSON logs at the configured level (debug, info, warn or error), with
+	// emails and PII_FIELDS masked
+	logging.Setup(cfg.LogLevel, cfg.PIIFields)
+
 	// Connect to database
-	database.ConnectDB()
+	database.ConnectDB(cfg.Database)
 	database.MigrateDB()
 
+	// Order lifecycle events, e.g. order.created; publish counts are served
+	// at /debug/vars
+	publisher, err := events.NewPublisher(cfg.Events)
+	if err != nil {
+		slog.Error("invalid EVENT_PUBLISHER", "error", err)
+		os.Exit(1)
+	}
+
+	// Clients for the user and product services; products are read over
+	// gRPC when PRODUCT_TRANSPORT=grpc
+	userClient := clients.NewUserClient(cfg)
+	productClient := clients.NewProductClient(cfg)
+
 	// Initialize services
-	orderService := services.NewOrderService(database.DB)
-	orderHandler := handlers.NewOrderHandler(orderService)
+	orderService := services.NewOrderService(database.DB, cfg, userClient, productClient, publisher)
+	orderHandler := handlers.NewOrderHandler(orderService, cfg.TimestampMaxFuture)
+
+	// Cache policies; orders change frequently and carry user data
+	ordersCacheControl := cfg.CacheControlOrders
+	healthCacheControl := cfg.CacheControlHealth
+
+	// When enabled, creates must carry an Idempotency-Key header
+	requireIdempotencyKey := cfg.HTTP.RequireIdempotencyKey
+
+	// Endpoints can be switched off with FEATURE_FLAGS, e.g. "order_search=false"
+	flags, err := middleware.ParseFlags(cfg.HTTP.FeatureFlags)
+	if err != nil {
+		slog.Error("invalid FEATURE_FLAGS", "error", err)
+		os.Exit(1)
+	}
+
+	// Routes wrapped in auth require a bearer JWT signed with JWT_SECRET;
+	// while it is unset they stay open
+	jwtSecret := []byte(cfg.HTTP.JWTSecret)
+	if len(jwtSecret) == 0 {
+		slog.Warn("JWT_SECRET is not set, authentication is disabled")
+	}
+	auth := func(next http.HandlerFunc) http.HandlerFunc { return middleware.RequireAuth(jwtSecret, next) }
+	createOrder := auth(orderHandler.CreateOrder)
+	updateOrder := auth(orderHandler.UpdateOrder)
+	deleteOrder := auth(orderHandler.DeleteOrder)
 
 	// Set up routes
-	http.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/orders", middleware.CacheControl(ordersCacheControl, middleware.RequireIdempotencyKey(requireIdempotencyKey, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
-			orderHandler.CreateOrder(w, r)
+			createOrder(w, r)
 		case http.MethodGet:
 			orderHandler.GetOrder(w, r)
+		case http.MethodPut:
+			updateOrder(w, r)
+		case http.MethodDelete:
+			deleteOrder(w, r)
 		default:
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
-	})
+	})))
+
+	// A single order can also be addressed by path, e.g. GET /orders/42. The
+	// fixed routes below carry a method so the mux ranks them above {id}.
+	http.HandleFunc("GET /orders/{id}", middleware.CacheControl(ordersCacheControl, orderHandler.GetOrder))
+	http.HandleFunc("PUT /orders/{id}", updateOrder)
+	http.HandleFunc("DELETE /orders/{id}", deleteOrder)
+
+	http.HandleFunc("GET /orders/by-user", middleware.Feature(flags, "orders_by_user", middleware.CacheControl(ordersCacheControl, orderHandler.GetOrdersByUser)))
+	http.HandleFunc("GET /orders/ltv", middleware.CacheControl(ordersCacheControl, orderHandler.GetLifetimeValue))
+	http.HandleFunc("GET /orders/summary", middleware.CacheControl(ordersCacheControl, orderHandler.GetSalesSummary))
+	http.HandleFunc("GET /orders/search", middleware.Feature(flags, "order_search", middleware.CacheControl(ordersCacheControl, orderHandler.SearchOrders)))
+	http.HandleFunc("PATCH /orders/status", auth(orderHandler.UpdateOrderStatus))
+	http.HandleFunc("GET /orders/{id}/shipping-estimate", middleware.Feature(flags, "shipping_estimate", orderHandler.EstimateShipping))
+	http.HandleFunc("GET /orders/throughput", middleware.CacheControl(ordersCacheControl, orderHandler.GetThroughput))
+	http.HandleFunc("POST /orders/batch", auth(middleware.RequireIdempotencyKey(requireIdempotencyKey, orderHandler.CreateOrdersBatch)))
+
+	// Liveness and readiness probes
+	http.HandleFunc("/health", middleware.CacheControl(healthCacheControl, orderHandler.Health))
+	http.HandleFunc("/health/ready", middleware.CacheControl(healthCacheControl, orderHandler.Ready))
+
+	// Aggregated health of the whole system
+	http.HandleFunc("/system/health", middleware.CacheControl(healthCacheControl, orderHandler.SystemHealth))
+
+	// Machine-readable description of the routes above
+	apiDoc := openapi.Build(openapi.Info{Title: "Order Service", Version: "1.0.0"}, handlers.Operations())
+	http.HandleFunc("/openapi.json", openapi.Handler(apiDoc))
+
+	// Header size cap enforced by net/http (431 when exceeded), and how many
+	// times a single header or query parameter may repeat
+	maxHeaderBytes := cfg.HTTP.MaxHeaderBytes
+	maxRepeated := cfg.HTTP.MaxRepeatedValues
+
+	// Request bodies larger than this are refused with 413
+	maxBodyBytes := cfg.HTTP.MaxBodyBytes
+
+	// Browser origins allowed to call the service, comma-separated or "*"
+	allowedOrigins := cfg.HTTP.AllowedOrigins
+
+	// Per-client rate limit; RATE_LIMIT_RPS unset disables it
+	var limiter *middleware.RateLimiter
+	if cfg.HTTP.RateLimitRPS > 0 {
+		limiter = middleware.NewRateLimiter(cfg.HTTP.RateLimitRPS, cfg.HTTP.RateLimitBurst, cfg.HTTP.RateLimitTrustProxy)
+	}
+
+	// "rewrite" (default) or "redirect" for paths with a trailing slash
+	trailingSlashMode := cfg.HTTP.TrailingSlashMode
+	handler := middleware.Chain(http.DefaultServeMux.ServeHTTP,
+		middleware.RequestID,
+		middleware.LogRequests,
+		middleware.Recover,
+		func(next http.HandlerFunc) http.HandlerFunc { return middleware.CORS(allowedOrigins, next) },
+		limiter.Limit,
+		func(next http.HandlerFunc) http.HandlerFunc { return middleware.LimitRepeated(maxRepeated, next) },
+		func(next http.HandlerFunc) http.HandlerFunc { return middleware.LimitBody(maxBodyBytes, next) },
+		func(next http.HandlerFunc) http.HandlerFunc { return middleware.TrailingSlash(trailingSlashMode, next) },
+	)
+
+	slog.Info("Order Service starting", "port", cfg.Port)
+	server := &http.Server{Addr: fmt.Sprintf(":%d", cfg.Port), Handler: handler, MaxHeaderBytes: maxHeaderBytes}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.ListenAndServe() }()
 
-	// Health check endpoint
-	http.HandleFunc("/health", orderHandler.Health)
+	// On SIGINT or SIGTERM stop accepting requests, let in-flight ones finish
+	// and flush queued events before exiting
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serveErr:
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	case sig := <-stop:
+		slog.Info("Order Service shutting down", "signal", sig.String())
+	}
 
-	fmt.Println("Order Service starting on port 8082...")
-	fmt.Println("Make sure User Service (port 8080) and Product Service (port 8081) are running!")
-	log.Fatal(http.ListenAndServe(":8082", nil))
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Error("server shutdown", "error", err)
+	}
+	if err := events.Close(ctx, publisher); err != nil {
+		slog.Error("event publisher shutdown", "error", err)
+	}
 }
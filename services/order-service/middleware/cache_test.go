@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func okHandler(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+func TestCacheControl(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		method string
+		want   string
+	}{
+		{"cacheable read", "public, max-age=300", http.MethodGet, "public, max-age=300"},
+		{"no-store read", "no-store", http.MethodGet, "no-store"},
+		{"head", "public, max-age=300", http.MethodHead, "public, max-age=300"},
+		{"write", "public, max-age=300", http.MethodPost, ""},
+		{"not configured", "", http.MethodGet, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		CacheControl(tt.value, okHandler)(rec, httptest.NewRequest(tt.method, "/products/categories", nil))
+		if got := rec.Header().Get("Cache-Control"); got != tt.want {
+			t.Errorf("%s: Cache-Control = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import "net/http"
+
+// Middleware wraps a handler with extra behavior
+type Middleware func(http.HandlerFunc) http.HandlerFunc
+
+// Chain wraps h with middlewares so that the first one listed is the
+// outermost, i.e. Chain(h, a, b) is a(b(h))
+func Chain(h http.HandlerFunc, middlewares ...Middleware) http.HandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	flags, err := ParseFlags(" order_search=false, product_usage=true ,")
+	if err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	if flags.Enabled("order_search") || !flags.Enabled("product_usage") || !flags.Enabled("unlisted") {
+		t.Errorf("flags = %v, want order_search off and everything else on", flags)
+	}
+
+	for _, value := range []string{"order_search", "order_search=maybe"} {
+		if _, err := ParseFlags(value); err == nil {
+			t.Errorf("ParseFlags(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestFeature(t *testing.T) {
+	flags := Flags{"order_search": false, "shipping_estimate": true}
+	tests := []struct {
+		feature string
+		want    int
+	}{
+		{"order_search", http.StatusNotFound},
+		{"shipping_estimate", http.StatusOK},
+		{"orders_by_user", http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		Feature(flags, tt.feature, okHandler)(rec, httptest.NewRequest(http.MethodGet, "/orders/search", nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.feature, rec.Code, tt.want)
+		}
+	}
+}
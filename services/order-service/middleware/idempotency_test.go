@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		method  string
+		key     string
+		want    int
+	}{
+		{"disabled without key", false, http.MethodPost, "", http.StatusCreated},
+		{"enabled without key", true, http.MethodPost, "", http.StatusBadRequest},
+		{"enabled with key", true, http.MethodPost, "k1", http.StatusCreated},
+		{"enabled on a read", true, http.MethodGet, "", http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := RequireIdempotencyKey(tt.enabled, func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusCreated)
+			})
+			req := httptest.NewRequest(tt.method, "/orders", nil)
+			if tt.key != "" {
+				req.Header.Set("Idempotency-Key", tt.key)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if called != (tt.want == http.StatusCreated) {
+				t.Errorf("handler called = %v, want %v", called, !called)
+			}
+		})
+	}
+}
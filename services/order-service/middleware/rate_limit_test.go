@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// fire sends n requests from remoteAddr through handler and counts the
+// answers by status
+func fire(t *testing.T, handler http.HandlerFunc, n int, remoteAddr, forwardedFor string) map[int]int {
+	t.Helper()
+	statuses := make(map[int]int)
+	for i := 0; i < n; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		statuses[rec.Code]++
+		if rec.Code == http.StatusTooManyRequests {
+			if seconds, err := strconv.Atoi(rec.Header().Get("Retry-After")); err != nil || seconds < 1 {
+				t.Errorf("429 with Retry-After %q, want whole seconds", rec.Header().Get("Retry-After"))
+			}
+		}
+	}
+	return statuses
+}
+
+func TestRateLimiterRejectsOverLimit(t *testing.T) {
+	handler := NewRateLimiter(1, 3, false).Limit(okHandler)
+
+	statuses := fire(t, handler, 10, "192.0.2.1:1234", "")
+	if statuses[http.StatusOK] != 3 || statuses[http.StatusTooManyRequests] != 7 {
+		t.Errorf("statuses = %v, want 3 accepted and 7 rejected with 429", statuses)
+	}
+
+	// Each client has its own bucket
+	if statuses := fire(t, handler, 1, "192.0.2.2:1234", ""); statuses[http.StatusOK] != 1 {
+		t.Errorf("second client: statuses = %v, want it accepted", statuses)
+	}
+}
+
+func TestRateLimiterForwardedFor(t *testing.T) {
+	// Behind a trusted proxy every request shares the proxy's address
+	trusted := NewRateLimiter(1, 1, true).Limit(okHandler)
+	if statuses := fire(t, trusted, 1, "10.0.0.1:1234", "192.0.2.1"); statuses[http.StatusOK] != 1 {
+		t.Errorf("first client: statuses = %v, want it accepted", statuses)
+	}
+	if statuses := fire(t, trusted, 1, "10.0.0.1:1234", "192.0.2.2, 10.0.0.2"); statuses[http.StatusOK] != 1 {
+		t.Errorf("second client: statuses = %v, want it accepted", statuses)
+	}
+
+	// Without trust the header is ignored, so it can't dodge the limit
+	untrusted := NewRateLimiter(1, 1, false).Limit(okHandler)
+	fire(t, untrusted, 1, "192.0.2.1:1234", "198.51.100.1")
+	if statuses := fire(t, untrusted, 1, "192.0.2.1:1234", "198.51.100.2"); statuses[http.StatusTooManyRequests] != 1 {
+		t.Errorf("spoofed header: statuses = %v, want 429", statuses)
+	}
+}
+
+func TestRateLimiterNil(t *testing.T) {
+	var limiter *RateLimiter
+	if statuses := fire(t, limiter.Limit(okHandler), 100, "192.0.2.1:1234", ""); statuses[http.StatusOK] != 100 {
+		t.Errorf("statuses = %v, want every request accepted", statuses)
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// LimitRepeated rejects requests that repeat any header or query parameter
+// more than max times. Too many copies of a header yield 431 Request Header
+// Fields Too Large; too many of a query parameter yield 400. A max of 0 or
+// less disables the check.
+func LimitRepeated(max int, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if max > 0 {
+			for name, values := range r.Header {
+				if len(values) > max {
+					http.Error(w, fmt.Sprintf("Header %s repeated more than %d times", name, max), http.StatusRequestHeaderFieldsTooLarge)
+					return
+				}
+			}
+			for name, values := range r.URL.Query() {
+				if len(values) > max {
+					http.Error(w, fmt.Sprintf("Query parameter %s repeated more than %d times", name, max), http.StatusBadRequest)
+					return
+				}
+			}
+		}
+		next(w, r)
+	}
+}
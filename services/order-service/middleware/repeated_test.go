@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLimitRepeated(t *testing.T) {
+	request := func(headers, params int) *http.Request {
+		target := "/products?"
+		for i := 0; i < params; i++ {
+			target += "category=c&"
+		}
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for i := 0; i < headers; i++ {
+			req.Header.Add("X-Tag", "t")
+		}
+		return req
+	}
+
+	tests := []struct {
+		name            string
+		max             int
+		headers, params int
+		want            int
+	}{
+		{"headers under the limit", 3, 3, 0, http.StatusOK},
+		{"headers over the limit", 3, 4, 0, http.StatusRequestHeaderFieldsTooLarge},
+		{"parameters under the limit", 3, 0, 3, http.StatusOK},
+		{"parameters over the limit", 3, 0, 4, http.StatusBadRequest},
+		{"check disabled", 0, 50, 50, http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		LimitRepeated(tt.max, okHandler)(rec, request(tt.headers, tt.params))
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
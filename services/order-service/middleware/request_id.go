@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+)
+
+// RequestIDHeader is the header used to carry the request/correlation ID
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID matches the client request IDs that are accepted; anything
+// else could inject text into logs and downstream headers
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type requestIDKey struct{}
+
+// RequestID resolves the request ID from the incoming X-Request-ID header,
+// generating one when it is absent or malformed, stores it in the request
+// context and echoes it back in the response header
+func RequestID(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// RequestIDFromContext returns the request ID stored by RequestID, if any
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// newRequestID generates a random 128-bit hex request ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
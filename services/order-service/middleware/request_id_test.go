@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestIDEcho(t *testing.T) {
+	tests := []struct {
+		name, incoming string
+		kept           bool
+	}{
+		{"valid", "req_42-abc", true},
+		{"longest", strings.Repeat("a", 64), true},
+		{"missing", "", false},
+		{"too long", strings.Repeat("a", 65), false},
+		{"spaces", "abc def", false},
+		{"log injection", "abc\" level=error", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := RequestID(func(w http.ResponseWriter, r *http.Request) {
+				seen = RequestIDFromContext(r.Context())
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			echoed := rec.Header().Get(RequestIDHeader)
+			if echoed != seen {
+				t.Errorf("echoed %q, want the ID the handler saw, %q", echoed, seen)
+			}
+			if kept := echoed == tt.incoming; kept != tt.kept {
+				t.Errorf("echoed %q for incoming %q, kept = %v, want %v", echoed, tt.incoming, kept, tt.kept)
+			}
+			if !validRequestID.MatchString(echoed) {
+				t.Errorf("echoed %q, want a valid ID", echoed)
+			}
+		})
+	}
+}
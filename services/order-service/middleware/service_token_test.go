@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// signJWT mints an HS256 token for sub that expires in an hour
+func signJWT(secret []byte, sub uint) string {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims := enc.EncodeToString([]byte(fmt.Sprintf(`{"sub":"%d","exp":%d}`, sub, time.Now().Add(time.Hour).Unix())))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(header + "." + claims))
+	return header + "." + claims + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+func TestRequireServiceOrAuth(t *testing.T) {
+	secret := []byte("secret")
+	tests := []struct {
+		name          string
+		token         string
+		serviceToken  string
+		authorization string
+		want          int
+	}{
+		{"service token", "s3cret", "s3cret", "", http.StatusOK},
+		{"wrong service token", "s3cret", "guess", "", http.StatusUnauthorized},
+		{"no credentials", "s3cret", "", "", http.StatusUnauthorized},
+		{"user token", "s3cret", "", "Bearer " + signJWT(secret, 7), http.StatusOK},
+		{"user token signed elsewhere", "s3cret", "", "Bearer " + signJWT([]byte("other"), 7), http.StatusUnauthorized},
+		{"no service token configured", "", "", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		handler := RequireServiceOrAuth(tt.token, secret, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		req := httptest.NewRequest(http.MethodPost, "/products/stock?id=1", nil)
+		if tt.serviceToken != "" {
+			req.Header.Set(ServiceTokenHeader, tt.serviceToken)
+		}
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// productsMux serves only the canonical /products path, like the services
+func productsMux(reached *string) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/products", func(w http.ResponseWriter, r *http.Request) {
+		*reached = r.URL.RequestURI()
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+func TestTrailingSlashRewrite(t *testing.T) {
+	for _, target := range []string{"/products", "/products/", "/products//?category=books"} {
+		var reached string
+		rec := httptest.NewRecorder()
+		TrailingSlash("rewrite", productsMux(&reached).ServeHTTP)(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK || reached == "" {
+			t.Errorf("GET %s: status = %d, want the /products handler reached", target, rec.Code)
+		}
+	}
+
+	var reached string
+	rec := httptest.NewRecorder()
+	TrailingSlash("rewrite", productsMux(&reached).ServeHTTP)(rec, httptest.NewRequest(http.MethodGet, "/products/?category=books", nil))
+	if reached != "/products?category=books" {
+		t.Errorf("handler saw %q, want the query kept", reached)
+	}
+}
+
+func TestTrailingSlashRedirect(t *testing.T) {
+	var reached string
+	handler := TrailingSlash("redirect", productsMux(&reached).ServeHTTP)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/products/?category=books&limit=5", nil))
+	if rec.Code != http.StatusPermanentRedirect {
+		t.Fatalf("status = %d, want 308", rec.Code)
+	}
+	if got := rec.Header().Get("Location"); got != "/products?category=books&limit=5" {
+		t.Errorf("Location = %q, want the canonical path with the query preserved", got)
+	}
+	if reached != "" {
+		t.Error("handler reached before the redirect was followed")
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/products?category=books", nil))
+	if rec.Code != http.StatusOK || reached != "/products?category=books" {
+		t.Errorf("canonical path: status = %d, handler saw %q", rec.Code, reached)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code == http.StatusPermanentRedirect {
+		t.Error("root path redirected")
+	}
+}
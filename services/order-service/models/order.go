@@ -6,12 +6,25 @@ import (
 	"gorm.io/gorm"
 )
 
+// Order lifecycle statuses
+const (
+	StatusPending   = "pending"
+	StatusPaid      = "paid"
+	StatusShipped   = "shipped"
+	StatusDelivered = "delivered"
+	StatusCancelled = "cancelled"
+)
+
 // Order represents an order in our system
 type Order struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	UserID    uint           `json:"user_id" gorm:"not null"`
-	ProductID uint           `json:"product_id" gorm:"not null"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	UserID     uint           `json:"user_id" gorm:"not null;index"`
+	ProductID  uint           `json:"product_id" gorm:"not null;index"`
+	Quantity   uint           `json:"quantity" gorm:"not null;default:1"`
+	TotalPrice float64        `json:"total_price" gorm:"not null;default:0"`
+	Currency   string         `json:"currency" gorm:"size:3;not null;default:USD"`
+	Status     string         `json:"status" gorm:"not null;default:pending;index"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
 }
@@ -0,0 +1,625 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.0
+// source: proto/product.proto
+
+package productpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Request/Response messages
+type GetProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductRequest) Reset() {
+	*x = GetProductRequest{}
+	mi := &file_proto_product_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductRequest) ProtoMessage() {}
+
+func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
+func (*GetProductRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetProductRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Products that don't exist are absent from the response
+type GetProductsByIDsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []uint32               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsByIDsRequest) Reset() {
+	*x = GetProductsByIDsRequest{}
+	mi := &file_proto_product_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsByIDsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsByIDsRequest) ProtoMessage() {}
+
+func (x *GetProductsByIDsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsByIDsRequest.ProtoReflect.Descriptor instead.
+func (*GetProductsByIDsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetProductsByIDsRequest) GetIds() []uint32 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type GetProductsByIDsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*ProductResponse     `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsByIDsResponse) Reset() {
+	*x = GetProductsByIDsResponse{}
+	mi := &file_proto_product_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsByIDsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsByIDsResponse) ProtoMessage() {}
+
+func (x *GetProductsByIDsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsByIDsResponse.ProtoReflect.Descriptor instead.
+func (*GetProductsByIDsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetProductsByIDsResponse) GetProducts() []*ProductResponse {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type CreateProductRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description    string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Price          float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Category       string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Barcode        string                 `protobuf:"bytes,5,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	FeaturedWeight float64                `protobuf:"fixed64,6,opt,name=featured_weight,json=featuredWeight,proto3" json:"featured_weight,omitempty"`
+	WeightGrams    int32                  `protobuf:"varint,7,opt,name=weight_grams,json=weightGrams,proto3" json:"weight_grams,omitempty"`
+	DimensionsCm   *Dimensions            `protobuf:"bytes,8,opt,name=dimensions_cm,json=dimensionsCm,proto3" json:"dimensions_cm,omitempty"`
+	Stock          int32                  `protobuf:"varint,9,opt,name=stock,proto3" json:"stock,omitempty"`
+	// RFC 3339 timestamps; empty means unbounded on that side
+	AvailableFrom  string `protobuf:"bytes,10,opt,name=available_from,json=availableFrom,proto3" json:"available_from,omitempty"`
+	AvailableUntil string `protobuf:"bytes,11,opt,name=available_until,json=availableUntil,proto3" json:"available_until,omitempty"`
+	// ISO 4217 code; USD when empty
+	Currency      string `protobuf:"bytes,12,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateProductRequest) Reset() {
+	*x = CreateProductRequest{}
+	mi := &file_proto_product_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProductRequest) ProtoMessage() {}
+
+func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
+func (*CreateProductRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateProductRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetBarcode() string {
+	if x != nil {
+		return x.Barcode
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetFeaturedWeight() float64 {
+	if x != nil {
+		return x.FeaturedWeight
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetWeightGrams() int32 {
+	if x != nil {
+		return x.WeightGrams
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetDimensionsCm() *Dimensions {
+	if x != nil {
+		return x.DimensionsCm
+	}
+	return nil
+}
+
+func (x *CreateProductRequest) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetAvailableFrom() string {
+	if x != nil {
+		return x.AvailableFrom
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetAvailableUntil() string {
+	if x != nil {
+		return x.AvailableUntil
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type ProductResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description    string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price          float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Category       string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Barcode        string                 `protobuf:"bytes,8,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	FeaturedWeight float64                `protobuf:"fixed64,9,opt,name=featured_weight,json=featuredWeight,proto3" json:"featured_weight,omitempty"`
+	WeightGrams    int32                  `protobuf:"varint,10,opt,name=weight_grams,json=weightGrams,proto3" json:"weight_grams,omitempty"`
+	DimensionsCm   *Dimensions            `protobuf:"bytes,11,opt,name=dimensions_cm,json=dimensionsCm,proto3" json:"dimensions_cm,omitempty"`
+	Stock          int32                  `protobuf:"varint,12,opt,name=stock,proto3" json:"stock,omitempty"`
+	// RFC 3339 timestamps; empty means unbounded on that side
+	AvailableFrom  string `protobuf:"bytes,13,opt,name=available_from,json=availableFrom,proto3" json:"available_from,omitempty"`
+	AvailableUntil string `protobuf:"bytes,14,opt,name=available_until,json=availableUntil,proto3" json:"available_until,omitempty"`
+	// ISO 4217 code of price
+	Currency      string `protobuf:"bytes,15,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductResponse) Reset() {
+	*x = ProductResponse{}
+	mi := &file_proto_product_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductResponse) ProtoMessage() {}
+
+func (x *ProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductResponse.ProtoReflect.Descriptor instead.
+func (*ProductResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ProductResponse) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ProductResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductResponse) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ProductResponse) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ProductResponse) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ProductResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ProductResponse) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *ProductResponse) GetBarcode() string {
+	if x != nil {
+		return x.Barcode
+	}
+	return ""
+}
+
+func (x *ProductResponse) GetFeaturedWeight() float64 {
+	if x != nil {
+		return x.FeaturedWeight
+	}
+	return 0
+}
+
+func (x *ProductResponse) GetWeightGrams() int32 {
+	if x != nil {
+		return x.WeightGrams
+	}
+	return 0
+}
+
+func (x *ProductResponse) GetDimensionsCm() *Dimensions {
+	if x != nil {
+		return x.DimensionsCm
+	}
+	return nil
+}
+
+func (x *ProductResponse) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *ProductResponse) GetAvailableFrom() string {
+	if x != nil {
+		return x.AvailableFrom
+	}
+	return ""
+}
+
+func (x *ProductResponse) GetAvailableUntil() string {
+	if x != nil {
+		return x.AvailableUntil
+	}
+	return ""
+}
+
+func (x *ProductResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Dimensions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Length        float64                `protobuf:"fixed64,1,opt,name=length,proto3" json:"length,omitempty"`
+	Width         float64                `protobuf:"fixed64,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height        float64                `protobuf:"fixed64,3,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Dimensions) Reset() {
+	*x = Dimensions{}
+	mi := &file_proto_product_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dimensions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dimensions) ProtoMessage() {}
+
+func (x *Dimensions) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dimensions.ProtoReflect.Descriptor instead.
+func (*Dimensions) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Dimensions) GetLength() float64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *Dimensions) GetWidth() float64 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Dimensions) GetHeight() float64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+var File_proto_product_proto protoreflect.FileDescriptor
+
+const file_proto_product_proto_rawDesc = "" +
+	"\n" +
+	"\x13proto/product.proto\x12\aproduct\"#\n" +
+	"\x11GetProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\"+\n" +
+	"\x17GetProductsByIDsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\rR\x03ids\"P\n" +
+	"\x18GetProductsByIDsResponse\x124\n" +
+	"\bproducts\x18\x01 \x03(\v2\x18.product.ProductResponseR\bproducts\"\xa0\x03\n" +
+	"\x14CreateProductRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bcategory\x18\x04 \x01(\tR\bcategory\x12\x18\n" +
+	"\abarcode\x18\x05 \x01(\tR\abarcode\x12'\n" +
+	"\x0ffeatured_weight\x18\x06 \x01(\x01R\x0efeaturedWeight\x12!\n" +
+	"\fweight_grams\x18\a \x01(\x05R\vweightGrams\x128\n" +
+	"\rdimensions_cm\x18\b \x01(\v2\x13.product.DimensionsR\fdimensionsCm\x12\x14\n" +
+	"\x05stock\x18\t \x01(\x05R\x05stock\x12%\n" +
+	"\x0eavailable_from\x18\n" +
+	" \x01(\tR\ravailableFrom\x12'\n" +
+	"\x0favailable_until\x18\v \x01(\tR\x0eavailableUntil\x12\x1a\n" +
+	"\bcurrency\x18\f \x01(\tR\bcurrency\"\xe9\x03\n" +
+	"\x0fProductResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bcategory\x18\x05 \x01(\tR\bcategory\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\x12\x18\n" +
+	"\abarcode\x18\b \x01(\tR\abarcode\x12'\n" +
+	"\x0ffeatured_weight\x18\t \x01(\x01R\x0efeaturedWeight\x12!\n" +
+	"\fweight_grams\x18\n" +
+	" \x01(\x05R\vweightGrams\x128\n" +
+	"\rdimensions_cm\x18\v \x01(\v2\x13.product.DimensionsR\fdimensionsCm\x12\x14\n" +
+	"\x05stock\x18\f \x01(\x05R\x05stock\x12%\n" +
+	"\x0eavailable_from\x18\r \x01(\tR\ravailableFrom\x12'\n" +
+	"\x0favailable_until\x18\x0e \x01(\tR\x0eavailableUntil\x12\x1a\n" +
+	"\bcurrency\x18\x0f \x01(\tR\bcurrency\"R\n" +
+	"\n" +
+	"Dimensions\x12\x16\n" +
+	"\x06length\x18\x01 \x01(\x01R\x06length\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\x01R\x05width\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x01R\x06height2\xf7\x01\n" +
+	"\x0eProductService\x12B\n" +
+	"\n" +
+	"GetProduct\x12\x1a.product.GetProductRequest\x1a\x18.product.ProductResponse\x12W\n" +
+	"\x10GetProductsByIDs\x12 .product.GetProductsByIDsRequest\x1a!.product.GetProductsByIDsResponse\x12H\n" +
+	"\rCreateProduct\x12\x1d.product.CreateProductRequest\x1a\x18.product.ProductResponseB\x17Z\x15product-service/protob\x06proto3"
+
+var (
+	file_proto_product_proto_rawDescOnce sync.Once
+	file_proto_product_proto_rawDescData []byte
+)
+
+func file_proto_product_proto_rawDescGZIP() []byte {
+	file_proto_product_proto_rawDescOnce.Do(func() {
+		file_proto_product_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_product_proto_rawDesc), len(file_proto_product_proto_rawDesc)))
+	})
+	return file_proto_product_proto_rawDescData
+}
+
+var file_proto_product_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_product_proto_goTypes = []any{
+	(*GetProductRequest)(nil),        // 0: product.GetProductRequest
+	(*GetProductsByIDsRequest)(nil),  // 1: product.GetProductsByIDsRequest
+	(*GetProductsByIDsResponse)(nil), // 2: product.GetProductsByIDsResponse
+	(*CreateProductRequest)(nil),     // 3: product.CreateProductRequest
+	(*ProductResponse)(nil),          // 4: product.ProductResponse
+	(*Dimensions)(nil),               // 5: product.Dimensions
+}
+var file_proto_product_proto_depIdxs = []int32{
+	4, // 0: product.GetProductsByIDsResponse.products:type_name -> product.ProductResponse
+	5, // 1: product.CreateProductRequest.dimensions_cm:type_name -> product.Dimensions
+	5, // 2: product.ProductResponse.dimensions_cm:type_name -> product.Dimensions
+	0, // 3: product.ProductService.GetProduct:input_type -> product.GetProductRequest
+	1, // 4: product.ProductService.GetProductsByIDs:input_type -> product.GetProductsByIDsRequest
+	3, // 5: product.ProductService.CreateProduct:input_type -> product.CreateProductRequest
+	4, // 6: product.ProductService.GetProduct:output_type -> product.ProductResponse
+	2, // 7: product.ProductService.GetProductsByIDs:output_type -> product.GetProductsByIDsResponse
+	4, // 8: product.ProductService.CreateProduct:output_type -> product.ProductResponse
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_proto_product_proto_init() }
+func file_proto_product_proto_init() {
+	if File_proto_product_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_product_proto_rawDesc), len(file_proto_product_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_product_proto_goTypes,
+		DependencyIndexes: file_proto_product_proto_depIdxs,
+		MessageInfos:      file_proto_product_proto_msgTypes,
+	}.Build()
+	File_proto_product_proto = out.File
+	file_proto_product_proto_goTypes = nil
+	file_proto_product_proto_depIdxs = nil
+}
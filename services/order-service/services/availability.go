@@ -0,0 +1,26 @@
+package services
+
+import (
+	"fmt"
+	"order-service/apperror"
+	"order-service/dto"
+	"time"
+)
+
+// ErrProductUnavailable is returned when ordering a product outside its
+// availability window
+var ErrProductUnavailable = apperror.Conflict("product is not available to order")
+
+// checkAvailability rejects orders for a product outside its availability
+// window. The window includes AvailableFrom and excludes AvailableUntil.
+func checkAvailability(product *dto.ProductResponse, now time.Time) error {
+	if product.AvailableFrom != nil && now.Before(*product.AvailableFrom) {
+		return fmt.Errorf("%w: product %d becomes orderable at %s", ErrProductUnavailable,
+			product.ID, product.AvailableFrom.UTC().Format(time.RFC3339))
+	}
+	if product.AvailableUntil != nil && !now.Before(*product.AvailableUntil) {
+		return fmt.Errorf("%w: product %d stopped being orderable at %s", ErrProductUnavailable,
+			product.ID, product.AvailableUntil.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"order-service/dto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckAvailability(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name        string
+		from, until *time.Time
+		available   bool
+	}{
+		{"no window", nil, nil, true},
+		{"before window", at(time.Hour), at(2 * time.Hour), false},
+		{"in window", at(-time.Hour), at(time.Hour), true},
+		{"opens now", at(0), nil, true},
+		{"closes now", nil, at(0), false},
+		{"after window", at(-2 * time.Hour), at(-time.Hour), false},
+	}
+	for _, tt := range tests {
+		product := &dto.ProductResponse{ID: 7, AvailableFrom: tt.from, AvailableUntil: tt.until}
+		err := checkAvailability(product, now)
+		if tt.available && err != nil {
+			t.Errorf("%s: err = %v, want orderable", tt.name, err)
+		}
+		if !tt.available && !errors.Is(err, ErrProductUnavailable) {
+			t.Errorf("%s: err = %v, want ErrProductUnavailable", tt.name, err)
+		}
+	}
+}
+
+func TestCreateOrderAvailabilityWindow(t *testing.T) {
+	now := time.Now()
+	hour := time.Hour
+	tests := []struct {
+		name        string
+		from, until time.Duration
+		available   bool
+		message     string
+	}{
+		{"before window", hour, 2 * hour, false, "becomes orderable at"},
+		{"in window", -hour, hour, true, ""},
+		{"after window", -2 * hour, -hour, false, "stopped being orderable at"},
+	}
+	for _, tt := range tests {
+		s, db, products := newTestService(t)
+		products.add(1, 10, 5)
+		from, until := now.Add(tt.from), now.Add(tt.until)
+		products.update(1, func(p *dto.ProductResponse) {
+			p.AvailableFrom, p.AvailableUntil = &from, &until
+		})
+
+		_, err := s.CreateOrder(context.Background(), dto.CreateOrderRequest{UserID: 1, ProductID: 1})
+		if tt.available {
+			if err != nil {
+				t.Errorf("%s: CreateOrder: %v", tt.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrProductUnavailable) {
+			t.Errorf("%s: err = %v, want ErrProductUnavailable (409)", tt.name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("%s: message = %q, want it to contain %q", tt.name, err.Error(), tt.message)
+		}
+		if n := countOrders(t, db); n != 0 {
+			t.Errorf("%s: %d orders written, want none", tt.name, n)
+		}
+		if stock := products.stockOf(1); stock != 5 {
+			t.Errorf("%s: stock = %d, want it untouched", tt.name, stock)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/dto"
+	"order-service/models"
+	"time"
+)
+
+// MaxBatchSize caps how many orders a single batch create may contain
+const MaxBatchSize = 100
+
+// errBatchInvalid is returned when at least one entry of a batch fails
+// validation or enrichment, in which case nothing is inserted
+var errBatchInvalid = errors.New("batch contains invalid orders")
+
+// CreateOrdersBatch validates and enriches every request, fetching each
+// distinct user and product only once, then inserts all orders in a single
+// transaction so the batch is atomic. The per-index results describe either
+// the created order or why that entry was rejected.
+//
+// Stock is reserved per product for the whole batch before the insert, as
+// CreateOrder does for one order. If a product can't cover its entries they
+// are rejected and the reservations already made are released; a failed
+// insert releases them all.
+func (s *OrderService) CreateOrdersBatch(ctx context.Context, reqs []dto.CreateOrderRequest) ([]dto.BatchOrderResult, error) {
+	results := make([]dto.BatchOrderResult, len(reqs))
+	var userIDs, productIDs []uint
+	seenUsers := make(map[uint]bool)
+	seenProducts := make(map[uint]bool)
+
+	for i, req := range reqs {
+		results[i].Index = i
+		if req.UserID == 0 || req.ProductID == 0 {
+			continue
+		}
+		if !seenUsers[req.UserID] {
+			seenUsers[req.UserID] = true
+			userIDs = append(userIDs, req.UserID)
+		}
+		if !seenProducts[req.ProductID] {
+			seenProducts[req.ProductID] = true
+			productIDs = append(productIDs, req.ProductID)
+		}
+	}
+
+	users, userErrs := s.fetchUsers(ctx, userIDs)
+	products, productErrs := s.fetchProducts(ctx, productIDs)
+
+	now := time.Now()
+	unavailable := make(map[uint]error)
+	for id, product := range products {
+		if err := checkAvailability(product, now); err != nil {
+			unavailable[id] = err
+		}
+	}
+
+	// An order is paid in one currency, so every entry must be priced in
+	// the currency of the first product in the batch
+	var currency string
+	for _, req := range reqs {
+		if product := products[req.ProductID]; product != nil {
+			currency = product.Currency
+			break
+		}
+	}
+
+	invalid := false
+	for i, req := range reqs {
+		switch {
+		case req.UserID == 0 || req.ProductID == 0:
+			results[i].Error = "valid user_id and product_id are required"
+		case req.Quantity != nil && *req.Quantity == 0:
+			results[i].Error = "quantity must be at least 1"
+		case userErrs[req.UserID] != nil:
+			results[i].Error = userErrs[req.UserID].Error()
+		case productErrs[req.ProductID] != nil:
+			results[i].Error = productErrs[req.ProductID].Error()
+		case unavailable[req.ProductID] != nil:
+			results[i].Error = unavailable[req.ProductID].Error()
+		case products[req.ProductID].Currency != currency:
+			results[i].Error = fmt.Sprintf("product is priced in %s but the batch is in %s", products[req.ProductID].Currency, currency)
+		default:
+			continue
+		}
+		invalid = true
+	}
+	if invalid {
+		return results, errBatchInvalid
+	}
+
+	quantities := make(map[uint]uint, len(productIDs))
+	for _, req := range reqs {
+		quantities[req.ProductID] += req.QuantityOrDefault()
+	}
+	if failed, err := s.reserveStocks(ctx, productIDs, quantities); err != nil {
+		if !errors.Is(err, ErrInsufficientStock) {
+			return nil, fmt.Errorf("failed to reserve stock: %w", err)
+		}
+		for i, req := range reqs {
+			if req.ProductID == failed {
+				results[i].Error = err.Error()
+			}
+		}
+		return results, errBatchInvalid
+	}
+
+	orders := make([]models.Order, len(reqs))
+	for i, req := range reqs {
+		quantity := req.QuantityOrDefault()
+		orders[i] = models.Order{
+			UserID:     req.UserID,
+			ProductID:  req.ProductID,
+			Quantity:   quantity,
+			TotalPrice: orderTotal(products[req.ProductID].Price, quantity),
+			Currency:   currency,
+			Status:     models.StatusPending,
+		}
+	}
+
+	if err := s.insertOrders(ctx, orders); err != nil {
+		s.releaseStocks(ctx, productIDs, quantities)
+		return nil, err
+	}
+
+	for i := range orders {
+		order := &orders[i]
+		results[i].Order = toDetailsResponse(order, users[order.UserID], products[order.ProductID])
+		s.publishOrderCreated(ctx, order)
+	}
+
+	return results, nil
+}
+
+// IsBatchInvalid reports whether err means the batch was rejected because of
+// invalid entries rather than an internal failure
+func IsBatchInvalid(err error) bool {
+	return errors.Is(err, errBatchInvalid)
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"order-service/logging"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	// maxDBWriteAttempts bounds how many times a transient write failure is retried
+	maxDBWriteAttempts = 3
+	// dbRetryBaseDelay is the first backoff delay; it doubles on each retry
+	dbRetryBaseDelay = 50 * time.Millisecond
+)
+
+// Postgres SQLSTATE codes that indicate the transaction can safely be retried
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// isTransientDBError reports whether err is a serialization failure or a
+// deadlock, both of which usually succeed when retried
+func isTransientDBError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+	}
+	return false
+}
+
+// withDBRetry runs fn and retries it with exponential backoff while it fails
+// with a transient database error. Other errors are returned immediately, and
+// retrying stops as soon as ctx is done.
+func withDBRetry(ctx context.Context, fn func() error) error {
+	var err error
+	delay := dbRetryBaseDelay
+	for attempt := 1; attempt <= maxDBWriteAttempts; attempt++ {
+		err = fn()
+		if err == nil || !isTransientDBError(err) {
+			return err
+		}
+		if attempt < maxDBWriteAttempts {
+			logging.PrintfContext(ctx, "Transient database error (attempt %d/%d), retrying in %s: %v", attempt, maxDBWriteAttempts, delay, err)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			delay *= 2
+		}
+	}
+	return err
+}
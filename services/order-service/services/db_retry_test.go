@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"order-service/dto"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestWithDBRetryRecoversFromTransientErrors(t *testing.T) {
+	for _, code := range []string{pgSerializationFailure, pgDeadlockDetected} {
+		calls := 0
+		err := withDBRetry(context.Background(), func() error {
+			calls++
+			if calls < maxDBWriteAttempts {
+				return &pgconn.PgError{Code: code}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("code %s: err = %v, want nil", code, err)
+		}
+		if calls != maxDBWriteAttempts {
+			t.Errorf("code %s: %d calls, want %d", code, calls, maxDBWriteAttempts)
+		}
+	}
+}
+
+func TestWithDBRetryFailsFastOnOtherErrors(t *testing.T) {
+	for _, want := range []error{errors.New("disk full"), &pgconn.PgError{Code: "23505"}} {
+		calls := 0
+		err := withDBRetry(context.Background(), func() error {
+			calls++
+			return want
+		})
+		if !errors.Is(err, want) {
+			t.Errorf("err = %v, want %v", err, want)
+		}
+		if calls != 1 {
+			t.Errorf("%v: %d calls, want 1", want, calls)
+		}
+	}
+}
+
+func TestWithDBRetryGivesUp(t *testing.T) {
+	calls := 0
+	err := withDBRetry(context.Background(), func() error {
+		calls++
+		return &pgconn.PgError{Code: pgDeadlockDetected}
+	})
+	if !isTransientDBError(err) {
+		t.Errorf("err = %v, want the last deadlock", err)
+	}
+	if calls != maxDBWriteAttempts {
+		t.Errorf("%d calls, want %d", calls, maxDBWriteAttempts)
+	}
+}
+
+func TestWithDBRetryStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := withDBRetry(ctx, func() error {
+		calls++
+		cancel()
+		return &pgconn.PgError{Code: pgSerializationFailure}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if calls != 1 {
+		t.Errorf("%d calls, want 1", calls)
+	}
+}
+
+func TestCreateOrderRetriesTransientInsert(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 5)
+
+	// The first insert deadlocks, as if Postgres had picked it as the victim
+	failures := 1
+	err := db.Callback().Create().Before("gorm:create").Register("test:deadlock", func(tx *gorm.DB) {
+		if failures > 0 {
+			failures--
+			tx.AddError(&pgconn.PgError{Code: pgDeadlockDetected})
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.CreateOrder(context.Background(), dto.CreateOrderRequest{UserID: 1, ProductID: 1}); err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	if failures != 0 {
+		t.Fatal("the insert never deadlocked")
+	}
+	if n := countOrders(t, db); n != 1 {
+		t.Errorf("%d orders, want 1", n)
+	}
+	if stock := products.stockOf(1); stock != 4 {
+		t.Errorf("stock = %d, want 4", stock)
+	}
+}
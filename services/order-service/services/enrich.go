@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"order-service/clients"
+	"order-service/dto"
+	"order-service/models"
+	"sync"
+)
+
+// runBounded calls fn once per ID with at most limit calls in flight
+func runBounded(ids []uint, limit int, fn func(id uint)) {
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id uint) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(id)
+		}(id)
+	}
+	wg.Wait()
+}
+
+// fetchUsers fetches the given users through the bounded worker pool,
+// returning the users found and the errors keyed by user ID
+func (s *OrderService) fetchUsers(ctx context.Context, ids []uint) (map[uint]*dto.UserResponse, map[uint]error) {
+	users := make(map[uint]*dto.UserResponse, len(ids))
+	errs := make(map[uint]error)
+	var mu sync.Mutex
+
+	runBounded(ids, s.enrichConcurrency, func(id uint) {
+		user, err := s.fetchUser(ctx, id)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[id] = err
+			return
+		}
+		users[id] = user
+	})
+
+	return users, errs
+}
+
+// productBatchSize is the most IDs sent in one batch fetch
+const productBatchSize = clients.MaxBatchIDs
+
+// fetchProducts fetches the given products, returning the products found and
+// the errors keyed by product ID. More than one product is fetched through
+// the product service's batch endpoint; a single product through the bounded
+// worker pool like users.
+func (s *OrderService) fetchProducts(ctx context.Context, ids []uint) (map[uint]*dto.ProductResponse, map[uint]error) {
+	if len(ids) > 1 {
+		return s.fetchProductsBatch(ctx, ids)
+	}
+
+	products := make(map[uint]*dto.ProductResponse, len(ids))
+	errs := make(map[uint]error)
+	var mu sync.Mutex
+
+	runBounded(ids, s.enrichConcurrency, func(id uint) {
+		product, err := s.fetchProduct(ctx, id)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[id] = err
+			return
+		}
+		products[id] = product
+	})
+
+	return products, errs
+}
+
+// fetchProductsBatch fetches products with GET /products/batch, in chunks of
+// productBatchSize. A failed chunk reports its error for every ID in it, and
+// IDs the product service doesn't return are reported as not found.
+func (s *OrderService) fetchProductsBatch(ctx context.Context, ids []uint) (map[uint]*dto.ProductResponse, map[uint]error) {
+	products := make(map[uint]*dto.ProductResponse, len(ids))
+	errs := make(map[uint]error)
+
+	for start := 0; start < len(ids); start += productBatchSize {
+		chunk := ids[start:min(start+productBatchSize, len(ids))]
+		found, err := s.fetchProductChunk(ctx, chunk)
+		for _, id := range chunk {
+			switch {
+			case err != nil:
+				errs[id] = err
+			case found[id] == nil:
+				errs[id] = clients.NewError("product", id, http.StatusNotFound, nil)
+			default:
+				products[id] = found[id]
+			}
+		}
+	}
+
+	return products, errs
+}
+
+// fetchProductChunk performs one batch fetch, remembering each product as
+// the last known copy for stale reads
+func (s *OrderService) fetchProductChunk(ctx context.Context, ids []uint) (map[uint]*dto.ProductResponse, error) {
+	found, err := s.products.GetProducts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, product := range found {
+		s.productCache.put(product)
+	}
+	return found, nil
+}
+
+// GetOrdersByUser returns every order placed by a user with its user and
+// product details. The user is fetched once and each distinct product at most
+// once, however many orders share it.
+func (s *OrderService) GetOrdersByUser(ctx context.Context, userID uint) ([]dto.OrderWithDetailsResponse, error) {
+	var orders []models.Order
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	user, err := s.fetchUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var productIDs []uint
+	seen := make(map[uint]bool)
+	for _, order := range orders {
+		if !seen[order.ProductID] {
+			seen[order.ProductID] = true
+			productIDs = append(productIDs, order.ProductID)
+		}
+	}
+
+	products, errs := s.fetchProducts(ctx, productIDs)
+	for _, id := range productIDs {
+		if err := errs[id]; err != nil {
+			return nil, err
+		}
+	}
+
+	responses := make([]dto.OrderWithDetailsResponse, 0, len(orders))
+	for i := range orders {
+		responses = append(responses, *toDetailsResponse(&orders[i], user, products[orders[i].ProductID]))
+	}
+	return responses, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// runTracked runs n calls through runBounded, each holding its slot for
+// hold, and returns the most calls seen in flight at once
+func runTracked(n, limit int, hold time.Duration) int32 {
+	ids := make([]uint, n)
+	for i := range ids {
+		ids[i] = uint(i + 1)
+	}
+
+	var inFlight, peak atomic.Int32
+	runBounded(ids, limit, func(id uint) {
+		current := inFlight.Add(1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(hold)
+		inFlight.Add(-1)
+	})
+	return peak.Load()
+}
+
+func TestRunBoundedLimitsConcurrency(t *testing.T) {
+	for _, limit := range []int{1, 3, 8} {
+		if peak := runTracked(40, limit, 5*time.Millisecond); peak != int32(limit) {
+			t.Errorf("limit %d: %d calls in flight at once, want exactly %d", limit, peak, limit)
+		}
+	}
+}
+
+func TestRunBoundedCallsEveryID(t *testing.T) {
+	var calls [10]atomic.Int32
+	ids := []uint{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	runBounded(ids, 4, func(id uint) { calls[id].Add(1) })
+	for id := range calls {
+		if n := calls[id].Load(); n != 1 {
+			t.Errorf("id %d called %d times, want 1", id, n)
+		}
+	}
+}
+
+func BenchmarkRunBounded(b *testing.B) {
+	var peak int32
+	for i := 0; i < b.N; i++ {
+		peak = max(peak, runTracked(100, 10, time.Millisecond))
+	}
+	if peak > 10 {
+		b.Fatalf("%d calls in flight, limit is 10", peak)
+	}
+	b.ReportMetric(float64(peak), "peak-in-flight")
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"order-service/clients"
+	"order-service/config"
+	"order-service/dto"
+	"order-service/events"
+	"order-service/models"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeUsers serves every positive user ID
+type fakeUsers struct{}
+
+func (fakeUsers) GetUser(ctx context.Context, id uint) (*dto.UserResponse, error) {
+	return &dto.UserResponse{ID: id, Name: fmt.Sprintf("user %d", id), Email: fmt.Sprintf("user%d@example.com", id)}, nil
+}
+
+func (fakeUsers) Ping(ctx context.Context) error { return nil }
+
+func (fakeUsers) BreakerState() string { return "closed" }
+
+// fakeProducts is an in-memory product service that tracks stock the way the
+// real one does, refusing adjustments that would take it below zero
+type fakeProducts struct {
+	mu       sync.Mutex
+	products map[uint]*dto.ProductResponse
+	stock    map[uint]int
+}
+
+func newFakeProducts() *fakeProducts {
+	return &fakeProducts{products: make(map[uint]*dto.ProductResponse), stock: make(map[uint]int)}
+}
+
+// add registers a product priced at price with stock units available
+func (p *fakeProducts) add(id uint, price float64, stock int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.products[id] = &dto.ProductResponse{ID: id, Name: fmt.Sprintf("product %d", id), Price: price, Currency: "USD"}
+	p.stock[id] = stock
+}
+
+// update changes a registered product's details
+func (p *fakeProducts) update(id uint, change func(*dto.ProductResponse)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	change(p.products[id])
+}
+
+// stockOf returns a product's current stock
+func (p *fakeProducts) stockOf(id uint) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stock[id]
+}
+
+func (p *fakeProducts) GetProduct(ctx context.Context, id uint) (*dto.ProductResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	product, ok := p.products[id]
+	if !ok {
+		return nil, clients.NewError("product", id, http.StatusNotFound, nil)
+	}
+	copied := *product
+	return &copied, nil
+}
+
+func (p *fakeProducts) GetProducts(ctx context.Context, ids []uint) (map[uint]*dto.ProductResponse, error) {
+	found := make(map[uint]*dto.ProductResponse)
+	for _, id := range ids {
+		if product, err := p.GetProduct(ctx, id); err == nil {
+			found[id] = product
+		}
+	}
+	return found, nil
+}
+
+func (p *fakeProducts) AdjustStock(ctx context.Context, id uint, delta int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.products[id]; !ok {
+		return clients.NewError("product", id, http.StatusNotFound, nil)
+	}
+	if p.stock[id]+delta < 0 {
+		return fmt.Errorf("%w for product %d", clients.ErrInsufficientStock, id)
+	}
+	p.stock[id] += delta
+	return nil
+}
+
+func (p *fakeProducts) Ping(ctx context.Context) error { return nil }
+
+func (p *fakeProducts) BreakerState() string { return "closed" }
+
+// newTestDB opens a fresh in-memory SQLite database with the order schema
+func newTestDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:         logger.Discard,
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	// Every connection to :memory: is a separate database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.Order{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// newTestService returns an order service over a fresh database and an
+// in-memory product service
+func newTestService(t *testing.T) (*OrderService, *gorm.DB, *fakeProducts) {
+	t.Helper()
+	db := newTestDB(t)
+	products := newFakeProducts()
+	cfg := config.Config{EnrichmentConcurrency: 4}
+	return NewOrderService(db, cfg, fakeUsers{}, products, events.NopPublisher{}), db, products
+}
+
+// countOrders returns the number of order rows that aren't soft-deleted
+func countOrders(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Model(&models.Order{}).Count(&n).Error; err != nil {
+		t.Fatalf("count orders: %v", err)
+	}
+	return n
+}
+
+// insertOrder writes an order row directly, bypassing stock reservation
+func insertOrder(t *testing.T, db *gorm.DB, order models.Order) models.Order {
+	t.Helper()
+	if order.Quantity == 0 {
+		order.Quantity = 1
+	}
+	if order.Status == "" {
+		order.Status = models.StatusPending
+	}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("insert order: %v", err)
+	}
+	return order
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"math"
+	"order-service/dto"
+	"order-service/models"
+
+	"gorm.io/gorm"
+)
+
+// daysPerMonth is the average month length used to express order frequency
+const daysPerMonth = 30.44
+
+// GetLifetimeValue computes a user's lifetime value from their delivered
+// orders: the total spent, how many orders and how often, and the average
+// order value. Users without delivered orders get zeros. The user must exist
+// in the user service.
+func (s *OrderService) GetLifetimeValue(ctx context.Context, userID uint) (*dto.LifetimeValueResponse, error) {
+	if _, err := s.fetchUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	delivered := func() *gorm.DB {
+		return s.db.WithContext(ctx).Model(&models.Order{}).
+			Where("user_id = ? AND status = ?", userID, models.StatusDelivered)
+	}
+
+	var agg struct {
+		Count int64
+		Total float64
+	}
+	if err := delivered().Select("COUNT(*) AS count, COALESCE(SUM(total_price), 0) AS total").Scan(&agg).Error; err != nil {
+		return nil, err
+	}
+
+	result := &dto.LifetimeValueResponse{
+		UserID:          userID,
+		CompletedOrders: agg.Count,
+		LifetimeValue:   roundCents(agg.Total),
+	}
+	if agg.Count == 0 {
+		return result, nil
+	}
+
+	result.AverageOrderValue = roundCents(agg.Total / float64(agg.Count))
+
+	var first, last models.Order
+	if err := delivered().Order("created_at, id").First(&first).Error; err != nil {
+		return nil, err
+	}
+	if err := delivered().Order("created_at DESC, id DESC").First(&last).Error; err != nil {
+		return nil, err
+	}
+	result.FirstOrderAt = &first.CreatedAt
+	result.LastOrderAt = &last.CreatedAt
+
+	// Frequency is over at least one month so a burst of orders on a single
+	// day doesn't read as an enormous monthly rate
+	months := max(last.CreatedAt.Sub(first.CreatedAt).Hours()/24/daysPerMonth, 1)
+	result.OrdersPerMonth = math.Round(float64(agg.Count)/months*100) / 100
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"order-service/models"
+	"testing"
+	"time"
+)
+
+func TestGetLifetimeValue(t *testing.T) {
+	s, db, _ := newTestService(t)
+	first := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, order := range []models.Order{
+		{UserID: 1, TotalPrice: 20.10, Status: models.StatusDelivered, CreatedAt: first},
+		{UserID: 1, TotalPrice: 35.25, Status: models.StatusDelivered, CreatedAt: first.AddDate(0, 1, 0)},
+		{UserID: 1, TotalPrice: 44.65, Status: models.StatusDelivered, CreatedAt: first.AddDate(0, 2, 0)},
+		{UserID: 1, TotalPrice: 99, Status: models.StatusCancelled, CreatedAt: first.AddDate(0, 1, 5)},
+		{UserID: 1, TotalPrice: 15, Status: models.StatusShipped, CreatedAt: first.AddDate(0, 2, 5)},
+		{UserID: 2, TotalPrice: 500, Status: models.StatusDelivered, CreatedAt: first},
+	} {
+		order.ProductID = 1
+		insertOrder(t, db, order)
+	}
+
+	ltv, err := s.GetLifetimeValue(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetLifetimeValue: %v", err)
+	}
+	if ltv.CompletedOrders != 3 || ltv.LifetimeValue != 100 || ltv.AverageOrderValue != 33.33 {
+		t.Errorf("ltv = %+v, want 3 orders worth 100 averaging 33.33", ltv)
+	}
+	// 3 orders over the 59 days between the first and last is 1.55 a month
+	if ltv.OrdersPerMonth != 1.55 {
+		t.Errorf("orders per month = %v, want 1.55", ltv.OrdersPerMonth)
+	}
+	if ltv.FirstOrderAt == nil || !ltv.FirstOrderAt.Equal(first) || ltv.LastOrderAt == nil || !ltv.LastOrderAt.Equal(first.AddDate(0, 2, 0)) {
+		t.Errorf("first/last order = %v/%v, want %s and %s", ltv.FirstOrderAt, ltv.LastOrderAt, first, first.AddDate(0, 2, 0))
+	}
+}
+
+func TestGetLifetimeValueWithoutCompletedOrders(t *testing.T) {
+	s, db, _ := newTestService(t)
+	insertOrder(t, db, models.Order{UserID: 3, ProductID: 1, TotalPrice: 40, Status: models.StatusPaid})
+
+	ltv, err := s.GetLifetimeValue(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("GetLifetimeValue: %v", err)
+	}
+	if ltv.UserID != 3 || ltv.CompletedOrders != 0 || ltv.LifetimeValue != 0 || ltv.AverageOrderValue != 0 || ltv.OrdersPerMonth != 0 {
+		t.Errorf("ltv = %+v, want zeros", ltv)
+	}
+	if ltv.FirstOrderAt != nil || ltv.LastOrderAt != nil {
+		t.Errorf("first/last order = %v/%v, want none", ltv.FirstOrderAt, ltv.LastOrderAt)
+	}
+}
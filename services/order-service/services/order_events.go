@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"order-service/events"
+	"order-service/models"
+	"strconv"
+	"time"
+)
+
+// publishOrderCreated emits order.created for a stored order. A failure is
+// logged and otherwise ignored: the order exists whether or not consumers
+// hear about it.
+func (s *OrderService) publishOrderCreated(ctx context.Context, order *models.Order) {
+	err := s.events.Publish(ctx, events.Event{
+		Name:       events.OrderCreated,
+		OccurredAt: time.Now().UTC(),
+		Key:        strconv.FormatUint(uint64(order.ID), 10),
+		Data: events.OrderCreatedData{
+			OrderID:   order.ID,
+			UserID:    order.UserID,
+			ProductID: order.ProductID,
+			Quantity:  order.Quantity,
+			Total:     order.TotalPrice,
+			Currency:  order.Currency,
+			CreatedAt: order.CreatedAt.UTC(),
+		},
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to publish event", "event", events.OrderCreated, "order_id", order.ID, "error", err)
+	}
+}
@@ -1,166 +1,333 @@
 package services
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
+	"order-service/apperror"
+	"order-service/clients"
+	"order-service/config"
 	"order-service/dto"
+	"order-service/events"
 	"order-service/models"
-	"os"
+	"sync"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 // OrderService handles order business logic
 type OrderService struct {
-	db *gorm.DB
+	db                *gorm.DB
+	users             clients.UserClient
+	products          clients.ProductClient
+	enrichConcurrency int
+	shippingRates     []config.ShippingRate
+	productCache      *productCache
+	staleFallback     bool
+	events            events.Publisher
 }
 
-// NewOrderService creates a new order service
-func NewOrderService(db *gorm.DB) *OrderService {
-	return &OrderService{db: db}
+// NewOrderService creates a new order service that fetches users and
+// products through the given clients and announces order lifecycle events
+// through publisher
+func NewOrderService(db *gorm.DB, cfg config.Config, users clients.UserClient, products clients.ProductClient, publisher events.Publisher) *OrderService {
+	rates := cfg.ShippingRates
+	if len(rates) == 0 {
+		rates = defaultShippingRates
+	}
+	return &OrderService{
+		db:                db,
+		users:             users,
+		products:          products,
+		enrichConcurrency: cfg.EnrichmentConcurrency,
+		shippingRates:     rates,
+		productCache:      newProductCache(),
+		staleFallback:     cfg.EnrichmentStaleFallback,
+		events:            publisher,
+	}
 }
 
-// CreateOrder creates a new order by fetching data from both services
-func (s *OrderService) CreateOrder(req dto.CreateOrderRequest) (*dto.OrderWithDetailsResponse, error) {
-	// Fetch user data from user service
-	user, err := s.fetchUser(req.UserID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch user: %v", err)
-	}
+// CreateOrder creates a new order by fetching data from both services.
+//
+// Stock for the ordered quantity is reserved in the product service before
+// the order row is written, and the order is refused if the product can't
+// cover it. The reservation and the insert are not one transaction: if the
+// insert fails, the reservation is compensated by adding the stock back.
+// Compensation is best effort, so a crash or product service outage between
+// the two steps can leave stock decremented without an order (never the
+// reverse); such failures are logged for manual correction.
+//
+// The user and product are fetched and stock reserved before the database
+// transaction opens, so no network call holds it open.
+func (s *OrderService) CreateOrder(ctx context.Context, req dto.CreateOrderRequest) (*dto.OrderWithDetailsResponse, error) {
+	// Fetch user and product data concurrently; both calls always run to
+	// completion so each response body is drained and closed
+	var (
+		wg                  sync.WaitGroup
+		user                *dto.UserResponse
+		product             *dto.ProductResponse
+		userErr, productErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		user, userErr = s.fetchUser(ctx, req.UserID)
+	}()
+	go func() {
+		defer wg.Done()
+		product, productErr = s.fetchProduct(ctx, req.ProductID)
+	}()
+	wg.Wait()
 
-	// Fetch product data from product service
-	product, err := s.fetchProduct(req.ProductID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch product: %v", err)
+	if userErr != nil {
+		return nil, userErr
+	}
+	if productErr != nil {
+		return nil, productErr
+	}
+	if err := checkAvailability(product, time.Now()); err != nil {
+		return nil, err
 	}
 
-	// Create order in database
-	order := models.Order{
-		UserID:    req.UserID,
-		ProductID: req.ProductID,
+	quantity := req.QuantityOrDefault()
+	if err := s.adjustStock(ctx, req.ProductID, -int(quantity)); err != nil {
+		return nil, fmt.Errorf("failed to reserve stock: %w", err)
 	}
 
-	if err := s.db.Create(&order).Error; err != nil {
+	// Create order in database
+	// The total is stored so historical orders keep the price at purchase time
+	orders := []models.Order{{
+		UserID:     req.UserID,
+		ProductID:  req.ProductID,
+		Quantity:   quantity,
+		TotalPrice: orderTotal(product.Price, quantity),
+		Currency:   product.Currency,
+		Status:     models.StatusPending,
+	}}
+	if err := s.insertOrders(ctx, orders); err != nil {
+		s.releaseStock(ctx, req.ProductID, quantity)
 		return nil, err
 	}
+	order := &orders[0]
+	s.publishOrderCreated(ctx, order)
 
 	// Return order with details
-	return &dto.OrderWithDetailsResponse{
-		ID:        order.ID,
-		UserID:    order.UserID,
-		ProductID: order.ProductID,
-		User:      user,
-		Product:   product,
-		CreatedAt: order.CreatedAt,
-		UpdatedAt: order.UpdatedAt,
-	}, nil
+	return toDetailsResponse(order, user, product), nil
+}
+
+// insertOrders is the database write path for new orders. Every step runs in
+// one transaction, so a failure in any of them rolls back the whole write and
+// no order row is left behind. The transaction is retried as a whole on
+// transient errors.
+func (s *OrderService) insertOrders(ctx context.Context, orders []models.Order) error {
+	return withDBRetry(ctx, func() error {
+		return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return tx.Create(&orders).Error
+		})
+	})
 }
 
 // GetOrder retrieves an order with full user and product details
-func (s *OrderService) GetOrder(orderID uint) (*dto.OrderWithDetailsResponse, error) {
+func (s *OrderService) GetOrder(ctx context.Context, orderID uint) (*dto.OrderWithDetailsResponse, error) {
 	var order models.Order
-	if err := s.db.First(&order, orderID).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&order, orderID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("order not found")
+			return nil, apperror.NotFound("order not found")
 		}
 		return nil, err
 	}
 
 	// Fetch fresh data from services
-	user, err := s.fetchUser(order.UserID)
+	user, err := s.fetchUser(ctx, order.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch user: %v", err)
+		return nil, err
 	}
 
-	product, err := s.fetchProduct(order.ProductID)
+	product, err := s.fetchProductForRead(ctx, order.ProductID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch product: %v", err)
+		return nil, err
 	}
 
-	return &dto.OrderWithDetailsResponse{
-		ID:        order.ID,
-		UserID:    order.UserID,
-		ProductID: order.ProductID,
-		User:      user,
-		Product:   product,
-		CreatedAt: order.CreatedAt,
-		UpdatedAt: order.UpdatedAt,
-	}, nil
+	return toDetailsResponse(&order, user, product), nil
+}
+
+// GetAllOrders retrieves a page of the orders matching the filter
+func (s *OrderService) GetAllOrders(ctx context.Context, filter dto.OrderFilter, page dto.Pagination) (*dto.OrderListResponse, error) {
+	return s.SearchOrders(ctx, filter, "", page)
 }
 
-// GetAllOrders retrieves all orders
-func (s *OrderService) GetAllOrders() ([]dto.OrderResponse, error) {
-	var orders []models.Order
-	if err := s.db.Find(&orders).Error; err != nil {
+// UpdateOrder changes the product and/or quantity of a pending order,
+// recomputing its total and currency from the current product. callerID is
+// the authenticated user, who must own the order, or 0 when authentication
+// is disabled.
+//
+// The stock reservation follows the change. Extra units are reserved before
+// the row is written and the write is refused if they can't be covered;
+// units no longer needed are released after it. A failed write releases the
+// extra units again, with the same best-effort compensation as CreateOrder.
+func (s *OrderService) UpdateOrder(ctx context.Context, orderID, callerID uint, req dto.UpdateOrderRequest) (*dto.OrderResponse, error) {
+	var order models.Order
+	if err := s.db.WithContext(ctx).First(&order, orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("order not found")
+		}
 		return nil, err
 	}
-
-	var responses []dto.OrderResponse
-	for _, order := range orders {
-		responses = append(responses, dto.OrderResponse{
-			ID:        order.ID,
-			UserID:    order.UserID,
-			ProductID: order.ProductID,
-			CreatedAt: order.CreatedAt,
-			UpdatedAt: order.UpdatedAt,
-		})
+	if err := checkOwner(&order, callerID); err != nil {
+		return nil, err
 	}
 
-	return responses, nil
-}
-
-// fetchUser fetches user data from user service
-func (s *OrderService) fetchUser(userID uint) (*dto.UserResponse, error) {
-	userServiceURL := os.Getenv("USER_SERVICE_URL")
-	if userServiceURL == "" {
-		userServiceURL = "http://localhost:8080"
+	if order.Status != models.StatusPending {
+		return nil, fmt.Errorf("%w: order is %s", ErrOrderNotPending, order.Status)
 	}
 
-	url := fmt.Sprintf("%s/users?id=%d", userServiceURL, userID)
+	prevProductID, prevQuantity := order.ProductID, order.Quantity
+	if req.ProductID != nil {
+		order.ProductID = *req.ProductID
+	}
+	if req.Quantity != nil {
+		order.Quantity = *req.Quantity
+	}
 
-	resp, err := http.Get(url)
+	product, err := s.fetchProduct(ctx, order.ProductID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch user: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	if err := checkAvailability(product, time.Now()); err != nil {
+		return nil, err
+	}
+	order.TotalPrice = orderTotal(product.Price, order.Quantity)
+	order.Currency = product.Currency
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("user service returned status %d", resp.StatusCode)
+	reserve, release := reservationChange(prevProductID, prevQuantity, order.ProductID, order.Quantity)
+	if reserve > 0 {
+		if err := s.adjustStock(ctx, order.ProductID, -int(reserve)); err != nil {
+			return nil, fmt.Errorf("failed to reserve stock: %w", err)
+		}
 	}
 
-	var user dto.UserResponse
-	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
-		return nil, fmt.Errorf("failed to decode user: %v", err)
+	// The status guard keeps a concurrent cancellation, which has already
+	// released the stock, from being overwritten
+	res := s.db.WithContext(ctx).Model(&order).
+		Where("status = ?", models.StatusPending).
+		Select("product_id", "quantity", "total_price", "currency").
+		Updates(&order)
+	if res.Error == nil && res.RowsAffected == 0 {
+		res.Error = ErrOrderNotPending
+	}
+	if res.Error != nil {
+		if reserve > 0 {
+			s.releaseStock(ctx, order.ProductID, reserve)
+		}
+		return nil, res.Error
+	}
+	if release > 0 {
+		s.releaseStock(ctx, prevProductID, release)
 	}
 
-	return &user, nil
+	response := toOrderResponse(&order)
+	return &response, nil
 }
 
-// fetchProduct fetches product data from product service
-func (s *OrderService) fetchProduct(productID uint) (*dto.ProductResponse, error) {
-	productServiceURL := os.Getenv("PRODUCT_SERVICE_URL")
-	if productServiceURL == "" {
-		productServiceURL = "http://localhost:8081"
+// DeleteOrder soft-deletes an order so it no longer appears in listings.
+// callerID is checked as in UpdateOrder. The stock of an order that hasn't
+// shipped is released.
+func (s *OrderService) DeleteOrder(ctx context.Context, orderID, callerID uint) error {
+	var order models.Order
+	if err := s.db.WithContext(ctx).First(&order, orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("order not found")
+		}
+		return err
+	}
+	if err := checkOwner(&order, callerID); err != nil {
+		return err
 	}
 
-	url := fmt.Sprintf("%s/products?id=%d", productServiceURL, productID)
+	// Only the request that deletes the row in the status it was read in
+	// releases the stock, so concurrent deletes can't release it twice
+	res := s.db.WithContext(ctx).Where("status = ?", order.Status).Delete(&order)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return apperror.Conflict("order was changed concurrently, try again")
+	}
+	if holdsStock(order.Status) {
+		s.releaseStock(ctx, order.ProductID, order.Quantity)
+	}
+	return nil
+}
 
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch product: %v", err)
+// checkOwner rejects a caller acting on another user's order. A callerID of
+// 0 means authentication is disabled and anyone may.
+func checkOwner(order *models.Order, callerID uint) error {
+	if callerID != 0 && order.UserID != callerID {
+		return apperror.Forbidden("order belongs to another user")
 	}
-	defer resp.Body.Close()
+	return nil
+}
+
+// orderTotal computes the total price of an order, rounded to cents
+func orderTotal(unitPrice float64, quantity uint) float64 {
+	return roundCents(unitPrice * float64(quantity))
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("product service returned status %d", resp.StatusCode)
+// toOrderResponse converts an Order model to the OrderResponse DTO
+func toOrderResponse(order *models.Order) dto.OrderResponse {
+	return dto.OrderResponse{
+		ID:         order.ID,
+		UserID:     order.UserID,
+		ProductID:  order.ProductID,
+		Quantity:   order.Quantity,
+		TotalPrice: order.TotalPrice,
+		Currency:   order.Currency,
+		Status:     order.Status,
+		CreatedAt:  order.CreatedAt,
+		UpdatedAt:  order.UpdatedAt,
 	}
+}
 
-	var product dto.ProductResponse
-	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
-		return nil, fmt.Errorf("failed to decode product: %v", err)
+// toDetailsResponse builds the detailed response for an order from its
+// fetched user and product
+func toDetailsResponse(order *models.Order, user *dto.UserResponse, product *dto.ProductResponse) *dto.OrderWithDetailsResponse {
+	return &dto.OrderWithDetailsResponse{
+		ID:               order.ID,
+		UserID:           order.UserID,
+		ProductID:        order.ProductID,
+		Quantity:         order.Quantity,
+		TotalPrice:       order.TotalPrice,
+		Currency:         order.Currency,
+		Status:           order.Status,
+		User:             user,
+		Product:          product,
+		TotalWeightGrams: product.WeightGrams * int(order.Quantity),
+		CreatedAt:        order.CreatedAt,
+		UpdatedAt:        order.UpdatedAt,
 	}
+}
 
-	return &product, nil
+// fetchUser fetches user data from user service
+func (s *OrderService) fetchUser(ctx context.Context, userID uint) (*dto.UserResponse, error) {
+	return s.users.GetUser(ctx, userID)
+}
+
+// fetchProduct fetches product data from product service, remembering it as
+// the last known copy for stale reads
+func (s *OrderService) fetchProduct(ctx context.Context, productID uint) (*dto.ProductResponse, error) {
+	product, err := s.products.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	s.productCache.put(product)
+	return product, nil
+}
+
+// BreakerStates reports the circuit breaker state of each downstream service
+func (s *OrderService) BreakerStates() map[string]string {
+	return map[string]string{
+		"user-service":    s.users.BreakerState(),
+		"product-service": s.products.BreakerState(),
+	}
 }
@@ -0,0 +1,384 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"order-service/apperror"
+	"order-service/clients"
+	"order-service/config"
+	"order-service/dto"
+	"order-service/events"
+	"order-service/models"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// failAfterInsert makes every insert fail once its rows have been written,
+// as a later step of the same transaction would
+func failAfterInsert(t *testing.T, db *gorm.DB) *int {
+	t.Helper()
+	calls := 0
+	err := db.Callback().Create().After("gorm:create").Register("test:fail_after_insert", func(tx *gorm.DB) {
+		calls++
+		if tx.Statement.RowsAffected == 0 {
+			t.Error("the insert wrote no rows before failing")
+		}
+		tx.AddError(errors.New("stock log write failed"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &calls
+}
+
+func TestCreateOrderRollsBackFailedTransaction(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 5)
+	calls := failAfterInsert(t, db)
+
+	_, err := s.CreateOrder(context.Background(), dto.CreateOrderRequest{UserID: 1, ProductID: 1})
+	if err == nil {
+		t.Fatal("CreateOrder succeeded, want the failure")
+	}
+	if *calls != 1 {
+		t.Errorf("insert ran %d times, want a non-transient failure not to be retried", *calls)
+	}
+
+	var n int64
+	if err := db.Unscoped().Model(&models.Order{}).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("%d order rows remain, want the insert rolled back", n)
+	}
+}
+
+func TestCreateOrdersBatchRollsBackFailedTransaction(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 5)
+	products.add(2, 20, 5)
+	failAfterInsert(t, db)
+
+	reqs := []dto.CreateOrderRequest{{UserID: 1, ProductID: 1}, {UserID: 2, ProductID: 2}}
+	if _, err := s.CreateOrdersBatch(context.Background(), reqs); err == nil || IsBatchInvalid(err) {
+		t.Fatalf("err = %v, want the insert failure", err)
+	}
+
+	var n int64
+	if err := db.Unscoped().Model(&models.Order{}).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("%d order rows remain, want none of the batch", n)
+	}
+}
+
+func TestUpdateOrder(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 5)
+	order := insertOrder(t, db, models.Order{UserID: 1, ProductID: 1, TotalPrice: 10})
+	quantity := uint(3)
+
+	got, err := s.UpdateOrder(context.Background(), order.ID, 0, dto.UpdateOrderRequest{Quantity: &quantity})
+	if err != nil {
+		t.Fatalf("UpdateOrder: %v", err)
+	}
+	if got.Quantity != 3 || got.TotalPrice != 30 {
+		t.Errorf("quantity %d, total %v, want 3 and 30", got.Quantity, got.TotalPrice)
+	}
+
+	if _, err := s.UpdateOrderStatus(context.Background(), order.ID, models.StatusPaid); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.UpdateOrder(context.Background(), order.ID, 0, dto.UpdateOrderRequest{Quantity: &quantity}); !errors.Is(err, ErrOrderNotPending) {
+		t.Errorf("update paid order: err = %v, want ErrOrderNotPending", err)
+	}
+}
+
+func TestOrderOwnership(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 5)
+	order := createOrder(t, s, 1, 1)
+	ctx := context.Background()
+	quantity := uint(3)
+
+	_, err := s.UpdateOrder(ctx, order.ID, 2, dto.UpdateOrderRequest{Quantity: &quantity})
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Code != apperror.CodeForbidden {
+		t.Errorf("update by another user: err = %v, want forbidden", err)
+	}
+	err = s.DeleteOrder(ctx, order.ID, 2)
+	if !errors.As(err, &appErr) || appErr.Code != apperror.CodeForbidden {
+		t.Errorf("delete by another user: err = %v, want forbidden", err)
+	}
+	if n := countOrders(t, db); n != 1 {
+		t.Errorf("%d orders, want the order kept", n)
+	}
+	if stock := products.stockOf(1); stock != 4 {
+		t.Errorf("stock = %d, want 4", stock)
+	}
+
+	// The owner, or any caller when authentication is disabled, may change it
+	if _, err := s.UpdateOrder(ctx, order.ID, 1, dto.UpdateOrderRequest{Quantity: &quantity}); err != nil {
+		t.Errorf("update by owner: %v", err)
+	}
+	if err := s.DeleteOrder(ctx, order.ID, 0); err != nil {
+		t.Errorf("delete without authentication: %v", err)
+	}
+}
+
+func TestDeleteOrderIsSoft(t *testing.T) {
+	s, db, _ := newTestService(t)
+	order := insertOrder(t, db, models.Order{UserID: 1, ProductID: 1})
+
+	if err := s.DeleteOrder(context.Background(), order.ID, 0); err != nil {
+		t.Fatalf("DeleteOrder: %v", err)
+	}
+	list, err := s.GetAllOrders(context.Background(), dto.OrderFilter{}, dto.Pagination{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 0 {
+		t.Errorf("GetAllOrders lists %d orders, want the deleted one excluded", list.Total)
+	}
+	var appErr *apperror.Error
+	if _, err := s.GetOrder(context.Background(), order.ID); !errors.As(err, &appErr) || appErr.Code != apperror.CodeNotFound {
+		t.Errorf("GetOrder after delete: err = %v, want not_found", err)
+	}
+
+	var row models.Order
+	if err := db.Unscoped().First(&row, order.ID).Error; err != nil {
+		t.Fatalf("Unscoped().First after delete: %v", err)
+	}
+	if !row.DeletedAt.Valid {
+		t.Error("deleted order has no deleted_at")
+	}
+}
+
+func TestGetOrderTotalWeight(t *testing.T) {
+	s, _, products := newTestService(t)
+	products.add(1, 10, 5)
+	products.update(1, func(p *dto.ProductResponse) { p.WeightGrams = 250 })
+	order := createOrder(t, s, 1, 3)
+
+	got, err := s.GetOrder(context.Background(), order.ID)
+	if err != nil {
+		t.Fatalf("GetOrder: %v", err)
+	}
+	if got.TotalWeightGrams != 750 {
+		t.Errorf("total weight = %d grams, want 750 for 3 x 250", got.TotalWeightGrams)
+	}
+}
+
+func TestCreateOrdersBatch(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 5)
+
+	results, err := s.CreateOrdersBatch(context.Background(), []dto.CreateOrderRequest{{UserID: 1, ProductID: 1}, {UserID: 2, ProductID: 1}})
+	if err != nil {
+		t.Fatalf("CreateOrdersBatch: %v", err)
+	}
+	for i, r := range results {
+		if r.Index != i || r.Order == nil || r.Order.ID == 0 {
+			t.Errorf("results[%d] = %+v, want the created order", i, r)
+		}
+	}
+	if n := countOrders(t, db); n != 2 {
+		t.Fatalf("%d orders, want 2", n)
+	}
+
+	results, err = s.CreateOrdersBatch(context.Background(), []dto.CreateOrderRequest{{UserID: 1, ProductID: 1}, {UserID: 1}})
+	if !IsBatchInvalid(err) {
+		t.Fatalf("err = %v, want the batch rejected", err)
+	}
+	if results[0].Error != "" || results[1].Error == "" {
+		t.Errorf("results = %+v, want only the second entry rejected", results)
+	}
+	if n := countOrders(t, db); n != 2 {
+		t.Errorf("%d orders, want nothing from the rejected batch", n)
+	}
+}
+
+func TestOrderCurrency(t *testing.T) {
+	s, _, products := newTestService(t)
+	products.add(1, 10, 5)
+	products.add(2, 20, 5)
+	products.update(2, func(p *dto.ProductResponse) { p.Currency = "EUR" })
+
+	order := createOrder(t, s, 1, 1)
+	if order.Currency != "USD" {
+		t.Errorf("order currency = %q, want the product's USD", order.Currency)
+	}
+	productID := uint(2)
+	updated, err := s.UpdateOrder(context.Background(), order.ID, 0, dto.UpdateOrderRequest{ProductID: &productID})
+	if err != nil {
+		t.Fatalf("UpdateOrder: %v", err)
+	}
+	if updated.Currency != "EUR" {
+		t.Errorf("currency after switching product = %q, want EUR", updated.Currency)
+	}
+
+	results, err := s.CreateOrdersBatch(context.Background(), []dto.CreateOrderRequest{{UserID: 1, ProductID: 1}, {UserID: 1, ProductID: 2}})
+	if !IsBatchInvalid(err) {
+		t.Fatalf("mixed currencies: err = %v, want the batch rejected", err)
+	}
+	if results[0].Error != "" || results[1].Error != "product is priced in EUR but the batch is in USD" {
+		t.Errorf("results = %+v, want only the EUR entry rejected", results)
+	}
+}
+
+func TestQueryAbortsWhenContextCancelled(t *testing.T) {
+	s, db, _ := newTestService(t)
+	insertOrder(t, db, models.Order{UserID: 1, ProductID: 1})
+
+	// Stand in for a slow query: count forever on the statement's context
+	// before the real query runs, so only cancellation can end it
+	err := db.Callback().Query().Before("gorm:query").Register("test:slow", func(tx *gorm.DB) {
+		var n int64
+		row := tx.Statement.ConnPool.QueryRowContext(tx.Statement.Context,
+			"WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT COUNT(*) FROM c")
+		if err := row.Scan(&n); err != nil {
+			tx.AddError(err)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = s.GetAllOrders(ctx, dto.OrderFilter{}, dto.Pagination{Limit: 10})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if elapsed > time.Second {
+		t.Errorf("query returned %s after cancellation, want it aborted promptly", elapsed)
+	}
+}
+
+// newHTTPTestService returns an order service over a fresh database that
+// calls the user and product services at the given URLs over HTTP
+func newHTTPTestService(t testing.TB, userURL, productURL string) *OrderService {
+	t.Helper()
+	cfg := config.Config{
+		HTTPClientTimeout:          2 * time.Second,
+		MaxIdleConnsPerHost:        10,
+		MaxDownstreamResponseBytes: 1 << 20,
+		BreakerThreshold:           5,
+		BreakerCooldown:            time.Minute,
+		EnrichmentConcurrency:      4,
+	}
+	return NewOrderService(newTestDB(t), cfg,
+		clients.NewUserHTTPClient(userURL, cfg),
+		clients.NewProductHTTPClient(productURL, cfg),
+		events.NopPublisher{})
+}
+
+// downstreamStub serves the user and product endpoints CreateOrder calls,
+// waiting delay before answering reads
+func downstreamStub(delay time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users":
+			time.Sleep(delay)
+			fmt.Fprintf(w, `{"id":%s,"name":"Ada","email":"ada@example.com"}`, r.URL.Query().Get("id"))
+		case "/products":
+			time.Sleep(delay)
+			fmt.Fprintf(w, `{"id":%s,"name":"Lamp","price":20}`, r.URL.Query().Get("id"))
+		case "/products/stock":
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func TestCreateOrderFetchesUserAndProductConcurrently(t *testing.T) {
+	const userDelay, productDelay = 200 * time.Millisecond, 300 * time.Millisecond
+	users := httptest.NewServer(downstreamStub(userDelay))
+	defer users.Close()
+	products := httptest.NewServer(downstreamStub(productDelay))
+	defer products.Close()
+	s := newHTTPTestService(t, users.URL, products.URL)
+
+	start := time.Now()
+	if _, err := s.CreateOrder(context.Background(), dto.CreateOrderRequest{UserID: 1, ProductID: 2}); err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	// Sequential fetches would take the sum, 500ms
+	if elapsed < productDelay || elapsed >= userDelay+productDelay-50*time.Millisecond {
+		t.Errorf("CreateOrder took %s, want about max(%s, %s)", elapsed, userDelay, productDelay)
+	}
+}
+
+// countConns counts the connections server accepts
+func countConns(server *httptest.Server) *atomic.Int32 {
+	var n atomic.Int32
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			n.Add(1)
+		}
+	}
+	return &n
+}
+
+func TestCreateOrderReusesConnections(t *testing.T) {
+	users := httptest.NewUnstartedServer(downstreamStub(0))
+	userConns := countConns(users)
+	users.Start()
+	defer users.Close()
+	products := httptest.NewUnstartedServer(downstreamStub(0))
+	productConns := countConns(products)
+	products.Start()
+	defer products.Close()
+	s := newHTTPTestService(t, users.URL, products.URL)
+
+	const orders = 1000
+	for i := 0; i < orders; i++ {
+		if _, err := s.CreateOrder(context.Background(), dto.CreateOrderRequest{UserID: 1, ProductID: 2}); err != nil {
+			t.Fatalf("CreateOrder %d: %v", i, err)
+		}
+	}
+
+	// Orders are created one at a time, so a pooled client needs a single
+	// connection to each service (plus a spare if one is briefly busy)
+	if n := userConns.Load(); n > 2 {
+		t.Errorf("%d connections dialed to the user service for %d orders, want them reused", n, orders)
+	}
+	if n := productConns.Load(); n > 2 {
+		t.Errorf("%d connections dialed to the product service for %d orders, want them reused", n, orders)
+	}
+}
+
+func BenchmarkCreateOrder(b *testing.B) {
+	users := httptest.NewUnstartedServer(downstreamStub(0))
+	userConns := countConns(users)
+	users.Start()
+	defer users.Close()
+	products := httptest.NewUnstartedServer(downstreamStub(0))
+	productConns := countConns(products)
+	products.Start()
+	defer products.Close()
+	s := newHTTPTestService(b, users.URL, products.URL)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.CreateOrder(context.Background(), dto.CreateOrderRequest{UserID: 1, ProductID: 2}); err != nil {
+			b.Fatalf("CreateOrder: %v", err)
+		}
+	}
+	b.ReportMetric(float64(userConns.Load()+productConns.Load()), "conns-dialed")
+}
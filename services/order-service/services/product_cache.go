@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"order-service/apperror"
+	"order-service/dto"
+	"sync"
+)
+
+// productCache keeps the last product successfully fetched for each ID so
+// reads can fall back to it while the product service is failing
+type productCache struct {
+	mu       sync.RWMutex
+	products map[uint]dto.ProductResponse
+}
+
+func newProductCache() *productCache {
+	return &productCache{products: make(map[uint]dto.ProductResponse)}
+}
+
+// put records product as the last known copy for its ID
+func (c *productCache) put(product *dto.ProductResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.products[product.ID] = *product
+}
+
+// get returns a copy of the last known product for id, if any
+func (c *productCache) get(id uint) (*dto.ProductResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	product, ok := c.products[id]
+	if !ok {
+		return nil, false
+	}
+	return &product, true
+}
+
+// fetchProductForRead fetches a product for a read-only response. When the
+// product service fails and stale fallback is enabled, the last cached copy
+// is returned with Stale set instead of the error. Writes never use this, so
+// prices are always taken from a fresh fetch.
+func (s *OrderService) fetchProductForRead(ctx context.Context, productID uint) (*dto.ProductResponse, error) {
+	product, err := s.fetchProduct(ctx, productID)
+	if err == nil || !s.staleFallback {
+		return product, err
+	}
+
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Code != apperror.CodeDownstream {
+		return nil, err
+	}
+	cached, ok := s.productCache.get(productID)
+	if !ok {
+		return nil, err
+	}
+	log.Printf("Serving stale product %d: %v", productID, err)
+	cached.Stale = true
+	return cached, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"order-service/apperror"
+	"order-service/clients"
+	"order-service/config"
+	"order-service/dto"
+	"order-service/events"
+	"order-service/models"
+	"testing"
+)
+
+// flakyProducts is a product service that can be switched off, failing every
+// fetch as unavailable
+type flakyProducts struct {
+	*fakeProducts
+	down bool
+}
+
+func (p *flakyProducts) GetProduct(ctx context.Context, id uint) (*dto.ProductResponse, error) {
+	if p.down {
+		return nil, clients.NewError("product", id, http.StatusServiceUnavailable, errors.New("connection refused"))
+	}
+	return p.fakeProducts.GetProduct(ctx, id)
+}
+
+func TestGetOrderStaleFallback(t *testing.T) {
+	for _, fallback := range []bool{true, false} {
+		db := newTestDB(t)
+		products := &flakyProducts{fakeProducts: newFakeProducts()}
+		products.add(1, 10, 5)
+		cfg := config.Config{EnrichmentConcurrency: 4, EnrichmentStaleFallback: fallback}
+		s := NewOrderService(db, cfg, fakeUsers{}, products, events.NopPublisher{})
+		order := insertOrder(t, db, models.Order{UserID: 1, ProductID: 1})
+		ctx := context.Background()
+
+		// A successful read caches the product
+		fresh, err := s.GetOrder(ctx, order.ID)
+		if err != nil {
+			t.Fatalf("GetOrder: %v", err)
+		}
+		if fresh.Product.Stale {
+			t.Error("fresh product marked stale")
+		}
+
+		products.down = true
+		got, err := s.GetOrder(ctx, order.ID)
+		if !fallback {
+			var appErr *apperror.Error
+			if !errors.As(err, &appErr) || appErr.Code != apperror.CodeDownstream {
+				t.Errorf("fallback off: err = %v, want the downstream error", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("fallback on: GetOrder: %v", err)
+		}
+		if !got.Product.Stale || got.Product.Name != "product 1" || got.Product.Price != 10 {
+			t.Errorf("fallback on: product = %+v, want the cached product marked stale", got.Product)
+		}
+	}
+}
+
+func TestGetOrderStaleFallbackNeedsCachedCopy(t *testing.T) {
+	db := newTestDB(t)
+	products := &flakyProducts{fakeProducts: newFakeProducts(), down: true}
+	cfg := config.Config{EnrichmentConcurrency: 4, EnrichmentStaleFallback: true}
+	s := NewOrderService(db, cfg, fakeUsers{}, products, events.NopPublisher{})
+	order := insertOrder(t, db, models.Order{UserID: 1, ProductID: 1})
+
+	var appErr *apperror.Error
+	if _, err := s.GetOrder(context.Background(), order.ID); !errors.As(err, &appErr) || appErr.Code != apperror.CodeDownstream {
+		t.Errorf("err = %v, want the downstream error when nothing is cached", err)
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"order-service/apperror"
+	"order-service/dto"
+	"order-service/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidSearch is returned when search criteria are invalid on their own
+// or contradict each other
+var ErrInvalidSearch = apperror.Validation("invalid search")
+
+// orderSorts is the allowlist of search sort keys and the ORDER BY each maps
+// to. A leading "-" sorts descending; id breaks ties so pages are stable.
+var orderSorts = map[string]string{
+	"id":           "id",
+	"-id":          "id DESC",
+	"created_at":   "created_at, id",
+	"-created_at":  "created_at DESC, id DESC",
+	"total_price":  "total_price, id",
+	"-total_price": "total_price DESC, id DESC",
+}
+
+// buildOrderQuery validates filter and sort and composes them into a single
+// query over orders. An empty sort orders by id.
+func buildOrderQuery(db *gorm.DB, filter dto.OrderFilter, sort string) (*gorm.DB, string, error) {
+	if sort == "" {
+		sort = "id"
+	}
+	orderBy, ok := orderSorts[sort]
+	if !ok {
+		return nil, "", fmt.Errorf("%w: unknown sort %q", ErrInvalidSearch, sort)
+	}
+	if filter.Status != "" {
+		if _, ok := allowedTransitions[filter.Status]; !ok {
+			return nil, "", fmt.Errorf("%w: %q", ErrInvalidStatus, filter.Status)
+		}
+	}
+	if filter.MinTotal != nil && filter.MaxTotal != nil && *filter.MinTotal > *filter.MaxTotal {
+		return nil, "", fmt.Errorf("%w: min_total must not exceed max_total", ErrInvalidSearch)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return nil, "", fmt.Errorf("%w: from must not be after to", ErrInvalidSearch)
+	}
+
+	query := db.Model(&models.Order{})
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.ProductID != nil {
+		query = query.Where("product_id = ?", *filter.ProductID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.MinTotal != nil {
+		query = query.Where("total_price >= ?", *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		query = query.Where("total_price <= ?", *filter.MaxTotal)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at <= ?", filter.To.UTC())
+	}
+	return query, orderBy, nil
+}
+
+// SearchOrders returns a page of the orders matching every given criterion,
+// in the requested sort order
+func (s *OrderService) SearchOrders(ctx context.Context, filter dto.OrderFilter, sort string, page dto.Pagination) (*dto.OrderListResponse, error) {
+	query, orderBy, err := buildOrderQuery(s.db.WithContext(ctx), filter, sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var orders []models.Order
+	if err := query.Order(orderBy).Limit(page.Limit).Offset(page.Offset).Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		responses = append(responses, toOrderResponse(&order))
+	}
+
+	return &dto.OrderListResponse{
+		Items:  responses,
+		Total:  total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"order-service/dto"
+	"order-service/models"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBuildOrderQuery(t *testing.T) {
+	_, db, _ := newTestService(t)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	for _, order := range []models.Order{
+		{UserID: 1, ProductID: 10, TotalPrice: 15, Status: models.StatusPending, CreatedAt: day(1)},
+		{UserID: 1, ProductID: 20, TotalPrice: 40, Status: models.StatusPaid, CreatedAt: day(2)},
+		{UserID: 2, ProductID: 10, TotalPrice: 25, Status: models.StatusPaid, CreatedAt: day(3)},
+		{UserID: 2, ProductID: 20, TotalPrice: 60, Status: models.StatusShipped, CreatedAt: day(4)},
+		{UserID: 3, ProductID: 10, TotalPrice: 5, Status: models.StatusCancelled, CreatedAt: day(5)},
+	} {
+		insertOrder(t, db, order)
+	}
+	id := func(v uint) *uint { return &v }
+	price := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name   string
+		filter dto.OrderFilter
+		sort   string
+		want   []uint
+	}{
+		{"no criteria", dto.OrderFilter{}, "", []uint{1, 2, 3, 4, 5}},
+		{"user", dto.OrderFilter{UserID: id(1)}, "", []uint{1, 2}},
+		{"user and product", dto.OrderFilter{UserID: id(2), ProductID: id(10)}, "", []uint{3}},
+		{"status", dto.OrderFilter{Status: models.StatusPaid}, "", []uint{2, 3}},
+		{"status and product", dto.OrderFilter{Status: models.StatusPaid, ProductID: id(20)}, "", []uint{2}},
+		{"total range", dto.OrderFilter{MinTotal: price(15), MaxTotal: price(40)}, "", []uint{1, 2, 3}},
+		{"equal total bounds", dto.OrderFilter{MinTotal: price(25), MaxTotal: price(25)}, "", []uint{3}},
+		{"minimum total and date", dto.OrderFilter{MinTotal: price(20), From: day(3)}, "", []uint{3, 4}},
+		{"date range", dto.OrderFilter{From: day(2), To: day(4)}, "", []uint{2, 3, 4}},
+		{"every criterion", dto.OrderFilter{UserID: id(2), ProductID: id(20), Status: models.StatusShipped, MinTotal: price(50), MaxTotal: price(70), From: day(1), To: day(5)}, "", []uint{4}},
+		{"no match", dto.OrderFilter{UserID: id(3), Status: models.StatusPaid}, "", nil},
+		{"sort by total", dto.OrderFilter{}, "total_price", []uint{5, 1, 3, 2, 4}},
+		{"sort by total descending", dto.OrderFilter{UserID: id(1)}, "-total_price", []uint{2, 1}},
+		{"sort by newest", dto.OrderFilter{ProductID: id(10)}, "-created_at", []uint{5, 3, 1}},
+	}
+	for _, tt := range tests {
+		query, orderBy, err := buildOrderQuery(db, tt.filter, tt.sort)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		var got []uint
+		if err := query.Order(orderBy).Pluck("id", &got).Error; err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: orders %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildOrderQueryRejects(t *testing.T) {
+	_, db, _ := newTestService(t)
+	price := func(v float64) *float64 { return &v }
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter dto.OrderFilter
+		sort   string
+		want   error
+	}{
+		{"unknown status", dto.OrderFilter{Status: "lost"}, "", ErrInvalidStatus},
+		{"sort outside the allowlist", dto.OrderFilter{}, "user_id", ErrInvalidSearch},
+		{"sort by raw SQL", dto.OrderFilter{}, "id; DROP TABLE orders", ErrInvalidSearch},
+		{"minimum above maximum", dto.OrderFilter{MinTotal: price(50), MaxTotal: price(10)}, "", ErrInvalidSearch},
+		{"from after to", dto.OrderFilter{From: from, To: from.Add(-time.Hour)}, "", ErrInvalidSearch},
+	}
+	for _, tt := range tests {
+		if _, _, err := buildOrderQuery(db, tt.filter, tt.sort); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestGetAllOrdersCreatedRange(t *testing.T) {
+	s, db, _ := newTestService(t)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	for d := 1; d <= 5; d++ {
+		insertOrder(t, db, models.Order{UserID: 1, ProductID: 1, CreatedAt: day(d)})
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []uint
+	}{
+		{"both bounds inclusive", day(2), day(4), []uint{2, 3, 4}},
+		{"open start", time.Time{}, day(2), []uint{1, 2}},
+		{"open end", day(4), time.Time{}, []uint{4, 5}},
+		{"single instant", day(3), day(3), []uint{3}},
+		{"other time zone", day(2).In(time.FixedZone("UTC-5", -5*3600)), day(2).Add(time.Hour), []uint{2}},
+		{"empty window", day(2).Add(time.Second), day(3).Add(-time.Second), nil},
+	}
+	for _, tt := range tests {
+		filter := dto.OrderFilter{From: tt.from, To: tt.to}
+		list, err := s.GetAllOrders(context.Background(), filter, dto.Pagination{Limit: 10})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []uint
+		for _, order := range list.Items {
+			got = append(got, order.ID)
+		}
+		if !slices.Equal(got, tt.want) || list.Total != int64(len(tt.want)) {
+			t.Errorf("%s: orders %v (total %d), want %v", tt.name, got, list.Total, tt.want)
+		}
+	}
+}
+
+func TestGetAllOrdersRejectsReversedRange(t *testing.T) {
+	s, _, _ := newTestService(t)
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	filter := dto.OrderFilter{From: from, To: from.Add(-time.Nanosecond)}
+	if _, err := s.GetAllOrders(context.Background(), filter, dto.Pagination{Limit: 10}); !errors.Is(err, ErrInvalidSearch) {
+		t.Errorf("err = %v, want ErrInvalidSearch", err)
+	}
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"order-service/apperror"
+	"order-service/config"
+	"order-service/dto"
+	"order-service/models"
+	"regexp"
+
+	"gorm.io/gorm"
+)
+
+// dimWeightDivisor converts a parcel's volume in cm³ to its dimensional
+// weight in kg, as carriers bill bulky light parcels by size
+const dimWeightDivisor = 5000
+
+// zipPattern matches a US ZIP or ZIP+4 code
+var zipPattern = regexp.MustCompile(`^\d{5}(-\d{4})?$`)
+
+// defaultShippingRates is used when SHIPPING_RATE_TABLE is unset
+var defaultShippingRates = []config.ShippingRate{
+	{MaxGrams: 1000, Base: 4.99, PerKg: 0},
+	{MaxGrams: 5000, Base: 7.99, PerKg: 1.50},
+	{MaxGrams: 20000, Base: 12.99, PerKg: 1.00},
+	{MaxGrams: 0, Base: 24.99, PerKg: 0.75},
+}
+
+// EstimateShipping estimates the cost of shipping an order to a ZIP code from
+// the configured rate table. The billable weight is the greater of the actual
+// and dimensional weight of the ordered quantity.
+func (s *OrderService) EstimateShipping(ctx context.Context, orderID uint, destination string) (*dto.ShippingEstimateResponse, error) {
+	if !zipPattern.MatchString(destination) {
+		return nil, apperror.Validation("destination must be a 5-digit ZIP or ZIP+4 code")
+	}
+
+	var order models.Order
+	if err := s.db.WithContext(ctx).First(&order, orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("order not found")
+		}
+		return nil, err
+	}
+
+	product, err := s.fetchProduct(ctx, order.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if product.WeightGrams <= 0 {
+		return nil, apperror.Validation("product %d has no weight recorded, so shipping can't be estimated", product.ID)
+	}
+
+	quantity := int(order.Quantity)
+	weight := product.WeightGrams * quantity
+	d := product.DimensionsCM
+	dimWeight := int(math.Ceil(d.Length*d.Width*d.Height/dimWeightDivisor*1000)) * quantity
+	billable := weight
+	if dimWeight > billable {
+		billable = dimWeight
+	}
+
+	rate := s.shippingRates[len(s.shippingRates)-1]
+	for _, r := range s.shippingRates {
+		if r.MaxGrams == 0 || billable <= r.MaxGrams {
+			rate = r
+			break
+		}
+	}
+
+	weightCost := roundCents(rate.PerKg * float64(billable) / 1000)
+	return &dto.ShippingEstimateResponse{
+		OrderID:                order.ID,
+		Destination:            destination,
+		WeightGrams:            weight,
+		DimensionalWeightGrams: dimWeight,
+		BillableWeightGrams:    billable,
+		Base:                   rate.Base,
+		PerKg:                  rate.PerKg,
+		WeightCost:             weightCost,
+		Total:                  roundCents(rate.Base + weightCost),
+	}, nil
+}
+
+// roundCents rounds an amount to two decimal places
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"order-service/apperror"
+	"order-service/dto"
+	"order-service/models"
+	"testing"
+)
+
+func TestEstimateShippingTiers(t *testing.T) {
+	tests := []struct {
+		name       string
+		grams      int
+		dimensions dto.Dimensions
+		quantity   uint
+		billable   int
+		total      float64
+	}{
+		{"first tier flat rate", 800, dto.Dimensions{}, 1, 800, 4.99},
+		{"first tier upper bound", 500, dto.Dimensions{}, 2, 1000, 4.99},
+		{"second tier", 3000, dto.Dimensions{}, 1, 3000, 12.49},
+		{"third tier", 10000, dto.Dimensions{}, 1, 10000, 22.99},
+		{"unbounded tier", 15000, dto.Dimensions{}, 2, 30000, 47.49},
+		{"dimensional weight", 1000, dto.Dimensions{Length: 50, Width: 40, Height: 30}, 1, 12000, 24.99},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db, products := newTestService(t)
+			products.add(1, 10, 0)
+			products.update(1, func(p *dto.ProductResponse) {
+				p.WeightGrams = tt.grams
+				p.DimensionsCM = tt.dimensions
+			})
+			order := insertOrder(t, db, models.Order{UserID: 1, ProductID: 1, Quantity: tt.quantity})
+
+			estimate, err := s.EstimateShipping(context.Background(), order.ID, "94107")
+			if err != nil {
+				t.Fatalf("EstimateShipping: %v", err)
+			}
+			if estimate.BillableWeightGrams != tt.billable {
+				t.Errorf("billable weight = %d, want %d", estimate.BillableWeightGrams, tt.billable)
+			}
+			if estimate.Total != tt.total {
+				t.Errorf("total = %v, want %v", estimate.Total, tt.total)
+			}
+		})
+	}
+}
+
+func TestEstimateShippingRejects(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 0)
+	order := insertOrder(t, db, models.Order{UserID: 1, ProductID: 1})
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		orderID     uint
+		destination string
+		code        apperror.Code
+	}{
+		{"missing weight data", order.ID, "94107", apperror.CodeValidation},
+		{"bad destination", order.ID, "9410", apperror.CodeValidation},
+		{"unknown order", order.ID + 1, "94107-1234", apperror.CodeNotFound},
+	}
+	for _, tt := range tests {
+		_, err := s.EstimateShipping(ctx, tt.orderID, tt.destination)
+		var appErr *apperror.Error
+		if !errors.As(err, &appErr) || appErr.Code != tt.code {
+			t.Errorf("%s: err = %v, want code %s", tt.name, err, tt.code)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/apperror"
+	"order-service/dto"
+	"order-service/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidStatus is returned for a status that isn't part of the lifecycle
+	ErrInvalidStatus = apperror.Validation("invalid order status")
+	// ErrInvalidTransition is returned when an order can't move to the requested status
+	ErrInvalidTransition = apperror.Conflict("invalid status transition")
+	// ErrOrderNotPending is returned when modifying an order that is past pending
+	ErrOrderNotPending = apperror.Conflict("only pending orders can be modified")
+)
+
+// allowedTransitions lists the statuses each status may move to. Orders move
+// forward through pending → paid → shipped → delivered and may be cancelled
+// at any point before delivery.
+var allowedTransitions = map[string][]string{
+	models.StatusPending:   {models.StatusPaid, models.StatusCancelled},
+	models.StatusPaid:      {models.StatusShipped, models.StatusCancelled},
+	models.StatusShipped:   {models.StatusDelivered, models.StatusCancelled},
+	models.StatusDelivered: {},
+	models.StatusCancelled: {},
+}
+
+// canTransition reports whether an order may move from one status to another
+func canTransition(from, to string) bool {
+	for _, next := range allowedTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateOrderStatus moves an order to a new status, enforcing the lifecycle.
+// Cancelling an order that hasn't shipped releases its stock.
+func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID uint, status string) (*dto.OrderResponse, error) {
+	if _, ok := allowedTransitions[status]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidStatus, status)
+	}
+
+	var order models.Order
+	if err := s.db.WithContext(ctx).First(&order, orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("order not found")
+		}
+		return nil, err
+	}
+
+	if !canTransition(order.Status, status) {
+		return nil, fmt.Errorf("%w: cannot move order from %s to %s", ErrInvalidTransition, order.Status, status)
+	}
+
+	// The update only applies in the status the order was read in, so of two
+	// concurrent cancellations only one releases the stock
+	previous := order.Status
+	res := s.db.WithContext(ctx).Model(&order).Where("status = ?", previous).Update("status", status)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: order status changed concurrently", ErrInvalidTransition)
+	}
+	if status == models.StatusCancelled && holdsStock(previous) {
+		s.releaseStock(ctx, order.ProductID, order.Quantity)
+	}
+
+	response := toOrderResponse(&order)
+	return &response, nil
+}
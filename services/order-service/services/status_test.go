@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"order-service/apperror"
+	"order-service/models"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{models.StatusPending, models.StatusPaid, true},
+		{models.StatusPaid, models.StatusShipped, true},
+		{models.StatusShipped, models.StatusDelivered, true},
+		{models.StatusPending, models.StatusCancelled, true},
+		{models.StatusPaid, models.StatusCancelled, true},
+		{models.StatusShipped, models.StatusCancelled, true},
+		{models.StatusPending, models.StatusShipped, false},
+		{models.StatusPaid, models.StatusPending, false},
+		{models.StatusDelivered, models.StatusCancelled, false},
+		{models.StatusCancelled, models.StatusPending, false},
+		{models.StatusPending, models.StatusPending, false},
+	}
+	for _, tt := range tests {
+		if got := canTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("canTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateOrderStatusLifecycle(t *testing.T) {
+	s, db, _ := newTestService(t)
+	order := insertOrder(t, db, models.Order{UserID: 1, ProductID: 1})
+	ctx := context.Background()
+
+	for _, status := range []string{models.StatusPaid, models.StatusShipped, models.StatusDelivered} {
+		resp, err := s.UpdateOrderStatus(ctx, order.ID, status)
+		if err != nil {
+			t.Fatalf("move to %s: %v", status, err)
+		}
+		if resp.Status != status {
+			t.Errorf("status = %s, want %s", resp.Status, status)
+		}
+	}
+
+	_, err := s.UpdateOrderStatus(ctx, order.ID, models.StatusCancelled)
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("cancel delivered order: err = %v, want ErrInvalidTransition", err)
+	}
+	var stored models.Order
+	db.First(&stored, order.ID)
+	if stored.Status != models.StatusDelivered {
+		t.Errorf("stored status = %s, want delivered", stored.Status)
+	}
+}
+
+func TestUpdateOrderStatusRejects(t *testing.T) {
+	s, db, _ := newTestService(t)
+	order := insertOrder(t, db, models.Order{UserID: 1, ProductID: 1})
+	ctx := context.Background()
+
+	if _, err := s.UpdateOrderStatus(ctx, order.ID, "refunded"); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("unknown status: err = %v, want ErrInvalidStatus", err)
+	}
+	if _, err := s.UpdateOrderStatus(ctx, order.ID, models.StatusShipped); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("pending to shipped: err = %v, want ErrInvalidTransition", err)
+	}
+	_, err := s.UpdateOrderStatus(ctx, order.ID+1, models.StatusPaid)
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Code != apperror.CodeNotFound {
+		t.Errorf("missing order: err = %v, want not found", err)
+	}
+}
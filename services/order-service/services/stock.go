@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"order-service/clients"
+	"order-service/logging"
+	"order-service/models"
+)
+
+// ErrInsufficientStock is returned when the product service can't cover the
+// requested quantity
+var ErrInsufficientStock = clients.ErrInsufficientStock
+
+// adjustStock changes a product's stock by delta through the product service
+func (s *OrderService) adjustStock(ctx context.Context, productID uint, delta int) error {
+	return s.products.AdjustStock(ctx, productID, delta)
+}
+
+// releaseStock returns previously reserved units to a product, logging
+// rather than failing when the compensation itself can't be applied. It runs
+// even if ctx was cancelled, since the reservation has already been made.
+func (s *OrderService) releaseStock(ctx context.Context, productID uint, quantity uint) {
+	if err := s.adjustStock(context.WithoutCancel(ctx), productID, int(quantity)); err != nil {
+		logging.PrintfContext(ctx, "Failed to release %d units of product %d, stock must be corrected manually: %v", quantity, productID, err)
+	}
+}
+
+// holdsStock reports whether an order in status still has its units
+// reserved. Once shipped they have left the warehouse, and a cancelled order
+// has already given them back.
+func holdsStock(status string) bool {
+	return status == models.StatusPending || status == models.StatusPaid
+}
+
+// reservationChange works out how many units an order change must reserve of
+// its new product and release of its previous one
+func reservationChange(prevProductID, prevQuantity, productID, quantity uint) (reserve, release uint) {
+	switch {
+	case prevProductID != productID:
+		return quantity, prevQuantity
+	case quantity > prevQuantity:
+		return quantity - prevQuantity, 0
+	default:
+		return 0, prevQuantity - quantity
+	}
+}
+
+// reserveStocks reserves quantities[id] units of every product in ids, in
+// order. If one can't be covered, the products already reserved are released
+// and its ID is returned with the error.
+func (s *OrderService) reserveStocks(ctx context.Context, ids []uint, quantities map[uint]uint) (uint, error) {
+	for i, id := range ids {
+		if err := s.adjustStock(ctx, id, -int(quantities[id])); err != nil {
+			s.releaseStocks(ctx, ids[:i], quantities)
+			return id, err
+		}
+	}
+	return 0, nil
+}
+
+// releaseStocks gives back what reserveStocks reserved for ids
+func (s *OrderService) releaseStocks(ctx context.Context, ids []uint, quantities map[uint]uint) {
+	for _, id := range ids {
+		s.releaseStock(ctx, id, quantities[id])
+	}
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"order-service/dto"
+	"order-service/models"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestReservationChange(t *testing.T) {
+	tests := []struct {
+		name                                         string
+		prevProduct, prevQuantity, product, quantity uint
+		reserve, release                             uint
+	}{
+		{"more units", 1, 2, 1, 5, 3, 0},
+		{"fewer units", 1, 5, 1, 2, 0, 3},
+		{"unchanged", 1, 2, 1, 2, 0, 0},
+		{"other product", 1, 2, 2, 3, 3, 2},
+	}
+	for _, tt := range tests {
+		reserve, release := reservationChange(tt.prevProduct, tt.prevQuantity, tt.product, tt.quantity)
+		if reserve != tt.reserve || release != tt.release {
+			t.Errorf("%s: reserve, release = %d, %d, want %d, %d", tt.name, reserve, release, tt.reserve, tt.release)
+		}
+	}
+}
+
+// createOrder places an order through the service, reserving its stock
+func createOrder(t *testing.T, s *OrderService, productID, quantity uint) *dto.OrderWithDetailsResponse {
+	t.Helper()
+	order, err := s.CreateOrder(context.Background(), dto.CreateOrderRequest{UserID: 1, ProductID: productID, Quantity: &quantity})
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	return order
+}
+
+func TestCreateOrderReservesStock(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 5)
+
+	createOrder(t, s, 1, 2)
+	if stock := products.stockOf(1); stock != 3 {
+		t.Errorf("stock = %d, want 3", stock)
+	}
+
+	quantity := uint(4)
+	_, err := s.CreateOrder(context.Background(), dto.CreateOrderRequest{UserID: 1, ProductID: 1, Quantity: &quantity})
+	if !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("err = %v, want ErrInsufficientStock", err)
+	}
+	if n := countOrders(t, db); n != 1 {
+		t.Errorf("%d orders, want only the first", n)
+	}
+	if stock := products.stockOf(1); stock != 3 {
+		t.Errorf("stock = %d after the refused order, want 3", stock)
+	}
+}
+
+func TestCreateOrderReleasesStockWhenInsertFails(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 5)
+
+	err := db.Callback().Create().Before("gorm:create").Register("test:fail", func(tx *gorm.DB) {
+		tx.AddError(errors.New("disk full"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quantity := uint(2)
+	if _, err := s.CreateOrder(context.Background(), dto.CreateOrderRequest{UserID: 1, ProductID: 1, Quantity: &quantity}); err == nil {
+		t.Fatal("CreateOrder succeeded, want the insert error")
+	}
+	if stock := products.stockOf(1); stock != 5 {
+		t.Errorf("stock = %d, want the reservation released back to 5", stock)
+	}
+}
+
+func TestUpdateOrderMovesReservation(t *testing.T) {
+	s, _, products := newTestService(t)
+	products.add(1, 10, 5)
+	products.add(2, 20, 10)
+	order := createOrder(t, s, 1, 1)
+	ctx := context.Background()
+
+	quantity := uint(3)
+	if _, err := s.UpdateOrder(ctx, order.ID, 0, dto.UpdateOrderRequest{Quantity: &quantity}); err != nil {
+		t.Fatalf("raise quantity: %v", err)
+	}
+	if stock := products.stockOf(1); stock != 2 {
+		t.Errorf("after raising quantity: stock = %d, want 2", stock)
+	}
+
+	productID := uint(2)
+	resp, err := s.UpdateOrder(ctx, order.ID, 0, dto.UpdateOrderRequest{ProductID: &productID})
+	if err != nil {
+		t.Fatalf("change product: %v", err)
+	}
+	if resp.TotalPrice != 60 {
+		t.Errorf("total = %v, want 60", resp.TotalPrice)
+	}
+	if stock := products.stockOf(1); stock != 5 {
+		t.Errorf("after changing product: old product stock = %d, want 5", stock)
+	}
+	if stock := products.stockOf(2); stock != 7 {
+		t.Errorf("after changing product: new product stock = %d, want 7", stock)
+	}
+
+	quantity = 1
+	if _, err := s.UpdateOrder(ctx, order.ID, 0, dto.UpdateOrderRequest{Quantity: &quantity}); err != nil {
+		t.Fatalf("lower quantity: %v", err)
+	}
+	if stock := products.stockOf(2); stock != 9 {
+		t.Errorf("after lowering quantity: stock = %d, want 9", stock)
+	}
+}
+
+func TestUpdateOrderInsufficientStock(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 3)
+	order := createOrder(t, s, 1, 1)
+
+	quantity := uint(5)
+	_, err := s.UpdateOrder(context.Background(), order.ID, 0, dto.UpdateOrderRequest{Quantity: &quantity})
+	if !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("err = %v, want ErrInsufficientStock", err)
+	}
+	var stored models.Order
+	db.First(&stored, order.ID)
+	if stored.Quantity != 1 {
+		t.Errorf("quantity = %d, want it unchanged at 1", stored.Quantity)
+	}
+	if stock := products.stockOf(1); stock != 2 {
+		t.Errorf("stock = %d, want 2", stock)
+	}
+}
+
+func TestDeleteOrderReleasesStock(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 5)
+	pending := createOrder(t, s, 1, 2)
+	shipped := insertOrder(t, db, models.Order{UserID: 1, ProductID: 1, Quantity: 1, Status: models.StatusShipped})
+	ctx := context.Background()
+
+	if err := s.DeleteOrder(ctx, pending.ID, 0); err != nil {
+		t.Fatalf("delete pending order: %v", err)
+	}
+	if stock := products.stockOf(1); stock != 5 {
+		t.Errorf("after deleting pending order: stock = %d, want 5", stock)
+	}
+
+	// Shipped units have left the warehouse
+	if err := s.DeleteOrder(ctx, shipped.ID, 0); err != nil {
+		t.Fatalf("delete shipped order: %v", err)
+	}
+	if stock := products.stockOf(1); stock != 5 {
+		t.Errorf("after deleting shipped order: stock = %d, want 5", stock)
+	}
+}
+
+func TestCancelOrderReleasesStockOnce(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 5)
+	order := createOrder(t, s, 1, 2)
+	ctx := context.Background()
+
+	if _, err := s.UpdateOrderStatus(ctx, order.ID, models.StatusCancelled); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if stock := products.stockOf(1); stock != 5 {
+		t.Errorf("after cancelling: stock = %d, want 5", stock)
+	}
+
+	// Deleting the cancelled order must not give the units back a second time
+	if err := s.DeleteOrder(ctx, order.ID, 0); err != nil {
+		t.Fatalf("delete cancelled order: %v", err)
+	}
+	if stock := products.stockOf(1); stock != 5 {
+		t.Errorf("after deleting: stock = %d, want 5", stock)
+	}
+
+	shipped := insertOrder(t, db, models.Order{UserID: 1, ProductID: 1, Quantity: 1, Status: models.StatusShipped})
+	if _, err := s.UpdateOrderStatus(ctx, shipped.ID, models.StatusCancelled); err != nil {
+		t.Fatalf("cancel shipped: %v", err)
+	}
+	if stock := products.stockOf(1); stock != 5 {
+		t.Errorf("after cancelling a shipped order: stock = %d, want 5", stock)
+	}
+}
+
+func TestCreateOrdersBatchReservesPerProduct(t *testing.T) {
+	s, db, products := newTestService(t)
+	products.add(1, 10, 5)
+	products.add(2, 20, 1)
+	two := uint(2)
+	ctx := context.Background()
+
+	// Product 2 can't cover both entries, so the whole batch is refused and
+	// product 1's reservation is given back
+	reqs := []dto.CreateOrderRequest{
+		{UserID: 1, ProductID: 1, Quantity: &two},
+		{UserID: 1, ProductID: 2},
+		{UserID: 2, ProductID: 2},
+	}
+	results, err := s.CreateOrdersBatch(ctx, reqs)
+	if !IsBatchInvalid(err) {
+		t.Fatalf("err = %v, want an invalid batch", err)
+	}
+	if results[0].Error != "" || results[1].Error == "" || results[2].Error == "" {
+		t.Errorf("errors = %q, %q, %q, want only the product 2 entries rejected", results[0].Error, results[1].Error, results[2].Error)
+	}
+	if n := countOrders(t, db); n != 0 {
+		t.Errorf("%d orders, want none", n)
+	}
+	if stock := products.stockOf(1); stock != 5 {
+		t.Errorf("product 1 stock = %d, want 5", stock)
+	}
+
+	if _, err := s.CreateOrdersBatch(ctx, reqs[:2]); err != nil {
+		t.Fatalf("CreateOrdersBatch: %v", err)
+	}
+	if stock1, stock2 := products.stockOf(1), products.stockOf(2); stock1 != 3 || stock2 != 0 {
+		t.Errorf("stock = %d, %d, want 3, 0", stock1, stock2)
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"order-service/dto"
+	"order-service/models"
+
+	"gorm.io/gorm"
+)
+
+// GetSalesSummary aggregates the orders matching filter in the database: how
+// many there are, the revenue they brought in, and the units and revenue of
+// each product, ordered by product ID. Cancelled orders are not sales and are
+// left out.
+func (s *OrderService) GetSalesSummary(ctx context.Context, filter dto.OrderFilter) (*dto.SalesSummaryResponse, error) {
+	query, _, err := buildOrderQuery(s.db.WithContext(ctx), filter, "")
+	if err != nil {
+		return nil, err
+	}
+	// A new session lets both aggregates below start from the same conditions
+	sales := query.Where("status <> ?", models.StatusCancelled).Session(&gorm.Session{})
+
+	var totals struct {
+		Count   int64
+		Revenue float64
+	}
+	if err := sales.Select("COUNT(*) AS count, COALESCE(SUM(total_price), 0) AS revenue").Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	products := []dto.ProductSales{}
+	if err := sales.
+		Select("product_id, SUM(quantity) AS units_sold, SUM(total_price) AS revenue").
+		Group("product_id").Order("product_id").
+		Scan(&products).Error; err != nil {
+		return nil, err
+	}
+	for i := range products {
+		products[i].Revenue = roundCents(products[i].Revenue)
+	}
+
+	return &dto.SalesSummaryResponse{
+		OrderCount:   totals.Count,
+		TotalRevenue: roundCents(totals.Revenue),
+		Products:     products,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"order-service/dto"
+	"order-service/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetSalesSummary(t *testing.T) {
+	s, db, _ := newTestService(t)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	for _, order := range []models.Order{
+		{UserID: 1, ProductID: 20, Quantity: 2, TotalPrice: 40.1, Status: models.StatusPaid, CreatedAt: day(1)},
+		{UserID: 1, ProductID: 10, Quantity: 1, TotalPrice: 15.2, Status: models.StatusPending, CreatedAt: day(2)},
+		{UserID: 2, ProductID: 20, Quantity: 3, TotalPrice: 60.3, Status: models.StatusShipped, CreatedAt: day(3)},
+		{UserID: 2, ProductID: 10, Quantity: 9, TotalPrice: 99, Status: models.StatusCancelled, CreatedAt: day(3)},
+	} {
+		insertOrder(t, db, order)
+	}
+
+	got, err := s.GetSalesSummary(context.Background(), dto.OrderFilter{})
+	if err != nil {
+		t.Fatalf("GetSalesSummary: %v", err)
+	}
+	want := &dto.SalesSummaryResponse{
+		OrderCount:   3,
+		TotalRevenue: 115.6,
+		Products: []dto.ProductSales{
+			{ProductID: 10, UnitsSold: 1, Revenue: 15.2},
+			{ProductID: 20, UnitsSold: 5, Revenue: 100.4},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summary = %+v, want %+v", got, want)
+	}
+
+	got, err = s.GetSalesSummary(context.Background(), dto.OrderFilter{From: day(2), To: day(2)})
+	if err != nil {
+		t.Fatalf("GetSalesSummary with a range: %v", err)
+	}
+	if got.OrderCount != 1 || got.TotalRevenue != 15.2 || len(got.Products) != 1 {
+		t.Errorf("summary of day 2 = %+v, want only the order of product 10", got)
+	}
+
+	got, err = s.GetSalesSummary(context.Background(), dto.OrderFilter{From: day(10)})
+	if err != nil {
+		t.Fatalf("GetSalesSummary of an empty range: %v", err)
+	}
+	if got.OrderCount != 0 || got.TotalRevenue != 0 || got.Products == nil || len(got.Products) != 0 {
+		t.Errorf("empty summary = %+v, want zeros and an empty product list", got)
+	}
+
+	if _, err := s.GetSalesSummary(context.Background(), dto.OrderFilter{From: day(3), To: day(1)}); !errors.Is(err, ErrInvalidSearch) {
+		t.Errorf("from after to: err = %v, want ErrInvalidSearch", err)
+	}
+}
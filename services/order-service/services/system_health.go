@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"order-service/dto"
+	"sync"
+	"time"
+)
+
+// healthProbeTimeout bounds how long a single downstream health probe may take
+const healthProbeTimeout = 2 * time.Second
+
+// Health statuses reported by CheckSystemHealth
+const (
+	HealthUp       = "up"
+	HealthDown     = "down"
+	HealthDegraded = "degraded"
+)
+
+// Readiness statuses reported by CheckReadiness
+const (
+	Ready    = "ready"
+	NotReady = "not_ready"
+)
+
+// CheckSystemHealth probes the user and product services concurrently and
+// rolls their status up: up when all are healthy, down when every
+// dependency is unreachable, and degraded otherwise
+func (s *OrderService) CheckSystemHealth(ctx context.Context) dto.SystemHealthResponse {
+	targets := s.healthTargets()
+
+	result := dto.SystemHealthResponse{
+		Status: HealthUp,
+		Services: map[string]dto.ServiceHealth{
+			"order-service": {Status: HealthUp},
+		},
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, target := range targets {
+		wg.Add(1)
+		go func(name string, target pinger) {
+			defer wg.Done()
+			health := probeHealth(ctx, target)
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Services[name] = health
+		}(name, target)
+	}
+	wg.Wait()
+
+	down := 0
+	for name := range targets {
+		if result.Services[name].Status != HealthUp {
+			down++
+		}
+	}
+	switch {
+	case down == len(targets):
+		result.Status = HealthDown
+	case down > 0:
+		result.Status = HealthDegraded
+	}
+
+	return result
+}
+
+// pinger is a downstream client whose health can be probed
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// healthTargets returns the downstream services to probe, keyed by name
+func (s *OrderService) healthTargets() map[string]pinger {
+	return map[string]pinger{
+		"user-service":    s.users,
+		"product-service": s.products,
+	}
+}
+
+// probeHealth calls a service's health endpoint and reports whether it is up
+func probeHealth(ctx context.Context, target pinger) dto.ServiceHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+	if err := target.Ping(ctx); err != nil {
+		return dto.ServiceHealth{Status: HealthDown, Error: err.Error()}
+	}
+	return dto.ServiceHealth{Status: HealthUp}
+}
+
+// CheckReadiness checks every dependency the order service needs to serve
+// requests: the user and product services and the database. The service is
+// ready only when all of them are up.
+func (s *OrderService) CheckReadiness(ctx context.Context) dto.ReadinessResponse {
+	targets := s.healthTargets()
+
+	result := dto.ReadinessResponse{
+		Status:       Ready,
+		Dependencies: make(map[string]dto.ServiceHealth, len(targets)+1),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	record := func(name string, health dto.ServiceHealth) {
+		mu.Lock()
+		defer mu.Unlock()
+		result.Dependencies[name] = health
+		if health.Status != HealthUp {
+			result.Status = NotReady
+		}
+	}
+
+	for name, target := range targets {
+		wg.Add(1)
+		go func(name string, target pinger) {
+			defer wg.Done()
+			record(name, probeHealth(ctx, target))
+		}(name, target)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		record("database", s.pingDB(ctx))
+	}()
+	wg.Wait()
+
+	return result
+}
+
+// pingDB checks that the database accepts connections
+func (s *OrderService) pingDB(ctx context.Context) dto.ServiceHealth {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return dto.ServiceHealth{Status: HealthDown, Error: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return dto.ServiceHealth{Status: HealthDown, Error: err.Error()}
+	}
+	return dto.ServiceHealth{Status: HealthUp}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"order-service/apperror"
+	"order-service/dto"
+	"order-service/models"
+	"time"
+)
+
+// MaxThroughputBuckets caps how many points a single throughput query may return
+const MaxThroughputBuckets = 1000
+
+// bucketLayout is the string form every driver renders bucket starts in
+const bucketLayout = "2006-01-02 15:04:05"
+
+var (
+	// ErrInvalidBucket is returned for a bucket size outside the allowlist
+	ErrInvalidBucket = apperror.Validation("invalid bucket, expected 1h or 1d")
+	// ErrInvalidRange is returned when from/to don't describe a usable window
+	ErrInvalidRange = apperror.Validation("invalid time range")
+)
+
+// throughputBucket describes how to truncate created_at for one bucket size
+type throughputBucket struct {
+	width        time.Duration
+	pgUnit       string // date_trunc unit
+	sqliteFormat string // strftime format
+}
+
+// throughputBuckets is the allowlist of supported bucket sizes
+var throughputBuckets = map[string]throughputBucket{
+	"1h": {width: time.Hour, pgUnit: "hour", sqliteFormat: "%Y-%m-%d %H:00:00"},
+	"1d": {width: 24 * time.Hour, pgUnit: "day", sqliteFormat: "%Y-%m-%d 00:00:00"},
+}
+
+// bucketExpr returns a SQL expression rendering created_at's bucket start in
+// UTC as bucketLayout, for the dialect the service is connected to
+func (s *OrderService) bucketExpr(b throughputBucket) string {
+	if s.db.Dialector.Name() == "sqlite" {
+		return fmt.Sprintf("strftime('%s', created_at)", b.sqliteFormat)
+	}
+	return fmt.Sprintf("to_char(date_trunc('%s', created_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD HH24:MI:SS')", b.pgUnit)
+}
+
+// GetThroughput counts orders created in [from, to) grouped by the given
+// bucket size. Buckets without orders are returned with a zero count so the
+// series is continuous. A zero from/to defaults to the last 24 buckets.
+func (s *OrderService) GetThroughput(ctx context.Context, bucket string, from, to time.Time) (*dto.ThroughputResponse, error) {
+	b, ok := throughputBuckets[bucket]
+	if !ok {
+		return nil, ErrInvalidBucket
+	}
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+	to = to.UTC()
+	if from.IsZero() {
+		from = to.Add(-24 * b.width)
+	}
+	from = from.UTC().Truncate(b.width)
+
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidRange)
+	}
+	if n := to.Sub(from) / b.width; n > MaxThroughputBuckets {
+		return nil, fmt.Errorf("%w: range spans more than %d buckets", ErrInvalidRange, MaxThroughputBuckets)
+	}
+
+	var rows []struct {
+		Bucket string
+		Count  int64
+	}
+	err := s.db.WithContext(ctx).Model(&models.Order{}).
+		Select(s.bucketExpr(b)+" AS bucket, COUNT(*) AS count").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[time.Time]int64, len(rows))
+	for _, row := range rows {
+		start, err := time.ParseInLocation(bucketLayout, row.Bucket, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected bucket value %q: %v", row.Bucket, err)
+		}
+		counts[start] = row.Count
+	}
+
+	points := []dto.ThroughputPoint{}
+	for start := from; start.Before(to); start = start.Add(b.width) {
+		points = append(points, dto.ThroughputPoint{Start: start, Count: counts[start]})
+	}
+
+	return &dto.ThroughputResponse{
+		Bucket: bucket,
+		From:   from,
+		To:     to,
+		Points: points,
+	}, nil
+}
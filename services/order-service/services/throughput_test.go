@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"order-service/models"
+	"testing"
+	"time"
+)
+
+func TestGetThroughputBuckets(t *testing.T) {
+	s, db, _ := newTestService(t)
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, offset := range []time.Duration{
+		10 * time.Minute, 50 * time.Minute, // hour 0
+		2*time.Hour + 59*time.Minute, // hour 2
+		4 * time.Hour,                // hour 4, excluded by to
+		26 * time.Hour,               // next day
+	} {
+		insertOrder(t, db, models.Order{UserID: 1, ProductID: 1, CreatedAt: base.Add(offset)})
+	}
+
+	hourly, err := s.GetThroughput(context.Background(), "1h", base.Add(15*time.Minute), base.Add(4*time.Hour))
+	if err != nil {
+		t.Fatalf("GetThroughput(1h): %v", err)
+	}
+	// from is truncated to its bucket, so the 00:10 order still counts
+	wantHourly := []int64{2, 0, 1, 0}
+	if len(hourly.Points) != len(wantHourly) {
+		t.Fatalf("got %d hourly points, want %d", len(hourly.Points), len(wantHourly))
+	}
+	for i, point := range hourly.Points {
+		if want := base.Add(time.Duration(i) * time.Hour); !point.Start.Equal(want) {
+			t.Errorf("point %d starts at %s, want %s", i, point.Start, want)
+		}
+		if point.Count != wantHourly[i] {
+			t.Errorf("point %d count = %d, want %d", i, point.Count, wantHourly[i])
+		}
+	}
+
+	daily, err := s.GetThroughput(context.Background(), "1d", base, base.Add(72*time.Hour))
+	if err != nil {
+		t.Fatalf("GetThroughput(1d): %v", err)
+	}
+	wantDaily := []int64{4, 1, 0}
+	if len(daily.Points) != len(wantDaily) {
+		t.Fatalf("got %d daily points, want %d", len(daily.Points), len(wantDaily))
+	}
+	for i, point := range daily.Points {
+		if point.Count != wantDaily[i] {
+			t.Errorf("day %d count = %d, want %d", i, point.Count, wantDaily[i])
+		}
+	}
+}
+
+func TestGetThroughputRejects(t *testing.T) {
+	s, _, _ := newTestService(t)
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		bucket   string
+		from, to time.Time
+		want     error
+	}{
+		{"unknown bucket", "1w", base, base.Add(time.Hour), ErrInvalidBucket},
+		{"reversed range", "1h", base.Add(time.Hour), base, ErrInvalidRange},
+		{"too many buckets", "1h", base, base.Add((MaxThroughputBuckets + 1) * time.Hour), ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		if _, err := s.GetThroughput(context.Background(), tt.bucket, tt.from, tt.to); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Package config loads the service's settings from environment variables
+// once at startup. Unset variables take their defaults; malformed ones are
+// reported together by Load so the service can refuse to start.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+)
+
+// Config holds the product service's settings
+type Config struct {
+	Port     int    // PORT
+	GRPCPort int    // GRPC_PORT, for the gRPC API served alongside REST
+	LogLevel string // LOG_LEVEL: debug, info, warn or error
+	Database Database
+
+	OrderServiceURL string // ORDER_SERVICE_URL
+
+	HTTP HTTP
+
+	CacheControlProducts string // CACHE_CONTROL_PRODUCTS
+	CacheControlHealth   string // CACHE_CONTROL_HEALTH
+
+	AdminToken  string // ADMIN_TOKEN; empty disables admin-only operations
+	RoundPrices bool   // PRICE_ROUNDING: reject (default) or round to cents
+
+	// CategoryCase is applied to categories on write (CATEGORY_CASE:
+	// lowercase or title); empty keeps them as given. AllowedCategories
+	// restricts the categories products may use (ALLOWED_CATEGORIES,
+	// comma-separated); empty allows any.
+	CategoryCase      string
+	AllowedCategories []string
+
+	// A stock decrease to ReorderPoint (REORDER_POINT) records a
+	// replenishment of ReorderQuantity units (REORDER_QUANTITY); zero
+	// disables replenishment
+	ReorderPoint    int
+	ReorderQuantity int
+
+	// CatalogSnapshotInterval is how often the listing fallback served
+	// during database outages is refreshed (CATALOG_SNAPSHOT_INTERVAL); zero
+	// disables it
+	CatalogSnapshotInterval time.Duration
+
+	// FeaturedRandomSeed seeds featured product sampling
+	// (FEATURED_RANDOM_SEED); it defaults to the start time, and setting it
+	// makes the selection reproducible
+	FeaturedRandomSeed int64
+}
+
+// Load reads the configuration from the environment
+func Load() (Config, error) {
+	var l loader
+	cfg := Config{
+		Port:     l.port("PORT", 8081),
+		GRPCPort: l.port("GRPC_PORT", 9081),
+		LogLevel: l.logLevel("LOG_LEVEL"),
+		Database: l.database("product_service"),
+
+		OrderServiceURL: l.url("ORDER_SERVICE_URL", "http://localhost:8082"),
+
+		HTTP: l.http(),
+
+		CacheControlProducts: l.string("CACHE_CONTROL_PRODUCTS", "public, max-age=60"),
+		CacheControlHealth:   l.string("CACHE_CONTROL_HEALTH", "no-store"),
+
+		AdminToken:  l.string("ADMIN_TOKEN", ""),
+		RoundPrices: l.oneOf("PRICE_ROUNDING", "reject", "reject", "round") == "round",
+
+		CategoryCase:      l.oneOf("CATEGORY_CASE", "", "lowercase", "title"),
+		AllowedCategories: l.list("ALLOWED_CATEGORIES"),
+
+		ReorderPoint:    l.int("REORDER_POINT", 0, 0),
+		ReorderQuantity: l.int("REORDER_QUANTITY", 0, 0),
+
+		CatalogSnapshotInterval: l.duration("CATALOG_SNAPSHOT_INTERVAL", 0),
+
+		FeaturedRandomSeed: l.int64("FEATURED_RANDOM_SEED", time.Now().UnixNano()),
+	}
+	return cfg, l.err()
+}
+
+// Database holds the database connection settings. The DB_HOST family of
+// variables configures PostgreSQL; SQLitePath is used only with sqlite.
+type Database struct {
+	Driver     string // DB_DRIVER: postgres or sqlite
+	Host       string // DB_HOST
+	Port       int    // DB_PORT
+	User       string // DB_USER
+	Password   string // DB_PASSWORD
+	Name       string // DB_NAME
+	SSLMode    string // DB_SSLMODE
+	SQLitePath string // DB_SQLITE_PATH: a file, or :memory:
+
+	// ConnectAttempts is how many times to try connecting at startup
+	// (DB_CONNECT_ATTEMPTS), ConnectRetryDelay the wait between attempts
+	// (DB_CONNECT_RETRY_DELAY)
+	ConnectAttempts   int
+	ConnectRetryDelay time.Duration
+
+	MaxOpenConns    int           // DB_MAX_OPEN_CONNS
+	MaxIdleConns    int           // DB_MAX_IDLE_CONNS
+	ConnMaxLifetime time.Duration // DB_CONN_MAX_LIFETIME
+}
+
+// DSN returns the connection string for the configured driver
+func (d Database) DSN() string {
+	if d.Driver == "sqlite" {
+		return d.SQLitePath
+	}
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode)
+}
+
+// HTTP holds the settings of the HTTP server and its middleware
+type HTTP struct {
+	RequireIdempotencyKey bool   // REQUIRE_IDEMPOTENCY_KEY
+	FeatureFlags          string // FEATURE_FLAGS, parsed by middleware.ParseFlags
+	JWTSecret             string // JWT_SECRET; empty disables authentication
+	ServiceToken          string // SERVICE_TOKEN, presented by the order service
+	MaxHeaderBytes        int    // MAX_HEADER_BYTES
+	MaxBodyBytes          int64  // MAX_BODY_BYTES
+	MaxRepeatedValues     int    // MAX_REPEATED_VALUES
+	AllowedOrigins        string // ALLOWED_ORIGINS
+	TrailingSlashMode     string // TRAILING_SLASH_MODE: rewrite or redirect
+
+	// RateLimitRPS is the per-client rate (RATE_LIMIT_RPS); zero disables
+	// rate limiting. RateLimitBurst defaults to the rate rounded up.
+	RateLimitRPS        float64
+	RateLimitBurst      int  // RATE_LIMIT_BURST
+	RateLimitTrustProxy bool // RATE_LIMIT_TRUST_PROXY
+}
+
+func (l *loader) http() HTTP {
+	cfg := HTTP{
+		RequireIdempotencyKey: l.bool("REQUIRE_IDEMPOTENCY_KEY", false),
+		FeatureFlags:          l.string("FEATURE_FLAGS", ""),
+		JWTSecret:             l.string("JWT_SECRET", ""),
+		ServiceToken:          l.string("SERVICE_TOKEN", ""),
+		MaxHeaderBytes:        l.int("MAX_HEADER_BYTES", http.DefaultMaxHeaderBytes, 1),
+		MaxBodyBytes:          int64(l.int("MAX_BODY_BYTES", 1<<20, 1)),
+		MaxRepeatedValues:     l.int("MAX_REPEATED_VALUES", 20, 1),
+		AllowedOrigins:        l.string("ALLOWED_ORIGINS", ""),
+		TrailingSlashMode:     l.oneOf("TRAILING_SLASH_MODE", "rewrite", "rewrite", "redirect"),
+		RateLimitRPS:          l.rate("RATE_LIMIT_RPS"),
+		RateLimitTrustProxy:   l.bool("RATE_LIMIT_TRUST_PROXY", false),
+	}
+	cfg.RateLimitBurst = l.int("RATE_LIMIT_BURST", int(math.Ceil(cfg.RateLimitRPS)), 1)
+	return cfg
+}
+
+func (l *loader) database(defaultName string) Database {
+	return Database{
+		Driver:     l.oneOf("DB_DRIVER", "postgres", "postgres", "sqlite"),
+		Host:       l.string("DB_HOST", "localhost"),
+		Port:       l.port("DB_PORT", 5432),
+		User:       l.string("DB_USER", "postgres"),
+		Password:   l.string("DB_PASSWORD", "password"),
+		Name:       l.string("DB_NAME", defaultName),
+		SSLMode:    l.oneOf("DB_SSLMODE", "disable", "disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
+		SQLitePath: l.string("DB_SQLITE_PATH", defaultName+".db"),
+
+		ConnectAttempts:   l.int("DB_CONNECT_ATTEMPTS", 10, 1),
+		ConnectRetryDelay: l.duration("DB_CONNECT_RETRY_DELAY", 2*time.Second),
+
+		MaxOpenConns:    l.int("DB_MAX_OPEN_CONNS", 25, 1),
+		MaxIdleConns:    l.int("DB_MAX_IDLE_CONNS", 5, 0),
+		ConnMaxLifetime: l.duration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+	}
+}
+
+// errInvalid wraps every error Load reports
+var errInvalid = errors.New("invalid configuration")
+
+// fail records that key has a malformed value
+func (l *loader) fail(key, value, reason string) {
+	l.errs = append(l.errs, fmt.Errorf("%w: %s=%q %s", errInvalid, key, value, reason))
+}
+
+// err joins everything recorded by fail, or returns nil
+func (l *loader) err() error {
+	return errors.Join(l.errs...)
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.CategoryCase != "" || cfg.AllowedCategories != nil {
+		t.Errorf("category case %q, allowlist %q, want categories left as given", cfg.CategoryCase, cfg.AllowedCategories)
+	}
+	if cfg.ReorderPoint != 0 || cfg.ReorderQuantity != 0 {
+		t.Errorf("reorder point %d, quantity %d, want replenishment disabled", cfg.ReorderPoint, cfg.ReorderQuantity)
+	}
+	if cfg.CatalogSnapshotInterval != 0 {
+		t.Errorf("snapshot interval = %s, want the snapshot disabled", cfg.CatalogSnapshotInterval)
+	}
+}
+
+func TestLoadFeatureSettings(t *testing.T) {
+	t.Setenv("CATEGORY_CASE", "title")
+	t.Setenv("ALLOWED_CATEGORIES", "Books, Home & Garden")
+	t.Setenv("REORDER_POINT", "3")
+	t.Setenv("REORDER_QUANTITY", "25")
+	t.Setenv("CATALOG_SNAPSHOT_INTERVAL", "1m")
+	t.Setenv("FEATURED_RANDOM_SEED", "42")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.CategoryCase != "title" {
+		t.Errorf("category case = %q, want title", cfg.CategoryCase)
+	}
+	if want := []string{"Books", "Home & Garden"}; !reflect.DeepEqual(cfg.AllowedCategories, want) {
+		t.Errorf("allowed categories = %q, want %q", cfg.AllowedCategories, want)
+	}
+	if cfg.ReorderPoint != 3 || cfg.ReorderQuantity != 25 {
+		t.Errorf("reorder point %d, quantity %d, want 3 and 25", cfg.ReorderPoint, cfg.ReorderQuantity)
+	}
+	if cfg.CatalogSnapshotInterval != time.Minute {
+		t.Errorf("snapshot interval = %s, want 1m", cfg.CatalogSnapshotInterval)
+	}
+	if cfg.FeaturedRandomSeed != 42 {
+		t.Errorf("featured seed = %d, want 42", cfg.FeaturedRandomSeed)
+	}
+}
+
+func TestLoadRejectsMalformedFeatureSettings(t *testing.T) {
+	tests := []struct{ key, value string }{
+		{"CATEGORY_CASE", "upper"},
+		{"REORDER_POINT", "-1"},
+		{"REORDER_QUANTITY", "lots"},
+		{"CATALOG_SNAPSHOT_INTERVAL", "0s"},
+		{"FEATURED_RANDOM_SEED", "lucky"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			if _, err := Load(); !errors.Is(err, errInvalid) {
+				t.Errorf("err = %v, want an invalid configuration", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"math"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loader reads typed values from the environment, collecting an error for
+// every malformed one instead of stopping at the first
+type loader struct {
+	errs []error
+}
+
+func (l *loader) string(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// list reads a comma-separated list, dropping empty entries
+func (l *loader) list(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// int reads an integer that must be at least min
+func (l *loader) int(key string, defaultValue, min int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		l.fail(key, value, "is not an integer")
+		return defaultValue
+	}
+	if n < min {
+		l.fail(key, value, "must be at least "+strconv.Itoa(min))
+		return defaultValue
+	}
+	return n
+}
+
+func (l *loader) int64(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		l.fail(key, value, "is not an integer")
+		return defaultValue
+	}
+	return n
+}
+
+func (l *loader) port(key string, defaultValue int) int {
+	port := l.int(key, defaultValue, 1)
+	if port > 65535 {
+		l.fail(key, os.Getenv(key), "is not a valid port")
+		return defaultValue
+	}
+	return port
+}
+
+func (l *loader) bool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.fail(key, value, "must be true or false")
+		return defaultValue
+	}
+	return b
+}
+
+// duration reads a positive Go duration such as "5s" or "1500ms"
+func (l *loader) duration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		l.fail(key, value, "must be a positive duration such as 5s")
+		return defaultValue
+	}
+	return d
+}
+
+// rate reads a positive, finite requests-per-second rate; unset is zero
+func (l *loader) rate(key string) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		l.fail(key, value, "must be a positive number")
+		return 0
+	}
+	return rate
+}
+
+// url reads an absolute http or https URL, without a trailing slash
+func (l *loader) url(key, defaultValue string) string {
+	value := l.string(key, defaultValue)
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		l.fail(key, value, "must be an absolute http:// or https:// URL")
+		return defaultValue
+	}
+	return strings.TrimSuffix(value, "/")
+}
+
+// oneOf reads a value that must be one of allowed, or else be unset and take
+// defaultValue
+func (l *loader) oneOf(key, defaultValue string, allowed ...string) string {
+	value := l.string(key, defaultValue)
+	if value == defaultValue {
+		return value
+	}
+	return l.check(key, value, defaultValue, allowed...)
+}
+
+// logLevel reads LOG_LEVEL, defaulting to info
+func (l *loader) logLevel(key string) string {
+	value := strings.ToLower(strings.TrimSpace(l.string(key, "info")))
+	if value == "warning" {
+		value = "warn"
+	}
+	return l.check(key, value, "info", "debug", "info", "warn", "error")
+}
+
+// check is oneOf for a value that has already been read and normalized
+func (l *loader) check(key, value, defaultValue string, allowed ...string) string {
+	for _, a := range allowed {
+		if value == a {
+			return value
+		}
+	}
+	l.fail(key, value, "must be one of "+strings.Join(allowed, ", "))
+	return defaultValue
+}
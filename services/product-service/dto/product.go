@@ -4,27 +4,174 @@ import "time"
 
 // CreateProductRequest represents the request payload for creating a product
 type CreateProductRequest struct {
-	Name        string  `json:"name" validate:"required"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" validate:"required,gt=0"`
-	Category    string  `json:"category" validate:"required"`
+	Name           string     `json:"name" validate:"required"`
+	Description    string     `json:"description"`
+	Price          float64    `json:"price" validate:"required,gt=0"`
+	Currency       string     `json:"currency,omitempty"` // ISO 4217, USD when omitted
+	Category       string     `json:"category" validate:"required"`
+	Barcode        string     `json:"barcode,omitempty"`
+	FeaturedWeight float64    `json:"featured_weight" validate:"gte=0"`
+	WeightGrams    int        `json:"weight_grams" validate:"gte=0"`
+	DimensionsCM   Dimensions `json:"dimensions_cm"`
+	Stock          int        `json:"stock" validate:"gte=0"`
+	// AvailableFrom and AvailableUntil optionally bound when the product can
+	// be ordered, e.g. for pre-orders or flash sales
+	AvailableFrom  *time.Time `json:"available_from,omitempty"`
+	AvailableUntil *time.Time `json:"available_until,omitempty"`
 }
 
-// UpdateProductRequest represents the request payload for updating a product
+// UpdateProductRequest represents the request payload for updating a
+// product. Stock is left out: it only changes through AdjustStockRequest.
 type UpdateProductRequest struct {
-	Name        string  `json:"name" validate:"required"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" validate:"required,gt=0"`
-	Category    string  `json:"category" validate:"required"`
+	Name           string     `json:"name" validate:"required"`
+	Description    string     `json:"description"`
+	Price          float64    `json:"price" validate:"required,gt=0"`
+	Currency       string     `json:"currency,omitempty"` // ISO 4217, unchanged when omitted
+	Category       string     `json:"category" validate:"required"`
+	Barcode        string     `json:"barcode,omitempty"`
+	FeaturedWeight float64    `json:"featured_weight" validate:"gte=0"`
+	WeightGrams    int        `json:"weight_grams" validate:"gte=0"`
+	DimensionsCM   Dimensions `json:"dimensions_cm"`
+	// AvailableFrom and AvailableUntil optionally bound when the product can
+	// be ordered, e.g. for pre-orders or flash sales
+	AvailableFrom  *time.Time `json:"available_from,omitempty"`
+	AvailableUntil *time.Time `json:"available_until,omitempty"`
+}
+
+// PatchProductRequest represents the request payload for partially updating
+// a product. Only the fields present in the JSON are changed; an empty
+// barcode clears it. Like a PUT it can't change stock.
+type PatchProductRequest struct {
+	Name           *string     `json:"name,omitempty"`
+	Description    *string     `json:"description,omitempty"`
+	Price          *float64    `json:"price,omitempty" validate:"omitempty,gt=0"`
+	Currency       *string     `json:"currency,omitempty"`
+	Category       *string     `json:"category,omitempty"`
+	Barcode        *string     `json:"barcode,omitempty"`
+	FeaturedWeight *float64    `json:"featured_weight,omitempty" validate:"omitempty,gte=0"`
+	WeightGrams    *int        `json:"weight_grams,omitempty" validate:"omitempty,gte=0"`
+	DimensionsCM   *Dimensions `json:"dimensions_cm,omitempty"`
+}
+
+// BulkCategoryRequest represents the request payload for recategorizing products
+type BulkCategoryRequest struct {
+	IDs      []uint `json:"ids" validate:"required"`
+	Category string `json:"category" validate:"required"`
+}
+
+// BulkCategoryResponse reports the outcome of a bulk category assignment
+type BulkCategoryResponse struct {
+	Updated    int64  `json:"updated"`
+	MissingIDs []uint `json:"missing_ids"`
+}
+
+// AdjustStockRequest represents the request payload for changing a product's
+// stock by a relative amount
+type AdjustStockRequest struct {
+	Delta int `json:"delta" validate:"required"`
 }
 
 // ProductResponse represents the response payload for product operations
 type ProductResponse struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Category    string    `json:"category"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	Price          float64    `json:"price"`
+	Currency       string     `json:"currency"`
+	Category       string     `json:"category"`
+	Barcode        string     `json:"barcode,omitempty"`
+	FeaturedWeight float64    `json:"featured_weight"`
+	WeightGrams    int        `json:"weight_grams"`
+	DimensionsCM   Dimensions `json:"dimensions_cm"`
+	Stock          int        `json:"stock"`
+	AvailableFrom  *time.Time `json:"available_from,omitempty"`
+	AvailableUntil *time.Time `json:"available_until,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	// DeletedAt is set on soft-deleted products, which are only listed for
+	// admins with include_deleted=true
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Dimensions represents the physical size of a product in centimeters
+type Dimensions struct {
+	Length float64 `json:"length" validate:"gte=0"`
+	Width  float64 `json:"width" validate:"gte=0"`
+	Height float64 `json:"height" validate:"gte=0"`
+}
+
+// PriceStats summarizes the prices of the products in a category
+type PriceStats struct {
+	Category string  `json:"category"`
+	Count    int64   `json:"count"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Average  float64 `json:"average"`
+	Median   float64 `json:"median"`
+}
+
+// Pagination selects a window of a listing
+type Pagination struct {
+	Limit  int
+	Offset int
+}
+
+// ProductListResponse is a page of products with the information clients
+// need to render pagination controls
+type ProductListResponse struct {
+	Items  []ProductResponse `json:"items"`
+	Total  int64             `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+	// Stale is set when the database was unavailable and the page was served
+	// from the catalog snapshot taken at StaleAsOf
+	Stale     bool       `json:"stale,omitempty"`
+	StaleAsOf *time.Time `json:"stale_as_of,omitempty"`
+}
+
+// ProductUsageResponse describes where a product is referenced
+type ProductUsageResponse struct {
+	ProductID        uint                `json:"product_id"`
+	Orders           *ProductOrderUsage  `json:"orders"` // null when the order service is unavailable
+	CategorySiblings ProductListResponse `json:"category_siblings"`
+	Warning          string              `json:"warning,omitempty"`
+}
+
+// ProductOrderUsage counts the orders for a product with a sample of the
+// most recent ones
+type ProductOrderUsage struct {
+	Total  int64           `json:"total"`
+	Recent []OrderResponse `json:"recent"`
+}
+
+// OrderResponse represents order data from the order service
+type OrderResponse struct {
+	ID         uint      `json:"id"`
+	UserID     uint      `json:"user_id"`
+	ProductID  uint      `json:"product_id"`
+	Quantity   uint      `json:"quantity"`
+	TotalPrice float64   `json:"total_price"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReadinessResponse reports whether the service can serve traffic and the
+// status of each dependency it checked
+type ReadinessResponse struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyHealth `json:"dependencies"`
+}
+
+// DependencyHealth is the status of a single dependency
+type DependencyHealth struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// CategoryListResponse lists the categories products can be assigned.
+// Restricted is set when they come from the configured allowlist rather than
+// the categories already in use.
+type CategoryListResponse struct {
+	Categories []string `json:"categories"`
+	Restricted bool     `json:"restricted"`
 }
@@ -0,0 +1,39 @@
+// Package events publishes domain events raised by the product service.
+// There is no message broker yet, so events are written to the log as one
+// JSON object per line for collectors to pick up.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// ReorderNeeded is raised when a product's stock drops to its reorder point
+const ReorderNeeded = "product.reorder_needed"
+
+// Event is the envelope every published event is written in
+type Event struct {
+	Name       string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// Publisher delivers events to their consumers
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// LogPublisher writes events to the standard logger
+type LogPublisher struct{}
+
+// Publish logs event as a single JSON line
+func (LogPublisher) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	log.Printf("EVENT: %s", body)
+	return nil
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// isAdmin reports whether the request carries the configured admin token.
+// Admin-only operations are disabled when no token is configured.
+func isAdmin(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	provided := r.Header.Get("X-Admin-Token")
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
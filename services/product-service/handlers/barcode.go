@@ -0,0 +1,27 @@
+package handlers
+
+// validBarcode reports whether code is a well-formed 12-digit UPC-A or
+// 13-digit EAN-13 barcode, including a correct check digit
+func validBarcode(code string) bool {
+	if len(code) != 12 && len(code) != 13 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	// Weights alternate 3 and 1 starting from the digit left of the check
+	// digit, which works for both UPC-A and EAN-13
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		digit := int(code[i] - '0')
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	check := (10 - sum%10) % 10
+	return check == int(code[len(code)-1]-'0')
+}
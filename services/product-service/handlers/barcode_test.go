@@ -0,0 +1,24 @@
+package handlers
+
+import "testing"
+
+func TestValidBarcode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"036000291452", true},   // UPC-A
+		{"4006381333931", true},  // EAN-13
+		{"036000291453", false},  // wrong check digit
+		{"4006381333930", false}, // wrong check digit
+		{"03600029145", false},   // too short
+		{"40063813339310", false},
+		{"03600029145X", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := validBarcode(tt.code); got != tt.want {
+			t.Errorf("validBarcode(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import "strings"
+
+// defaultCurrency is assumed for products created without a currency, which
+// keeps clients written before prices carried one working unchanged
+const defaultCurrency = "USD"
+
+// currencies is the set of active ISO 4217 currency codes
+var currencies = func() map[string]bool {
+	codes := `AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF
+		BMD BND BOB BRL BSD BTN BWP BYN BZD CAD CDF CHF CLP CNY COP CRC CUP CVE
+		CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GNF GTQ
+		GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF
+		KPW KRW KWD KYD KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP
+		MRU MUR MVR MWK MXN MYR MZN NAD NGN NIO NOK NPR NZD OMR PAB PEN PGK PHP
+		PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD
+		SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH UGX USD UYU
+		UZS VES VND VUV WST XAF XCD XOF XPF YER ZAR ZMW ZWG`
+	set := make(map[string]bool)
+	for _, code := range strings.Fields(codes) {
+		set[code] = true
+	}
+	return set
+}()
+
+// normalizeCurrency upper-cases a currency code and reports whether it is a
+// known ISO 4217 code
+func normalizeCurrency(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return code, currencies[code]
+}
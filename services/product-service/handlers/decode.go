@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-service/apperror"
+	"strings"
+)
+
+// decodeJSON decodes the request body into v. Fields v doesn't declare are
+// rejected so a typo such as "prodct_id" fails instead of leaving a zero
+// value, and a body cut off by middleware.LimitBody is reported as too large.
+func decodeJSON(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err == nil {
+		return nil
+	}
+
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return apperror.TooLarge("request body too large, limit is %d bytes", maxErr.Limit)
+	}
+	// encoding/json has no typed error for unknown fields
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return apperror.Validation("unknown field %s", field)
+	}
+	return apperror.Validation("Invalid JSON")
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"product-service/apperror"
+	"product-service/dto"
+	"product-service/middleware"
+	pb "product-service/proto/proto"
+	"product-service/services"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ProductGRPCServer serves the gRPC ProductService defined in
+// proto/product.proto. It shares ProductService with the REST handlers, so
+// both transports apply the same business rules.
+type ProductGRPCServer struct {
+	pb.UnimplementedProductServiceServer
+	productService *services.ProductService
+	jwtSecret      []byte
+	roundPrices    bool
+}
+
+// NewProductGRPCServer creates a gRPC server for productService. Writes
+// require a bearer JWT signed with jwtSecret in the authorization metadata,
+// unless jwtSecret is empty. roundPrices is applied as by the REST handler.
+func NewProductGRPCServer(productService *services.ProductService, jwtSecret []byte, roundPrices bool) *ProductGRPCServer {
+	return &ProductGRPCServer{productService: productService, jwtSecret: jwtSecret, roundPrices: roundPrices}
+}
+
+// GetProduct returns one product, or NotFound
+func (s *ProductGRPCServer) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.ProductResponse, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Invalid product ID")
+	}
+
+	product, err := s.productService.GetProduct(ctx, uint(req.GetId()), false)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return productToProto(product), nil
+}
+
+// GetProductsByIDs returns the products with the given IDs, ordered by ID.
+// IDs that don't exist are absent from the response.
+func (s *ProductGRPCServer) GetProductsByIDs(ctx context.Context, req *pb.GetProductsByIDsRequest) (*pb.GetProductsByIDsResponse, error) {
+	seen := make(map[uint]bool, len(req.GetIds()))
+	ids := make([]uint, 0, len(req.GetIds()))
+	for _, id := range req.GetIds() {
+		if id == 0 {
+			return nil, status.Error(codes.InvalidArgument, "ids must be positive integers")
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			ids = append(ids, uint(id))
+		}
+	}
+	switch {
+	case len(ids) == 0:
+		return nil, status.Error(codes.InvalidArgument, "ids is required")
+	case len(ids) > services.MaxBatchIDs:
+		return nil, status.Errorf(codes.InvalidArgument, "ids must not list more than %d IDs", services.MaxBatchIDs)
+	}
+
+	products, err := s.productService.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &pb.GetProductsByIDsResponse{Products: make([]*pb.ProductResponse, 0, len(products))}
+	for i := range products {
+		resp.Products = append(resp.Products, productToProto(&products[i]))
+	}
+	return resp, nil
+}
+
+// CreateProduct validates and stores a new product, like POST /products
+func (s *ProductGRPCServer) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.ProductResponse, error) {
+	if err := s.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	createReq, err := createRequestFromProto(req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if err := validateCreateProduct(&createReq, s.roundPrices); err != nil {
+		return nil, grpcError(err)
+	}
+
+	product, err := s.productService.CreateProduct(ctx, createReq)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return productToProto(product), nil
+}
+
+// authenticate checks the bearer token in the authorization metadata, the
+// gRPC counterpart of middleware.RequireAuth
+func (s *ProductGRPCServer) authenticate(ctx context.Context) error {
+	if len(s.jwtSecret) == 0 {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var authorization string
+	if values := md.Get("authorization"); len(values) > 0 {
+		authorization = values[0]
+	}
+	if _, err := middleware.Authenticate(s.jwtSecret, authorization); err != nil {
+		return status.Error(codes.Unauthenticated, "Unauthorized: "+err.Error())
+	}
+	return nil
+}
+
+// grpcError maps an application error to the gRPC status matching its HTTP
+// status in the REST API
+func grpcError(err error) error {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		return status.Error(codes.Internal, "internal server error")
+	}
+
+	switch appErr.Code {
+	case apperror.CodeNotFound:
+		return status.Error(codes.NotFound, appErr.Error())
+	case apperror.CodeValidation:
+		return status.Error(codes.InvalidArgument, appErr.Error())
+	case apperror.CodeForbidden:
+		return status.Error(codes.PermissionDenied, appErr.Error())
+	case apperror.CodeConflict:
+		return status.Error(codes.AlreadyExists, appErr.Error())
+	case apperror.CodeDownstream:
+		return status.Error(codes.Unavailable, appErr.Error())
+	case apperror.CodeTooLarge:
+		return status.Error(codes.ResourceExhausted, appErr.Error())
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
+
+// createRequestFromProto converts a gRPC create request to the DTO the REST
+// API decodes into
+func createRequestFromProto(req *pb.CreateProductRequest) (dto.CreateProductRequest, error) {
+	from, err := parseProtoTime("available_from", req.GetAvailableFrom())
+	if err != nil {
+		return dto.CreateProductRequest{}, err
+	}
+	until, err := parseProtoTime("available_until", req.GetAvailableUntil())
+	if err != nil {
+		return dto.CreateProductRequest{}, err
+	}
+
+	return dto.CreateProductRequest{
+		Name:           req.GetName(),
+		Description:    req.GetDescription(),
+		Price:          req.GetPrice(),
+		Currency:       req.GetCurrency(),
+		Category:       req.GetCategory(),
+		Barcode:        req.GetBarcode(),
+		FeaturedWeight: req.GetFeaturedWeight(),
+		WeightGrams:    int(req.GetWeightGrams()),
+		DimensionsCM: dto.Dimensions{
+			Length: req.GetDimensionsCm().GetLength(),
+			Width:  req.GetDimensionsCm().GetWidth(),
+			Height: req.GetDimensionsCm().GetHeight(),
+		},
+		Stock:          int(req.GetStock()),
+		AvailableFrom:  from,
+		AvailableUntil: until,
+	}, nil
+}
+
+// parseProtoTime parses an optional RFC 3339 timestamp field
+func parseProtoTime(field, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, apperror.Validation("%s must be an RFC 3339 timestamp", field)
+	}
+	return &t, nil
+}
+
+// productToProto converts a product DTO to its gRPC message
+func productToProto(product *dto.ProductResponse) *pb.ProductResponse {
+	return &pb.ProductResponse{
+		Id:             uint32(product.ID),
+		Name:           product.Name,
+		Description:    product.Description,
+		Price:          product.Price,
+		Currency:       product.Currency,
+		Category:       product.Category,
+		CreatedAt:      product.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:      product.UpdatedAt.Format(time.RFC3339Nano),
+		Barcode:        product.Barcode,
+		FeaturedWeight: product.FeaturedWeight,
+		WeightGrams:    int32(product.WeightGrams),
+		DimensionsCm: &pb.Dimensions{
+			Length: product.DimensionsCM.Length,
+			Width:  product.DimensionsCM.Width,
+			Height: product.DimensionsCM.Height,
+		},
+		Stock:          int32(product.Stock),
+		AvailableFrom:  formatProtoTime(product.AvailableFrom),
+		AvailableUntil: formatProtoTime(product.AvailableUntil),
+	}
+}
+
+// formatProtoTime formats an optional timestamp, empty when unset
+func formatProtoTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	pb "product-service/proto/proto"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// signJWT mints an HS256 token for sub that expires in an hour
+func signJWT(secret []byte, sub uint) string {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims := enc.EncodeToString([]byte(fmt.Sprintf(`{"sub":"%d","exp":%d}`, sub, time.Now().Add(time.Hour).Unix())))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(header + "." + claims))
+	return header + "." + claims + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+func TestGRPCCreateProduct(t *testing.T) {
+	secret := []byte("secret")
+	s := NewProductGRPCServer(newTestHandler(t).productService, secret, false)
+	authed := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+signJWT(secret, 1)))
+	valid := &pb.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  *pb.CreateProductRequest
+		want codes.Code
+	}{
+		{"no token", context.Background(), valid, codes.Unauthenticated},
+		{"wrong token", metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+signJWT([]byte("other"), 1))), valid, codes.Unauthenticated},
+		{"invalid product", authed, &pb.CreateProductRequest{Name: "Lamp", Price: -1, Category: "home"}, codes.InvalidArgument},
+		{"valid", authed, valid, codes.OK},
+	}
+	for _, tt := range tests {
+		product, err := s.CreateProduct(tt.ctx, tt.req)
+		if code := status.Code(err); code != tt.want {
+			t.Errorf("%s: code = %s, want %s (%v)", tt.name, code, tt.want, err)
+			continue
+		}
+		if tt.want == codes.OK && (product.GetId() == 0 || product.GetName() != "Lamp") {
+			t.Errorf("%s: product = %v, want the stored Lamp", tt.name, product)
+		}
+	}
+
+	if _, err := s.GetProduct(context.Background(), &pb.GetProductRequest{Id: 99}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetProduct(99): err = %v, want NotFound", err)
+	}
+}
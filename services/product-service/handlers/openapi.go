@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"net/http"
+	"product-service/apperror"
+	"product-service/dto"
+	"product-service/openapi"
+)
+
+// errorResponses documents error statuses, all answered with the JSON error
+// body written by apperror.WriteError
+func errorResponses(statuses ...int) []openapi.Response {
+	responses := make([]openapi.Response, len(statuses))
+	for i, status := range statuses {
+		responses[i] = openapi.Response{Status: status, Body: apperror.ErrorResponse{}}
+	}
+	return responses
+}
+
+// okResponses documents a 200 response with body
+func okResponses(body interface{}, errors ...int) []openapi.Response {
+	return append([]openapi.Response{{Status: http.StatusOK, Body: body}}, errorResponses(errors...)...)
+}
+
+var (
+	idParam        = openapi.Param{Name: "id", Type: "integer", Required: true, Description: "Product ID"}
+	pathID         = openapi.Param{Name: "id", In: "path", Type: "integer", Description: "Product ID"}
+	tzParam        = openapi.Query("tz", "string", "IANA zone to render timestamps in, e.g. America/New_York")
+	adminToken     = openapi.Param{Name: "X-Admin-Token", In: "header", Type: "string", Required: true, Description: "Must match ADMIN_TOKEN"}
+	ifNoneMatch    = openapi.Param{Name: "If-None-Match", In: "header", Type: "string", Description: "ETag of a cached copy; answered with 304 if unchanged"}
+	idempotencyKey = openapi.Param{Name: "Idempotency-Key", In: "header", Type: "string", Description: "Required when REQUIRE_IDEMPOTENCY_KEY is enabled; repeated keys are not deduplicated"}
+)
+
+// Operations describes every route the product service registers. Add new
+// routes here alongside their registration in main so GET /openapi.json
+// stays complete.
+func Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/products",
+			Summary: "Get a product by ?id= or ?barcode=, or a page of products, optionally by category or name search",
+			Params: []openapi.Param{
+				openapi.Query("id", "integer", "Product ID"),
+				openapi.Query("barcode", "string", "12-digit UPC or 13-digit EAN"),
+				openapi.Query("category", "string", "Only products in this category"),
+				openapi.Query("q", "string", "Case-insensitive substring of the name"),
+				openapi.Query("limit", "integer", "Page size, default 20, max 100"),
+				openapi.Query("offset", "integer", "Number of products to skip"),
+				openapi.Query("include_deleted", "boolean", "Include soft-deleted products; requires X-Admin-Token"),
+				tzParam, ifNoneMatch,
+				{Name: "X-Admin-Token", In: "header", Type: "string", Description: "Required with include_deleted=true"},
+			},
+			Responses: append(okResponses(openapi.OneOf(dto.ProductResponse{}, dto.ProductListResponse{}), 400, 403, 404),
+				openapi.Response{Status: http.StatusNotModified}),
+		},
+		{
+			Method: http.MethodPost, Path: "/products", Auth: true,
+			Summary: "Create a product",
+			Params:  []openapi.Param{idempotencyKey},
+			Body:    dto.CreateProductRequest{},
+			Responses: append([]openapi.Response{{Status: http.StatusCreated, Body: dto.ProductResponse{}}},
+				errorResponses(400, 401, 409, 413)...),
+		},
+		{
+			Method: http.MethodPut, Path: "/products", Auth: true,
+			Summary:   "Replace a product",
+			Params:    []openapi.Param{idParam},
+			Body:      dto.UpdateProductRequest{},
+			Responses: okResponses(dto.ProductResponse{}, 400, 401, 404, 409, 413),
+		},
+		{
+			Method: http.MethodPatch, Path: "/products", Auth: true,
+			Summary:   "Change only the fields sent",
+			Params:    []openapi.Param{idParam},
+			Body:      dto.PatchProductRequest{},
+			Responses: okResponses(dto.ProductResponse{}, 400, 401, 404, 409, 413),
+		},
+		{
+			Method: http.MethodDelete, Path: "/products", Auth: true,
+			Summary: "Soft-delete a product, or remove it permanently with ?hard=true and X-Admin-Token",
+			Params: []openapi.Param{
+				idParam,
+				openapi.Query("hard", "boolean", "Delete permanently"),
+				{Name: "X-Admin-Token", In: "header", Type: "string", Description: "Required with hard=true"},
+			},
+			Responses: append([]openapi.Response{{Status: http.StatusNoContent}},
+				errorResponses(400, 401, 403, 404)...),
+		},
+		{
+			Method: http.MethodGet, Path: "/products/{id}",
+			Summary: "Get a product by ID",
+			Params: []openapi.Param{
+				pathID,
+				openapi.Query("include_deleted", "boolean", "Find a soft-deleted product; requires X-Admin-Token"),
+				tzParam, ifNoneMatch,
+				{Name: "X-Admin-Token", In: "header", Type: "string", Description: "Required with include_deleted=true"},
+			},
+			Responses: append(okResponses(dto.ProductResponse{}, 400, 403, 404),
+				openapi.Response{Status: http.StatusNotModified}),
+		},
+		{
+			Method: http.MethodPut, Path: "/products/{id}", Auth: true,
+			Summary:   "Replace a product",
+			Params:    []openapi.Param{pathID},
+			Body:      dto.UpdateProductRequest{},
+			Responses: okResponses(dto.ProductResponse{}, 400, 401, 404, 409, 413),
+		},
+		{
+			Method: http.MethodPatch, Path: "/products/{id}", Auth: true,
+			Summary:   "Change only the fields sent",
+			Params:    []openapi.Param{pathID},
+			Body:      dto.PatchProductRequest{},
+			Responses: okResponses(dto.ProductResponse{}, 400, 401, 404, 409, 413),
+		},
+		{
+			Method: http.MethodDelete, Path: "/products/{id}", Auth: true,
+			Summary: "Soft-delete a product, or remove it permanently with ?hard=true and X-Admin-Token",
+			Params: []openapi.Param{
+				pathID,
+				openapi.Query("hard", "boolean", "Delete permanently"),
+				{Name: "X-Admin-Token", In: "header", Type: "string", Description: "Required with hard=true"},
+			},
+			Responses: append([]openapi.Response{{Status: http.StatusNoContent}},
+				errorResponses(400, 401, 403, 404)...),
+		},
+		{
+			Method: http.MethodPost, Path: "/products/bulk", Auth: true,
+			Summary: "Create up to 100 products in one transaction; any invalid item rejects the batch",
+			Params:  []openapi.Param{idempotencyKey},
+			Body:    []dto.CreateProductRequest{},
+			Responses: append([]openapi.Response{{Status: http.StatusCreated, Body: []dto.ProductResponse{}}},
+				errorResponses(400, 401, 409, 413)...),
+		},
+		{
+			Method: http.MethodPost, Path: "/products/restore", Auth: true,
+			Summary:   "Restore a soft-deleted product",
+			Params:    []openapi.Param{idParam},
+			Responses: okResponses(dto.ProductResponse{}, 400, 401, 404),
+		},
+		{
+			Method: http.MethodPost, Path: "/products/bulk-category", Auth: true,
+			Summary:   "Set the category of several products",
+			Body:      dto.BulkCategoryRequest{},
+			Responses: okResponses(dto.BulkCategoryResponse{}, 400, 401, 413),
+		},
+		{
+			Method: http.MethodGet, Path: "/products/batch",
+			Summary:   "Get up to 100 products by ID; unknown IDs are left out",
+			Params:    []openapi.Param{{Name: "ids", Type: "string", Required: true, Description: "Comma-separated IDs"}},
+			Responses: okResponses([]dto.ProductResponse{}, 400),
+		},
+		{
+			Method: http.MethodPost, Path: "/products/stock", Auth: true,
+			Summary:   "Adjust stock by a relative amount; services may send X-Service-Token instead of a JWT",
+			Params:    []openapi.Param{idParam},
+			Body:      dto.AdjustStockRequest{},
+			Responses: okResponses(dto.ProductResponse{}, 400, 401, 404, 409, 413),
+		},
+		{
+			Method: http.MethodGet, Path: "/products/{id}/usage",
+			Summary:   "Where a product is referenced: its orders and category siblings",
+			Params:    []openapi.Param{pathID, adminToken},
+			Responses: okResponses(dto.ProductUsageResponse{}, 400, 403, 404),
+		},
+		{
+			Method: http.MethodGet, Path: "/products/featured",
+			Summary:   "Random featured products weighted by featured_weight",
+			Params:    []openapi.Param{openapi.Query("count", "integer", "How many to return")},
+			Responses: okResponses([]dto.ProductResponse{}, 400),
+		},
+		{
+			Method: http.MethodGet, Path: "/products/price-stats",
+			Summary:   "Price statistics for a category, or for every category",
+			Params:    []openapi.Param{openapi.Query("category", "string", "Omit for all categories")},
+			Responses: okResponses(openapi.OneOf(dto.PriceStats{}, []dto.PriceStats{})),
+		},
+		{
+			Method: http.MethodGet, Path: "/categories",
+			Summary:   "Categories products can be assigned",
+			Responses: okResponses(dto.CategoryListResponse{}),
+		},
+		{
+			Method: http.MethodGet, Path: "/health",
+			Summary:   "Liveness check",
+			Responses: []openapi.Response{{Status: http.StatusOK, Description: "Plain-text status"}},
+		},
+		{
+			Method: http.MethodGet, Path: "/health/ready",
+			Summary: "Readiness of the database",
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: dto.ReadinessResponse{}},
+				{Status: http.StatusServiceUnavailable, Body: dto.ReadinessResponse{}},
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/openapi.json",
+			Summary:   "This document",
+			Responses: []openapi.Response{{Status: http.StatusOK, Description: "OpenAPI 3.0 document"}},
+		},
+	}
+}
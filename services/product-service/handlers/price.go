@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// decimalPlaces returns the number of digits after the decimal point in the
+// shortest representation of f
+func decimalPlaces(f float64) int {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+// normalizePrice enforces cent precision on a price. Prices with more than two
+// decimal places are rejected, or rounded to the nearest cent when round is
+// set.
+func normalizePrice(price float64, round bool) (float64, bool) {
+	if decimalPlaces(price) <= 2 {
+		return price, true
+	}
+	if round {
+		return math.Round(price*100) / 100, true
+	}
+	return 0, false
+}
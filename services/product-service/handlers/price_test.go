@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"product-service/dto"
+	"testing"
+)
+
+func TestNormalizePrice(t *testing.T) {
+	tests := []struct {
+		price  float64
+		round  bool
+		want   float64
+		wantOK bool
+	}{
+		{19.99, false, 19.99, true},
+		{19.990, false, 19.99, true},
+		{19, false, 19, true},
+		{19.999, false, 0, false},
+		{0.001, false, 0, false},
+		{19.99, true, 19.99, true},
+		{19.999, true, 20, true},
+		{19.994, true, 19.99, true},
+	}
+	for _, tt := range tests {
+		got, ok := normalizePrice(tt.price, tt.round)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("normalizePrice(%v, %v) = %v, %v, want %v, %v", tt.price, tt.round, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCreateProductPricePrecision(t *testing.T) {
+	tests := []struct {
+		price      string
+		round      bool
+		wantStatus int
+		wantPrice  float64
+	}{
+		{"19", false, http.StatusCreated, 19},
+		{"19.99", false, http.StatusCreated, 19.99},
+		{"19.990", false, http.StatusCreated, 19.99},
+		{"19.999", false, http.StatusBadRequest, 0},
+		{"19.999", true, http.StatusCreated, 20},
+	}
+	for _, tt := range tests {
+		h := newTestHandler(t)
+		h.roundPrices = tt.round
+		body := json.RawMessage(`{"name": "Lamp", "category": "home", "price": ` + tt.price + `}`)
+
+		rec := serve(h.CreateProduct, http.MethodPost, "/products", body)
+		if rec.Code != tt.wantStatus {
+			t.Errorf("price %s (round %v): status = %d, want %d: %s", tt.price, tt.round, rec.Code, tt.wantStatus, rec.Body)
+			continue
+		}
+		if rec.Code != http.StatusCreated {
+			continue
+		}
+		product, err := h.productService.GetProduct(context.Background(), 1, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if product.Price != tt.wantPrice {
+			t.Errorf("price %s (round %v): stored %v, want %v", tt.price, tt.round, product.Price, tt.wantPrice)
+		}
+	}
+}
+
+func TestUpdateProductPricePrecision(t *testing.T) {
+	h := newTestHandler(t)
+	createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+
+	put := json.RawMessage(`{"name": "Lamp", "category": "home", "price": 19.999}`)
+	if rec := serve(h.UpdateProduct, http.MethodPut, "/products?id=1", put); rec.Code != http.StatusBadRequest {
+		t.Errorf("PUT: status = %d, want 400", rec.Code)
+	}
+	put = json.RawMessage(`{"name": "Lamp", "category": "home", "price": 24.990}`)
+	if rec := serve(h.UpdateProduct, http.MethodPut, "/products?id=1", put); rec.Code != http.StatusOK {
+		t.Errorf("PUT: status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	patch := json.RawMessage(`{"price": 19.999}`)
+	if rec := serve(h.PatchProduct, http.MethodPatch, "/products?id=1", patch); rec.Code != http.StatusBadRequest {
+		t.Errorf("PATCH: status = %d, want 400", rec.Code)
+	}
+	patch = json.RawMessage(`{"price": 24.990}`)
+	if rec := serve(h.PatchProduct, http.MethodPatch, "/products?id=1", patch); rec.Code != http.StatusOK {
+		t.Errorf("PATCH: status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+}
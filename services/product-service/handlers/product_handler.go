@@ -2,20 +2,30 @@ package handlers
 
 import (
 	"encoding/json"
+	"log"
+	"log/slog"
 	"net/http"
+	"product-service/apperror"
 	"product-service/dto"
+	"product-service/middleware"
 	"product-service/services"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // ProductHandler handles HTTP requests for product operations
 type ProductHandler struct {
 	productService *services.ProductService
+	adminToken     string
+	roundPrices    bool
 }
 
-// NewProductHandler creates a new product handler
-func NewProductHandler(productService *services.ProductService) *ProductHandler {
-	return &ProductHandler{productService: productService}
+// NewProductHandler creates a new product handler. adminToken unlocks the
+// admin-only operations, and roundPrices rounds prices with sub-cent
+// precision instead of rejecting them.
+func NewProductHandler(productService *services.ProductService, adminToken string, roundPrices bool) *ProductHandler {
+	return &ProductHandler{productService: productService, adminToken: adminToken, roundPrices: roundPrices}
 }
 
 // CreateProduct handles POST /products
@@ -26,20 +36,19 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.CreateProductRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		apperror.WriteError(w, err)
 		return
 	}
 
-	// Basic validation
-	if req.Name == "" || req.Category == "" || req.Price <= 0 {
-		http.Error(w, "Name, category, and valid price are required", http.StatusBadRequest)
+	if err := validateCreateProduct(&req, h.roundPrices); err != nil {
+		apperror.WriteError(w, err)
 		return
 	}
 
-	product, err := h.productService.CreateProduct(req)
+	product, err := h.productService.CreateProduct(r.Context(), req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.WriteError(w, err)
 		return
 	}
 
@@ -48,36 +57,167 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(product)
 }
 
-// GetProduct handles GET /products
+// CreateProducts handles POST /products/bulk. The body is a JSON array of
+// create requests; if any item is invalid the whole batch is rejected with
+// its index, and nothing is inserted.
+func (h *ProductHandler) CreateProducts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var reqs []dto.CreateProductRequest
+	if err := decodeJSON(r, &reqs); err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	switch {
+	case len(reqs) == 0:
+		apperror.WriteError(w, apperror.Validation("At least one product is required"))
+		return
+	case len(reqs) > services.MaxBulkProducts:
+		apperror.WriteError(w, apperror.Validation("At most %d products can be created at once", services.MaxBulkProducts))
+		return
+	}
+
+	for i := range reqs {
+		if err := validateCreateProduct(&reqs[i], h.roundPrices); err != nil {
+			apperror.WriteError(w, apperror.Validation("item %d: %w", i, err))
+			return
+		}
+	}
+
+	products, err := h.productService.CreateProducts(r.Context(), reqs)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(products)
+}
+
+// validateCreateProduct checks a create request and rounds its price to
+// cents when roundPrices is set. Both the REST and gRPC transports use it.
+func validateCreateProduct(req *dto.CreateProductRequest, roundPrices bool) error {
+	if req.Name == "" || req.Category == "" || req.Price <= 0 {
+		return apperror.Validation("Name, category, and valid price are required")
+	}
+
+	if req.Barcode != "" && !validBarcode(req.Barcode) {
+		return apperror.Validation("Barcode must be a valid 12-digit UPC or 13-digit EAN")
+	}
+
+	price, ok := normalizePrice(req.Price, roundPrices)
+	if !ok {
+		return apperror.Validation("Price must have at most two decimal places")
+	}
+	req.Price = price
+
+	if req.Currency == "" {
+		req.Currency = defaultCurrency
+	}
+	currency, ok := normalizeCurrency(req.Currency)
+	if !ok {
+		return apperror.Validation("Currency must be an ISO 4217 code such as USD or EUR")
+	}
+	req.Currency = currency
+
+	if req.FeaturedWeight < 0 {
+		return apperror.Validation("Featured weight must be non-negative")
+	}
+
+	if !validPhysicalAttributes(req.WeightGrams, req.DimensionsCM) {
+		return apperror.Validation("Weight and dimensions must be non-negative")
+	}
+
+	if req.Stock < 0 {
+		return apperror.Validation("Stock must be non-negative")
+	}
+
+	if req.AvailableFrom != nil && req.AvailableUntil != nil && !req.AvailableFrom.Before(*req.AvailableUntil) {
+		return apperror.Validation("available_from must be before available_until")
+	}
+
+	return nil
+}
+
+// GetProduct handles GET /products and GET /products/{id}
 func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	category := r.URL.Query().Get("category")
-	if category != "" {
-		// Return products by category
-		products, err := h.productService.GetProductsByCategory(category)
+	loc, err := parseTimezone(r)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid timezone"))
+		return
+	}
+
+	// Soft-deleted products are only shown to admins who ask for them
+	includeDeleted, err := parseBool(r.URL.Query().Get("include_deleted"), false)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid include_deleted flag"))
+		return
+	}
+	if includeDeleted && !isAdmin(r, h.adminToken) {
+		apperror.WriteError(w, apperror.Forbidden("include_deleted requires admin privileges"))
+		return
+	}
+
+	barcode := r.URL.Query().Get("barcode")
+	if barcode != "" {
+		if !validBarcode(barcode) {
+			apperror.WriteError(w, apperror.Validation("Barcode must be a valid 12-digit UPC or 13-digit EAN"))
+			return
+		}
+
+		product, err := h.productService.GetProductByBarcode(r.Context(), barcode)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperror.WriteError(w, err)
 			return
 		}
+		localizeProduct(product, loc)
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(products)
+		json.NewEncoder(w).Encode(product)
 		return
 	}
 
-	idStr := r.URL.Query().Get("id")
+	idStr := resourceID(r)
 	if idStr == "" {
-		// Return all products
-		products, err := h.productService.GetAllProducts()
+		page, err := parsePagination(r)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperror.WriteError(w, err)
 			return
 		}
 
+		var products *dto.ProductListResponse
+		category := r.URL.Query().Get("category")
+		if r.URL.Query().Has("q") {
+			// Search by name, within the category when one is given
+			query := strings.TrimSpace(r.URL.Query().Get("q"))
+			if query == "" {
+				apperror.WriteError(w, apperror.Validation("Search query q must not be empty"))
+				return
+			}
+			products, err = h.productService.SearchProducts(r.Context(), query, category, page)
+		} else if category != "" {
+			// Return products by category
+			products, err = h.productService.GetProductsByCategory(r.Context(), category, page)
+		} else {
+			// Return all products
+			products, err = h.productService.GetAllProducts(r.Context(), page, includeDeleted)
+		}
+		if err != nil {
+			apperror.WriteError(w, err)
+			return
+		}
+		localizeProducts(products.Items, loc)
+
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(products)
 		return
@@ -85,61 +225,170 @@ func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		apperror.WriteError(w, apperror.Validation("Invalid product ID"))
 		return
 	}
 
-	product, err := h.productService.GetProduct(uint(id))
+	product, err := h.productService.GetProduct(r.Context(), uint(id), includeDeleted)
 	if err != nil {
-		if err.Error() == "product not found" {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.WriteError(w, err)
 		return
 	}
+	localizeProduct(product, loc)
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(product)
+	writeJSONWithETag(w, r, product)
 }
 
-// UpdateProduct handles PUT /products
+// UpdateProduct handles PUT /products/{id} and PUT /products?id=
 func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	idStr := r.URL.Query().Get("id")
+	idStr := resourceID(r)
 	if idStr == "" {
-		http.Error(w, "Product ID is required", http.StatusBadRequest)
+		apperror.WriteError(w, apperror.Validation("Product ID is required"))
 		return
 	}
 
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		apperror.WriteError(w, apperror.Validation("Invalid product ID"))
 		return
 	}
 
 	var req dto.UpdateProductRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		apperror.WriteError(w, err)
 		return
 	}
 
 	if req.Name == "" || req.Category == "" || req.Price <= 0 {
-		http.Error(w, "Name, category, and valid price are required", http.StatusBadRequest)
+		apperror.WriteError(w, apperror.Validation("Name, category, and valid price are required"))
+		return
+	}
+
+	if req.Barcode != "" && !validBarcode(req.Barcode) {
+		apperror.WriteError(w, apperror.Validation("Barcode must be a valid 12-digit UPC or 13-digit EAN"))
+		return
+	}
+
+	price, ok := normalizePrice(req.Price, h.roundPrices)
+	if !ok {
+		apperror.WriteError(w, apperror.Validation("Price must have at most two decimal places"))
+		return
+	}
+	req.Price = price
+
+	if req.Currency != "" {
+		currency, ok := normalizeCurrency(req.Currency)
+		if !ok {
+			apperror.WriteError(w, apperror.Validation("Currency must be an ISO 4217 code such as USD or EUR"))
+			return
+		}
+		req.Currency = currency
+	}
+
+	if req.FeaturedWeight < 0 {
+		apperror.WriteError(w, apperror.Validation("Featured weight must be non-negative"))
+		return
+	}
+
+	if !validPhysicalAttributes(req.WeightGrams, req.DimensionsCM) {
+		apperror.WriteError(w, apperror.Validation("Weight and dimensions must be non-negative"))
 		return
 	}
 
-	product, err := h.productService.UpdateProduct(uint(id), req)
+	if req.AvailableFrom != nil && req.AvailableUntil != nil && !req.AvailableFrom.Before(*req.AvailableUntil) {
+		apperror.WriteError(w, apperror.Validation("available_from must be before available_until"))
+		return
+	}
+
+	product, err := h.productService.UpdateProduct(r.Context(), uint(id), req)
 	if err != nil {
-		if err.Error() == "product not found" {
-			http.Error(w, err.Error(), http.StatusNotFound)
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// PatchProduct handles PATCH /products/{id} and PATCH /products?id=
+func (h *ProductHandler) PatchProduct(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := resourceID(r)
+	if idStr == "" {
+		apperror.WriteError(w, apperror.Validation("Product ID is required"))
+		return
+	}
+
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid product ID"))
+		return
+	}
+
+	var req dto.PatchProductRequest
+	if err := decodeJSON(r, &req); err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	if (req.Name != nil && *req.Name == "") || (req.Category != nil && *req.Category == "") {
+		apperror.WriteError(w, apperror.Validation("Name and category must not be empty"))
+		return
+	}
+
+	if req.Barcode != nil && *req.Barcode != "" && !validBarcode(*req.Barcode) {
+		apperror.WriteError(w, apperror.Validation("Barcode must be a valid 12-digit UPC or 13-digit EAN"))
+		return
+	}
+
+	if req.Price != nil {
+		if *req.Price <= 0 {
+			apperror.WriteError(w, apperror.Validation("Price must be positive"))
+			return
+		}
+		price, ok := normalizePrice(*req.Price, h.roundPrices)
+		if !ok {
+			apperror.WriteError(w, apperror.Validation("Price must have at most two decimal places"))
+			return
+		}
+		req.Price = &price
+	}
+
+	if req.Currency != nil {
+		currency, ok := normalizeCurrency(*req.Currency)
+		if !ok {
+			apperror.WriteError(w, apperror.Validation("Currency must be an ISO 4217 code such as USD or EUR"))
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		req.Currency = &currency
+	}
+
+	if req.FeaturedWeight != nil && *req.FeaturedWeight < 0 {
+		apperror.WriteError(w, apperror.Validation("Featured weight must be non-negative"))
+		return
+	}
+
+	if req.WeightGrams != nil && *req.WeightGrams < 0 {
+		apperror.WriteError(w, apperror.Validation("Weight and dimensions must be non-negative"))
+		return
+	}
+	if req.DimensionsCM != nil && !validPhysicalAttributes(0, *req.DimensionsCM) {
+		apperror.WriteError(w, apperror.Validation("Weight and dimensions must be non-negative"))
+		return
+	}
+
+	product, err := h.productService.PatchProduct(r.Context(), uint(id), req)
+	if err != nil {
+		apperror.WriteError(w, err)
 		return
 	}
 
@@ -147,39 +396,358 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(product)
 }
 
-// DeleteProduct handles DELETE /products
+// DeleteProduct handles DELETE /products/{id} and DELETE /products?id=
 func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	idStr := resourceID(r)
+	if idStr == "" {
+		apperror.WriteError(w, apperror.Validation("Product ID is required"))
+		return
+	}
+
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid product ID"))
+		return
+	}
+
+	hard, err := parseBool(r.URL.Query().Get("hard"), false)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid hard flag"))
+		return
+	}
+	if hard && !isAdmin(r, h.adminToken) {
+		apperror.WriteError(w, apperror.Forbidden("Hard delete requires admin privileges"))
+		return
+	}
+
+	if hard {
+		err = h.productService.HardDeleteProduct(r.Context(), uint(id))
+	} else {
+		err = h.productService.DeleteProduct(r.Context(), uint(id))
+	}
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	if hard {
+		log.Printf("AUDIT: product %d permanently deleted by admin (remote=%s, request_id=%s)", id, r.RemoteAddr, middleware.RequestIDFromContext(r.Context()))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreProduct handles POST /products/restore, undoing a soft delete
+func (h *ProductHandler) RestoreProduct(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := r.URL.Query().Get("id")
+	if idStr == "" {
+		apperror.WriteError(w, apperror.Validation("Product ID is required"))
+		return
+	}
+
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid product ID"))
+		return
+	}
+
+	product, err := h.productService.RestoreProduct(r.Context(), uint(id))
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// resourceID returns the ID a request addresses: the {id} path segment of
+// routes like GET /products/{id}, or else the ?id= query parameter the older
+// routes take
+func resourceID(r *http.Request) string {
+	if id := r.PathValue("id"); id != "" {
+		return id
+	}
+	return r.URL.Query().Get("id")
+}
+
+// GetProductUsage handles GET /products/{id}/usage, an admin view of where a
+// product is referenced. The order section is null with a warning when the
+// order service can't be reached, rather than failing the whole response.
+func (h *ProductHandler) GetProductUsage(w http.ResponseWriter, r *http.Request) {
+	if !isAdmin(r, h.adminToken) {
+		apperror.WriteError(w, apperror.Forbidden("Product usage requires admin privileges"))
+		return
+	}
+
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil || id == 0 {
+		apperror.WriteError(w, apperror.Validation("Invalid product ID"))
+		return
+	}
+
+	siblings, err := h.productService.GetCategorySiblings(r.Context(), uint(id))
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	usage := dto.ProductUsageResponse{ProductID: uint(id), CategorySiblings: *siblings}
+	orders, err := h.productService.FetchProductOrders(r.Context(), uint(id))
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to fetch orders for product usage", "product_id", id, "error", err)
+		usage.Warning = "orders unavailable: order service could not be reached"
+	} else {
+		usage.Orders = orders
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usage)
+}
+
+// GetProductsBatch handles GET /products/batch?ids=1,2,3
+func (h *ProductHandler) GetProductsBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	loc, err := parseTimezone(r)
+	if err != nil {
+		apperror.WriteError(w, apperror.Validation("Invalid timezone"))
+		return
+	}
+
+	ids, err := parseIDList(r.URL.Query().Get("ids"))
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	products, err := h.productService.GetProductsByIDs(r.Context(), ids)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+	localizeProducts(products, loc)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
+// parseIDList parses a comma-separated list of positive IDs, dropping
+// duplicates. At least one and at most services.MaxBatchIDs IDs are allowed.
+func parseIDList(value string) ([]uint, error) {
+	if value == "" {
+		return nil, apperror.Validation("ids is required")
+	}
+
+	parts := strings.Split(value, ",")
+	if len(parts) > services.MaxBatchIDs {
+		return nil, apperror.Validation("ids must not list more than %d IDs", services.MaxBatchIDs)
+	}
+
+	ids := make([]uint, 0, len(parts))
+	seen := make(map[uint]bool, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+		if err != nil || id == 0 {
+			return nil, apperror.Validation("Invalid product ID %q in ids", part)
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, nil
+}
+
+// AdjustStock handles POST /products/stock
+func (h *ProductHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
 	idStr := r.URL.Query().Get("id")
 	if idStr == "" {
-		http.Error(w, "Product ID is required", http.StatusBadRequest)
+		apperror.WriteError(w, apperror.Validation("Product ID is required"))
 		return
 	}
 
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		apperror.WriteError(w, apperror.Validation("Invalid product ID"))
+		return
+	}
+
+	var req dto.AdjustStockRequest
+	if err := decodeJSON(r, &req); err != nil {
+		apperror.WriteError(w, err)
 		return
 	}
 
-	err = h.productService.DeleteProduct(uint(id))
+	if req.Delta == 0 {
+		apperror.WriteError(w, apperror.Validation("Delta must be non-zero"))
+		return
+	}
+
+	product, err := h.productService.AdjustStock(r.Context(), uint(id), req.Delta)
 	if err != nil {
-		if err.Error() == "product not found" {
-			http.Error(w, err.Error(), http.StatusNotFound)
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// BulkAssignCategory handles POST /products/bulk-category
+func (h *ProductHandler) BulkAssignCategory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req dto.BulkCategoryRequest
+	if err := decodeJSON(r, &req); err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	if len(req.IDs) == 0 || req.Category == "" {
+		apperror.WriteError(w, apperror.Validation("IDs and category are required"))
+		return
+	}
+
+	result, err := h.productService.BulkAssignCategory(r.Context(), req)
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetFeaturedProducts handles GET /products/featured
+func (h *ProductHandler) GetFeaturedProducts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	count := services.DefaultFeaturedCount
+	if countStr := r.URL.Query().Get("count"); countStr != "" {
+		n, err := strconv.Atoi(countStr)
+		if err != nil || n <= 0 || n > services.MaxFeaturedCount {
+			apperror.WriteError(w, apperror.Validation("Count must be between 1 and %d", services.MaxFeaturedCount))
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		count = n
+	}
+
+	products, err := h.productService.GetFeaturedProducts(r.Context(), count)
+	if err != nil {
+		apperror.WriteError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
+// GetPriceStats handles GET /products/price-stats
+func (h *ProductHandler) GetPriceStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	category := r.URL.Query().Get("category")
+	if category != "" {
+		stats, err := h.productService.GetPriceStats(r.Context(), category)
+		if err != nil {
+			apperror.WriteError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stats)
+		return
+	}
+
+	stats, err := h.productService.GetPriceStatsByCategory(r.Context())
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GetCategories handles GET /categories
+func (h *ProductHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	categories, err := h.productService.GetCategories(r.Context())
+	if err != nil {
+		apperror.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(categories)
+}
+
+// validPhysicalAttributes reports whether the optional shipping attributes are non-negative
+func validPhysicalAttributes(weightGrams int, dims dto.Dimensions) bool {
+	return weightGrams >= 0 && dims.Length >= 0 && dims.Width >= 0 && dims.Height >= 0
+}
+
+// readinessTimeout bounds how long the readiness database ping may take
+const readinessTimeout = 2 * time.Second
+
+// Ready handles GET /health/ready, answering 503 when the database can't be
+// reached
+func (h *ProductHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	readiness := dto.ReadinessResponse{
+		Status:       "ready",
+		Dependencies: map[string]dto.DependencyHealth{"database": {Status: "up"}},
+	}
+	status := http.StatusOK
+	if err := h.productService.Ping(r.Context(), readinessTimeout); err != nil {
+		readiness.Status = "not_ready"
+		readiness.Dependencies["database"] = dto.DependencyHealth{Status: "down", Error: err.Error()}
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(readiness)
 }
 
-// Health handles GET /health
+// Health handles GET /health as a liveness probe: it only confirms the
+// process is serving
 func (h *ProductHandler) Health(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Product Service is healthy"))
@@ -0,0 +1,517 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"product-service/config"
+	"product-service/dto"
+	"product-service/models"
+	"product-service/services"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestHandler returns a handler backed by a fresh in-memory SQLite
+// database
+func newTestHandler(t *testing.T) *ProductHandler {
+	t.Helper()
+	return newConfiguredTestHandler(t, config.Config{}, "")
+}
+
+// newConfiguredTestHandler is newTestHandler with a service configured by cfg
+// and admin operations unlocked by adminToken
+func newConfiguredTestHandler(t *testing.T, cfg config.Config, adminToken string) *ProductHandler {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:         logger.Discard,
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	// Every connection to :memory: is a separate database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.Product{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return NewProductHandler(services.NewProductService(db, cfg), adminToken, false)
+}
+
+// createProduct inserts a product through the service, bypassing HTTP
+func createProduct(t *testing.T, h *ProductHandler, req dto.CreateProductRequest) *dto.ProductResponse {
+	t.Helper()
+	product, err := h.productService.CreateProduct(context.Background(), req)
+	if err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	return product
+}
+
+// serve runs handler on a request and returns the recorded response
+func serve(handler http.HandlerFunc, method, target string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, target, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+// errorCode returns the code of an apperror JSON body
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode error body %q: %v", rec.Body.String(), err)
+	}
+	return body.Error.Code
+}
+
+func TestGetProductTimezone(t *testing.T) {
+	h := newTestHandler(t)
+	product := createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+
+	rec := serve(h.GetProduct, http.MethodGet, "/products?id=1&tz=America/New_York", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var got struct {
+		CreatedAt string `json:"created_at"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, got.CreatedAt)
+	if err != nil {
+		t.Fatalf("created_at %q: %v", got.CreatedAt, err)
+	}
+	if !createdAt.Equal(product.CreatedAt) {
+		t.Errorf("created_at = %s, want the stored instant %s", createdAt, product.CreatedAt)
+	}
+	ny, _ := time.LoadLocation("America/New_York")
+	_, wantOffset := product.CreatedAt.In(ny).Zone()
+	if _, offset := createdAt.Zone(); offset != wantOffset {
+		t.Errorf("created_at %q has offset %d, want %d", got.CreatedAt, offset, wantOffset)
+	}
+}
+
+func TestGetProductETag(t *testing.T) {
+	h := newTestHandler(t)
+	product := createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/products?id=1", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.GetProduct(rec, req)
+		return rec
+	}
+	rec := get("")
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" {
+		t.Fatalf("status = %d, ETag %q, want 200 with a tag", rec.Code, etag)
+	}
+
+	for _, header := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		if rec := get(header); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: status = %d with %d bytes, want 304 and no body", header, rec.Code, rec.Body.Len())
+		}
+	}
+	if rec := get(`"other"`); rec.Code != http.StatusOK {
+		t.Errorf("different tag: status = %d, want 200", rec.Code)
+	}
+
+	update := dto.UpdateProductRequest{Name: "Lamp", Price: 24.99, Category: "home"}
+	if _, err := h.productService.UpdateProduct(context.Background(), product.ID, update); err != nil {
+		t.Fatal(err)
+	}
+	if rec := get(etag); rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("after update: status = %d, ETag %q, want 200 with a new tag", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestGetProductInvalidTimezone(t *testing.T) {
+	h := newTestHandler(t)
+	createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+
+	for _, target := range []string{"/products?id=1&tz=Mars/Olympus", "/products?tz=Mars/Olympus"} {
+		rec := serve(h.GetProduct, http.MethodGet, target, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s: status = %d, want 400", target, rec.Code)
+		}
+	}
+}
+
+func TestGetProductByBarcode(t *testing.T) {
+	h := newTestHandler(t)
+	product := createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home", Barcode: "036000291452"})
+
+	rec := serve(h.GetProduct, http.MethodGet, "/products?barcode=036000291452", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var got dto.ProductResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != product.ID || got.Barcode != "036000291452" {
+		t.Errorf("got product %d with barcode %q, want %d with 036000291452", got.ID, got.Barcode, product.ID)
+	}
+
+	rec = serve(h.GetProduct, http.MethodGet, "/products?barcode=4006381333931", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown barcode: status = %d, want 404", rec.Code)
+	}
+
+	for _, barcode := range []string{"036000291453", "12345", "03600029145a"} {
+		rec = serve(h.GetProduct, http.MethodGet, "/products?barcode="+barcode, nil)
+		if rec.Code != http.StatusBadRequest || errorCode(t, rec) != "validation" {
+			t.Errorf("barcode %s: status = %d, want 400", barcode, rec.Code)
+		}
+	}
+}
+
+func TestCreateProductBarcode(t *testing.T) {
+	h := newTestHandler(t)
+	req := dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home", Barcode: "4006381333931"}
+
+	if rec := serve(h.CreateProduct, http.MethodPost, "/products", req); rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+
+	if rec := serve(h.CreateProduct, http.MethodPost, "/products", req); rec.Code != http.StatusConflict {
+		t.Errorf("duplicate barcode: status = %d, want 409", rec.Code)
+	}
+
+	req.Barcode = "4006381333932"
+	if rec := serve(h.CreateProduct, http.MethodPost, "/products", req); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad check digit: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestCreateProductPhysicalAttributes(t *testing.T) {
+	h := newTestHandler(t)
+
+	valid := dto.CreateProductRequest{
+		Name: "Lamp", Price: 19.99, Category: "home",
+		WeightGrams:  1200,
+		DimensionsCM: dto.Dimensions{Length: 30, Width: 20, Height: 45.5},
+	}
+	rec := serve(h.CreateProduct, http.MethodPost, "/products", valid)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	var got dto.ProductResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.WeightGrams != valid.WeightGrams || got.DimensionsCM != valid.DimensionsCM {
+		t.Errorf("got weight %d and dimensions %+v, want %d and %+v",
+			got.WeightGrams, got.DimensionsCM, valid.WeightGrams, valid.DimensionsCM)
+	}
+
+	invalid := map[string]func(*dto.CreateProductRequest){
+		"negative weight": func(r *dto.CreateProductRequest) { r.WeightGrams = -1 },
+		"negative length": func(r *dto.CreateProductRequest) { r.DimensionsCM.Length = -0.5 },
+		"negative width":  func(r *dto.CreateProductRequest) { r.DimensionsCM.Width = -2 },
+		"negative height": func(r *dto.CreateProductRequest) { r.DimensionsCM.Height = -10 },
+	}
+	for name, mutate := range invalid {
+		req := valid
+		mutate(&req)
+		rec := serve(h.CreateProduct, http.MethodPost, "/products", req)
+		if rec.Code != http.StatusBadRequest || errorCode(t, rec) != "validation" {
+			t.Errorf("%s: status = %d, want 400 validation: %s", name, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestCreateProducts(t *testing.T) {
+	h := newTestHandler(t)
+	lamp := dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"}
+	desk := dto.CreateProductRequest{Name: "Desk", Price: 149, Category: "home"}
+
+	rec := serve(h.CreateProducts, http.MethodPost, "/products/bulk", []dto.CreateProductRequest{lamp, desk})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	var created []dto.ProductResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 2 || created[0].ID == 0 || created[1].Name != "Desk" {
+		t.Errorf("created = %+v, want both products with IDs", created)
+	}
+
+	invalid := desk
+	invalid.Price = -1
+	rec = serve(h.CreateProducts, http.MethodPost, "/products/bulk", []dto.CreateProductRequest{lamp, invalid})
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "item 1:") {
+		t.Errorf("invalid second item: status = %d, body %s, want 400 naming item 1", rec.Code, rec.Body)
+	}
+	if rec := serve(h.CreateProducts, http.MethodPost, "/products/bulk", []dto.CreateProductRequest{}); rec.Code != http.StatusBadRequest {
+		t.Errorf("empty batch: status = %d, want 400", rec.Code)
+	}
+
+	list, err := h.productService.GetAllProducts(context.Background(), dto.Pagination{Limit: 10}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 2 {
+		t.Errorf("%d products stored, want only the first batch", list.Total)
+	}
+}
+
+// adminRequest builds a request carrying the admin token
+func adminRequest(method, target, token string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	req.Header.Set("X-Admin-Token", token)
+	return req
+}
+
+func TestDeleteProductHard(t *testing.T) {
+	h := newConfiguredTestHandler(t, config.Config{}, "secret")
+	product := createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+
+	rec := serve(h.DeleteProduct, http.MethodDelete, "/products?id=1&hard=true", nil)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("hard delete without admin token: status = %d, want 403", rec.Code)
+	}
+	if code := errorCode(t, rec); code != "forbidden" {
+		t.Errorf("error code = %q, want forbidden", code)
+	}
+	if _, err := h.productService.GetProduct(context.Background(), product.ID, false); err != nil {
+		t.Fatalf("product gone after a refused hard delete: %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	h.DeleteProduct(rec, adminRequest(http.MethodDelete, "/products?id=1&hard=true", "secret"))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("hard delete: status = %d, want 204: %s", rec.Code, rec.Body)
+	}
+	// A second hard delete looks the row up unscoped, so it only 404s once
+	// the row is really gone
+	rec = httptest.NewRecorder()
+	h.DeleteProduct(rec, adminRequest(http.MethodDelete, "/products?id=1&hard=true", "secret"))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("hard delete of a hard-deleted product: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestDeleteProductDefaultIsSoft(t *testing.T) {
+	h := newConfiguredTestHandler(t, config.Config{}, "secret")
+	product := createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+
+	if rec := serve(h.DeleteProduct, http.MethodDelete, "/products?id=1", nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204: %s", rec.Code, rec.Body)
+	}
+	if _, err := h.productService.GetProduct(context.Background(), product.ID, false); err == nil {
+		t.Error("soft-deleted product is still listed")
+	}
+	rec := httptest.NewRecorder()
+	h.DeleteProduct(rec, adminRequest(http.MethodDelete, "/products?id=1&hard=true", "secret"))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("soft-deleted row is gone: hard delete status = %d, want 204", rec.Code)
+	}
+}
+
+// usageMux routes the usage endpoint the way main does
+func usageMux(h *ProductHandler) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /products/{id}/usage", h.GetProductUsage)
+	return mux
+}
+
+func TestGetProductUsage(t *testing.T) {
+	orders := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orders/search" || r.URL.Query().Get("product_id") != "1" {
+			t.Errorf("order service called with %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[{"id":7,"user_id":3,"product_id":1,"quantity":2},{"id":5,"user_id":4,"product_id":1,"quantity":1}],"total":12}`))
+	}))
+	defer orders.Close()
+
+	h := newConfiguredTestHandler(t, config.Config{OrderServiceURL: orders.URL}, "secret")
+	createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+	createProduct(t, h, dto.CreateProductRequest{Name: "Rug", Price: 49, Category: "home"})
+	createProduct(t, h, dto.CreateProductRequest{Name: "Radio", Price: 20, Category: "electronics"})
+
+	rec := httptest.NewRecorder()
+	usageMux(h).ServeHTTP(rec, adminRequest(http.MethodGet, "/products/1/usage", "secret"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var got dto.ProductUsageResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ProductID != 1 || got.Warning != "" {
+		t.Errorf("product_id = %d, warning = %q, want 1 and no warning", got.ProductID, got.Warning)
+	}
+	if got.Orders == nil || got.Orders.Total != 12 || len(got.Orders.Recent) != 2 || got.Orders.Recent[0].ID != 7 {
+		t.Errorf("orders = %+v, want 12 in total with orders 7 and 5", got.Orders)
+	}
+	if got.CategorySiblings.Total != 1 || got.CategorySiblings.Items[0].Name != "Rug" {
+		t.Errorf("category siblings = %+v, want only Rug", got.CategorySiblings)
+	}
+}
+
+func TestGetProductUsageOrderServiceDown(t *testing.T) {
+	orders := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	orders.Close()
+
+	h := newConfiguredTestHandler(t, config.Config{OrderServiceURL: orders.URL}, "secret")
+	createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+	createProduct(t, h, dto.CreateProductRequest{Name: "Rug", Price: 49, Category: "home"})
+
+	rec := httptest.NewRecorder()
+	usageMux(h).ServeHTTP(rec, adminRequest(http.MethodGet, "/products/1/usage", "secret"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var got struct {
+		Orders           json.RawMessage         `json:"orders"`
+		CategorySiblings dto.ProductListResponse `json:"category_siblings"`
+		Warning          string                  `json:"warning"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if string(got.Orders) != "null" || got.Warning == "" {
+		t.Errorf("orders = %s, warning = %q, want null orders with a warning", got.Orders, got.Warning)
+	}
+	if got.CategorySiblings.Total != 1 {
+		t.Errorf("category siblings total = %d, want 1", got.CategorySiblings.Total)
+	}
+
+	rec = httptest.NewRecorder()
+	usageMux(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/products/1/usage", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("without admin token: status = %d, want 403", rec.Code)
+	}
+}
+
+func TestProductRoutesByPath(t *testing.T) {
+	h := newTestHandler(t)
+	createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /products", h.GetProduct)
+	mux.HandleFunc("GET /products/{id}", h.GetProduct)
+	mux.HandleFunc("PUT /products/{id}", h.UpdateProduct)
+	mux.HandleFunc("PATCH /products/{id}", h.PatchProduct)
+	mux.HandleFunc("DELETE /products/{id}", h.DeleteProduct)
+	mux.HandleFunc("GET /products/batch", h.GetProductsBatch)
+
+	name := "Desk lamp"
+	tests := []struct {
+		method, target string
+		body           interface{}
+		want           int
+	}{
+		{http.MethodGet, "/products/1", nil, http.StatusOK},
+		{http.MethodGet, "/products?id=1", nil, http.StatusOK},
+		{http.MethodGet, "/products/abc", nil, http.StatusBadRequest},
+		{http.MethodGet, "/products/batch?ids=1", nil, http.StatusOK},
+		{http.MethodPut, "/products/1", dto.UpdateProductRequest{Name: "Lamp", Price: 24.99, Category: "home"}, http.StatusOK},
+		{http.MethodPatch, "/products/1", dto.PatchProductRequest{Name: &name}, http.StatusOK},
+		{http.MethodDelete, "/products/1", nil, http.StatusNoContent},
+		{http.MethodGet, "/products/1", nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if rec := serve(mux.ServeHTTP, tt.method, tt.target, tt.body); rec.Code != tt.want {
+			t.Errorf("%s %s: status = %d, want %d: %s", tt.method, tt.target, rec.Code, tt.want, rec.Body)
+		}
+	}
+}
+
+func TestProductCurrency(t *testing.T) {
+	h := newTestHandler(t)
+	currency := func(rec *httptest.ResponseRecorder) string {
+		t.Helper()
+		var product dto.ProductResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &product); err != nil {
+			t.Fatalf("decode %q: %v", rec.Body, err)
+		}
+		return product.Currency
+	}
+
+	rec := serve(h.CreateProduct, http.MethodPost, "/products", dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+	if rec.Code != http.StatusCreated || currency(rec) != "USD" {
+		t.Errorf("create without a currency: status = %d, body %s, want 201 in USD", rec.Code, rec.Body)
+	}
+	rec = serve(h.CreateProduct, http.MethodPost, "/products", dto.CreateProductRequest{Name: "Desk", Price: 149, Category: "home", Currency: "eur"})
+	if rec.Code != http.StatusCreated || currency(rec) != "EUR" {
+		t.Errorf("create in eur: status = %d, body %s, want 201 in EUR", rec.Code, rec.Body)
+	}
+	rec = serve(h.CreateProduct, http.MethodPost, "/products", dto.CreateProductRequest{Name: "Desk", Price: 149, Category: "home", Currency: "XYZ"})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("create in XYZ: status = %d, want 400", rec.Code)
+	}
+
+	rec = serve(h.UpdateProduct, http.MethodPut, "/products?id=2", dto.UpdateProductRequest{Name: "Desk", Price: 159, Category: "home"})
+	if rec.Code != http.StatusOK || currency(rec) != "EUR" {
+		t.Errorf("PUT without a currency: status = %d, body %s, want the currency kept", rec.Code, rec.Body)
+	}
+	gbp := "gbp"
+	rec = serve(h.PatchProduct, http.MethodPatch, "/products?id=2", dto.PatchProductRequest{Currency: &gbp})
+	if rec.Code != http.StatusOK || currency(rec) != "GBP" {
+		t.Errorf("PATCH to gbp: status = %d, body %s, want GBP", rec.Code, rec.Body)
+	}
+}
+
+func TestUpdateProductKeepsStock(t *testing.T) {
+	h := newTestHandler(t)
+	product := createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home", Stock: 10})
+	if _, err := h.productService.AdjustStock(context.Background(), product.ID, -3); err != nil {
+		t.Fatal(err)
+	}
+
+	put := dto.UpdateProductRequest{Name: "Lamp", Price: 24.99, Category: "home"}
+	if rec := serve(h.UpdateProduct, http.MethodPut, "/products?id=1", put); rec.Code != http.StatusOK {
+		t.Fatalf("PUT: status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	got, err := h.productService.GetProduct(context.Background(), product.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Stock != 7 {
+		t.Errorf("stock after PUT = %d, want the adjusted 7", got.Stock)
+	}
+
+	for _, tt := range []struct {
+		handler http.HandlerFunc
+		method  string
+		body    json.RawMessage
+	}{
+		{h.UpdateProduct, http.MethodPut, json.RawMessage(`{"name": "Lamp", "category": "home", "price": 24.99, "stock": 50}`)},
+		{h.PatchProduct, http.MethodPatch, json.RawMessage(`{"stock": 50}`)},
+	} {
+		if rec := serve(tt.handler, tt.method, "/products?id=1", tt.body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s with stock: status = %d, want 400", tt.method, rec.Code)
+		}
+	}
+}
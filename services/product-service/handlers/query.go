@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"product-service/apperror"
+	"product-service/dto"
+	"strconv"
+	"strings"
+)
+
+// parseBool interprets a boolean query value, accepting the common truthy
+// (true, 1, yes, on) and falsy (false, 0, no, off) spellings in any case.
+// An empty value yields def; anything else is an error.
+func parseBool(value string, def bool) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return def, nil
+	case "true", "1", "yes", "on":
+		return true, nil
+	case "false", "0", "no", "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid boolean %q", value)
+	}
+}
+
+const (
+	// defaultPageLimit is the page size used when ?limit= is omitted
+	defaultPageLimit = 20
+	// maxPageLimit is the largest page size a caller may request
+	maxPageLimit = 100
+)
+
+// parsePagination reads the optional ?limit= and ?offset= query parameters.
+// A limit above maxPageLimit is rejected rather than clamped so callers learn
+// the real cap.
+func parsePagination(r *http.Request) (dto.Pagination, error) {
+	page := dto.Pagination{Limit: defaultPageLimit}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return page, apperror.Validation("limit must be a positive integer")
+		}
+		if limit > maxPageLimit {
+			return page, apperror.Validation("limit must not exceed %d", maxPageLimit)
+		}
+		page.Limit = limit
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return page, apperror.Validation("offset must be a non-negative integer")
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
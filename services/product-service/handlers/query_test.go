@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"product-service/config"
+	"product-service/dto"
+	"testing"
+)
+
+func TestParseBool(t *testing.T) {
+	tests := []struct {
+		value string
+		def   bool
+		want  bool
+	}{
+		{"", false, false},
+		{"", true, true},
+		{"true", false, true},
+		{"TRUE", false, true},
+		{"1", false, true},
+		{"yes", false, true},
+		{"On", false, true},
+		{" yes ", false, true},
+		{"false", true, false},
+		{"False", true, false},
+		{"0", true, false},
+		{"no", true, false},
+		{"OFF", true, false},
+	}
+	for _, tt := range tests {
+		got, err := parseBool(tt.value, tt.def)
+		if err != nil {
+			t.Errorf("parseBool(%q, %v) error: %v", tt.value, tt.def, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseBool(%q, %v) = %v, want %v", tt.value, tt.def, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"maybe", "2", "y", "truee"} {
+		if _, err := parseBool(value, false); err == nil {
+			t.Errorf("parseBool(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestDeleteProductHardSpellings(t *testing.T) {
+	h := newConfiguredTestHandler(t, config.Config{}, "secret")
+	createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+
+	tests := []struct {
+		flag string
+		want int
+	}{
+		{"maybe", http.StatusBadRequest},
+		{"no", http.StatusNoContent},  // soft delete
+		{"YES", http.StatusNoContent}, // hard delete of the soft-deleted row
+		{"1", http.StatusNotFound},    // the row is gone
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.DeleteProduct(rec, adminRequest(http.MethodDelete, "/products?id=1&hard="+tt.flag, "secret"))
+		if rec.Code != tt.want {
+			t.Errorf("hard=%s: status = %d, want %d", tt.flag, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestGetProductIncludeDeletedSpellings(t *testing.T) {
+	h := newConfiguredTestHandler(t, config.Config{}, "secret")
+	product := createProduct(t, h, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+	if err := h.productService.DeleteProduct(context.Background(), product.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		flag string
+		want int
+	}{
+		{"yes", http.StatusOK},
+		{"1", http.StatusOK},
+		{"ON", http.StatusOK},
+		{"no", http.StatusNotFound},
+		{"0", http.StatusNotFound},
+		{"maybe", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.GetProduct(rec, adminRequest(http.MethodGet, "/products?id=1&include_deleted="+tt.flag, "secret"))
+		if rec.Code != tt.want {
+			t.Errorf("include_deleted=%s: status = %d, want %d", tt.flag, rec.Code, tt.want)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.GetProduct(rec, httptest.NewRequest(http.MethodGet, "/products?id=1&include_deleted=true", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("include_deleted without the admin token: status = %d, want 403", rec.Code)
+	}
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"product-service/dto"
+	"time"
+)
+
+// parseTimezone resolves the optional ?tz= query parameter. A nil location
+// means timestamps are returned as stored (UTC).
+func parseTimezone(r *http.Request) (*time.Location, error) {
+	tz := r.URL.Query().Get("tz")
+	if tz == "" {
+		return nil, nil
+	}
+	return time.LoadLocation(tz)
+}
+
+// localizeProduct converts the product's timestamps to the given location
+func localizeProduct(product *dto.ProductResponse, loc *time.Location) {
+	if loc == nil || product == nil {
+		return
+	}
+	product.CreatedAt = product.CreatedAt.In(loc)
+	product.UpdatedAt = product.UpdatedAt.In(loc)
+	if product.DeletedAt != nil {
+		deleted := product.DeletedAt.In(loc)
+		product.DeletedAt = &deleted
+	}
+}
+
+// localizeProducts converts the timestamps of every product in the slice
+func localizeProducts(products []dto.ProductResponse, loc *time.Location) {
+	for i := range products {
+		localizeProduct(&products[i], loc)
+	}
+}
@@ -1,42 +1,165 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"product-service/config"
 	"product-service/database"
 	"product-service/handlers"
+	"product-service/logging"
+	"product-service/middleware"
+	"product-service/openapi"
+	pb "product-service/proto/proto"
 	"product-service/services"
+	_ "time/tzdata" // embed zone data so ?tz= works in minimal images
+
+	"google.golang.org/grpc"
 )
 
 func main() {
+	// Every setting comes from the environment; refuse to start on a
+	// malformed one rather than run with a surprising default
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// JSON logs at the configured level (debug, info, warn or error)
+	logging.Setup(cfg.LogLevel)
+
 	// Connect to database
-	database.ConnectDB()
+	database.ConnectDB(cfg.Database)
 	database.MigrateDB()
 
 	// Initialize services
-	productService := services.NewProductService(database.DB)
-	productHandler := handlers.NewProductHandler(productService)
+	productService := services.NewProductService(database.DB, cfg)
+	productService.StartCatalogSnapshot(context.Background())
+	productHandler := handlers.NewProductHandler(productService, cfg.AdminToken, cfg.RoundPrices)
+
+	// Cache policies; product data changes rarely so clients may cache briefly
+	productsCacheControl := cfg.CacheControlProducts
+	healthCacheControl := cfg.CacheControlHealth
+
+	// When enabled, creates must carry an Idempotency-Key header
+	requireIdempotencyKey := cfg.HTTP.RequireIdempotencyKey
+
+	// Endpoints can be switched off with FEATURE_FLAGS, e.g. "product_usage=false"
+	flags, err := middleware.ParseFlags(cfg.HTTP.FeatureFlags)
+	if err != nil {
+		slog.Error("invalid FEATURE_FLAGS", "error", err)
+		os.Exit(1)
+	}
+
+	// Routes wrapped in auth require a bearer JWT signed with JWT_SECRET;
+	// while it is unset they stay open
+	jwtSecret := []byte(cfg.HTTP.JWTSecret)
+	if len(jwtSecret) == 0 {
+		slog.Warn("JWT_SECRET is not set, authentication is disabled")
+	}
+	auth := func(next http.HandlerFunc) http.HandlerFunc { return middleware.RequireAuth(jwtSecret, next) }
+	createProduct := auth(productHandler.CreateProduct)
+	updateProduct := auth(productHandler.UpdateProduct)
+	patchProduct := auth(productHandler.PatchProduct)
+	deleteProduct := auth(productHandler.DeleteProduct)
 
 	// Set up routes
-	http.HandleFunc("/products", func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/products", middleware.CacheControl(productsCacheControl, middleware.RequireIdempotencyKey(requireIdempotencyKey, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
-			productHandler.CreateProduct(w, r)
+			createProduct(w, r)
 		case http.MethodGet:
 			productHandler.GetProduct(w, r)
 		case http.MethodPut:
-			productHandler.UpdateProduct(w, r)
+			updateProduct(w, r)
+		case http.MethodPatch:
+			patchProduct(w, r)
 		case http.MethodDelete:
-			productHandler.DeleteProduct(w, r)
+			deleteProduct(w, r)
 		default:
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
-	})
+	})))
+
+	// A single product can also be addressed by path, e.g. GET /products/42.
+	// The fixed routes below carry a method so the mux ranks them above {id}.
+	http.HandleFunc("GET /products/{id}", middleware.CacheControl(productsCacheControl, productHandler.GetProduct))
+	http.HandleFunc("PUT /products/{id}", updateProduct)
+	http.HandleFunc("PATCH /products/{id}", patchProduct)
+	http.HandleFunc("DELETE /products/{id}", deleteProduct)
+
+	http.HandleFunc("POST /products/bulk", auth(middleware.RequireIdempotencyKey(requireIdempotencyKey, productHandler.CreateProducts)))
+	http.HandleFunc("POST /products/restore", auth(productHandler.RestoreProduct))
+	http.HandleFunc("POST /products/bulk-category", auth(productHandler.BulkAssignCategory))
+	http.HandleFunc("GET /products/batch", middleware.CacheControl(productsCacheControl, productHandler.GetProductsBatch))
+	http.HandleFunc("POST /products/stock", middleware.RequireServiceOrAuth(cfg.HTTP.ServiceToken, jwtSecret, productHandler.AdjustStock))
+	http.HandleFunc("GET /products/{id}/usage", middleware.Feature(flags, "product_usage", productHandler.GetProductUsage))
+	http.HandleFunc("GET /products/featured", middleware.Feature(flags, "featured_products", middleware.CacheControl(productsCacheControl, productHandler.GetFeaturedProducts)))
+	http.HandleFunc("GET /products/price-stats", middleware.CacheControl(productsCacheControl, productHandler.GetPriceStats))
+	http.HandleFunc("/categories", middleware.CacheControl(productsCacheControl, productHandler.GetCategories))
 
-	// Health check endpoint
-	http.HandleFunc("/health", productHandler.Health)
+	// Machine-readable description of the routes registered here
+	apiDoc := openapi.Build(openapi.Info{Title: "Product Service", Version: "1.0.0"}, handlers.Operations())
+	http.HandleFunc("/openapi.json", openapi.Handler(apiDoc))
+
+	// Liveness and readiness probes
+	http.HandleFunc("/health", middleware.CacheControl(healthCacheControl, productHandler.Health))
+	http.HandleFunc("/health/ready", middleware.CacheControl(healthCacheControl, productHandler.Ready))
+
+	// Header size cap enforced by net/http (431 when exceeded), and how many
+	// times a single header or query parameter may repeat
+	maxHeaderBytes := cfg.HTTP.MaxHeaderBytes
+	maxRepeated := cfg.HTTP.MaxRepeatedValues
+
+	// Request bodies larger than this are refused with 413
+	maxBodyBytes := cfg.HTTP.MaxBodyBytes
+
+	// Browser origins allowed to call the service, comma-separated or "*"
+	allowedOrigins := cfg.HTTP.AllowedOrigins
+
+	// Per-client rate limit; RATE_LIMIT_RPS unset disables it
+	var limiter *middleware.RateLimiter
+	if cfg.HTTP.RateLimitRPS > 0 {
+		limiter = middleware.NewRateLimiter(cfg.HTTP.RateLimitRPS, cfg.HTTP.RateLimitBurst, cfg.HTTP.RateLimitTrustProxy)
+	}
+
+	// "rewrite" (default) or "redirect" for paths with a trailing slash
+	trailingSlashMode := cfg.HTTP.TrailingSlashMode
+	handler := middleware.Chain(http.DefaultServeMux.ServeHTTP,
+		middleware.RequestID,
+		middleware.LogRequests,
+		middleware.Recover,
+		func(next http.HandlerFunc) http.HandlerFunc { return middleware.CORS(allowedOrigins, next) },
+		limiter.Limit,
+		func(next http.HandlerFunc) http.HandlerFunc { return middleware.LimitRepeated(maxRepeated, next) },
+		func(next http.HandlerFunc) http.HandlerFunc { return middleware.LimitBody(maxBodyBytes, next) },
+		func(next http.HandlerFunc) http.HandlerFunc { return middleware.TrailingSlash(trailingSlashMode, next) },
+	)
+
+	// The gRPC API runs on its own port and shares productService with REST
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
+	if err != nil {
+		slog.Error("gRPC listen failed", "error", err)
+		os.Exit(1)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterProductServiceServer(grpcServer, handlers.NewProductGRPCServer(productService, jwtSecret, cfg.RoundPrices))
+	go func() {
+		slog.Info("Product Service gRPC starting", "port", cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			slog.Error("gRPC server stopped", "error", err)
+			os.Exit(1)
+		}
+	}()
 
-	fmt.Println("Product Service starting on port 8081...")
-	log.Fatal(http.ListenAndServe(":8081", nil))
+	slog.Info("Product Service starting", "port", cfg.Port)
+	server := &http.Server{Addr: fmt.Sprintf(":%d", cfg.Port), Handler: handler, MaxHeaderBytes: maxHeaderBytes}
+	if err := server.ListenAndServe(); err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,15 @@
+package middleware
+
+import "net/http"
+
+// LimitBody caps request bodies at max bytes. Reading past the cap fails
+// with *http.MaxBytesError, which handlers report as 413. A max of 0 or less
+// disables the limit.
+func LimitBody(max int64, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if max > 0 && r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, max)
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,14 @@
+package middleware
+
+import "net/http"
+
+// CacheControl sets the given Cache-Control header on GET and HEAD responses.
+// An empty value leaves the response untouched.
+func CacheControl(value string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if value != "" && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+			w.Header().Set("Cache-Control", value)
+		}
+		next(w, r)
+	}
+}
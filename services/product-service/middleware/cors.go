@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	corsAllowMethods  = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsAllowHeaders  = "Authorization, Content-Type, Idempotency-Key, If-None-Match, X-Admin-Token, X-Request-ID"
+	corsExposeHeaders = "ETag, X-Request-ID, Retry-After"
+)
+
+// CORS lets browsers on the allowed origins call the service. origins is a
+// comma-separated allowlist as in ALLOWED_ORIGINS; "*" allows any origin and
+// an empty list emits no CORS headers at all. Preflight requests are answered
+// here with 204 and never reach next.
+func CORS(origins string, next http.HandlerFunc) http.HandlerFunc {
+	allowed := make(map[string]bool)
+	for _, origin := range strings.Split(origins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			allowed[strings.TrimRight(origin, "/")] = true
+		}
+	}
+	if len(allowed) == 0 {
+		return next
+	}
+	anyOrigin := allowed["*"]
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || (!anyOrigin && !allowed[origin]) {
+			next(w, r)
+			return
+		}
+
+		h := w.Header()
+		if anyOrigin {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Add("Vary", "Origin")
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", corsAllowMethods)
+			h.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			h.Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		h.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+		next(w, r)
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Flags maps feature names to whether they are enabled
+type Flags map[string]bool
+
+// ParseFlags parses a comma-separated list of name=bool pairs, such as
+// "order_search=false,product_usage=true"
+func ParseFlags(value string) (Flags, error) {
+	flags := make(Flags)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("feature flag %q must be name=bool", pair)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("feature flag %q must be name=bool", pair)
+		}
+		flags[strings.TrimSpace(name)] = enabled
+	}
+	return flags, nil
+}
+
+// Enabled reports whether a feature is on. Features that aren't listed are on,
+// so flags only need to name what is switched off.
+func (f Flags) Enabled(name string) bool {
+	enabled, ok := f[name]
+	return !ok || enabled
+}
+
+// Feature serves next only while the named feature is enabled, answering 404
+// otherwise so a disabled endpoint looks like it doesn't exist
+func Feature(flags Flags, name string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !flags.Enabled(name) {
+			http.NotFound(w, r)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimiterIdleTTL is how long a client's bucket is kept after its last
+// request; an idle bucket would be full again by then anyway
+const rateLimiterIdleTTL = 10 * time.Minute
+
+// RateLimiter throttles requests with a token bucket per client IP
+type RateLimiter struct {
+	limit          rate.Limit
+	burst          int
+	trustForwarded bool
+
+	mu        sync.Mutex
+	clients   map[string]*clientBucket
+	lastSweep time.Time
+}
+
+type clientBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter allows each client rps requests per second with bursts of
+// up to burst. When trustForwarded is set the client is identified by the
+// first X-Forwarded-For address, which is only safe behind a proxy that sets
+// that header; otherwise by the connection's remote address.
+func NewRateLimiter(rps float64, burst int, trustForwarded bool) *RateLimiter {
+	return &RateLimiter{
+		limit:          rate.Limit(rps),
+		burst:          burst,
+		trustForwarded: trustForwarded,
+		clients:        make(map[string]*clientBucket),
+		lastSweep:      time.Now(),
+	}
+}
+
+// Limit answers 429 with a Retry-After header once a client exceeds its rate.
+// A nil RateLimiter lets every request through.
+func (l *RateLimiter) Limit(next http.HandlerFunc) http.HandlerFunc {
+	if l == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		reservation := l.bucket(l.clientIP(r)).Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			reservation.Cancel()
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// bucket returns the client's token bucket, creating it on first use and
+// dropping buckets that have been idle for rateLimiterIdleTTL
+func (l *RateLimiter) bucket(client string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > rateLimiterIdleTTL {
+		for key, b := range l.clients {
+			if now.Sub(b.lastSeen) > rateLimiterIdleTTL {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.clients[client]
+	if !ok {
+		b = &clientBucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[client] = b
+	}
+	b.lastSeen = now
+	return b.limiter
+}
+
+// clientIP identifies the client a request is counted against
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	if l.trustForwarded {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			if ip := strings.TrimSpace(first); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
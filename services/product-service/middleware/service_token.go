@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// ServiceTokenHeader carries the shared secret one service presents when it
+// calls an internal endpoint of another
+const ServiceTokenHeader = "X-Service-Token"
+
+// RequireServiceOrAuth admits requests whose X-Service-Token matches token,
+// compared in constant time, and otherwise requires a bearer JWT as
+// RequireAuth does. With an empty token only the JWT is accepted.
+func RequireServiceOrAuth(token string, secret []byte, next http.HandlerFunc) http.HandlerFunc {
+	authenticated := RequireAuth(secret, next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		provided := r.Header.Get(ServiceTokenHeader)
+		if token != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+			next(w, r)
+			return
+		}
+		authenticated(w, r)
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// TrailingSlash normalizes request paths ending in a slash so that /products/
+// and /products reach the same handler. With mode "redirect" clients receive a
+// permanent redirect to the canonical path (query string preserved); any other
+// mode rewrites the path in place.
+func TrailingSlash(mode string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if len(path) > 1 && strings.HasSuffix(path, "/") {
+			trimmed := strings.TrimRight(path, "/")
+			if trimmed == "" {
+				trimmed = "/"
+			}
+
+			if mode == "redirect" {
+				target := *r.URL
+				target.Path = trimmed
+				target.RawPath = ""
+				http.Redirect(w, r, target.String(), http.StatusPermanentRedirect)
+				return
+			}
+
+			r = r.Clone(r.Context())
+			r.URL.Path = trimmed
+			r.URL.RawPath = ""
+		}
+		next(w, r)
+	}
+}
@@ -8,12 +8,37 @@ import (
 
 // Product represents a product in our system
 type Product struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	Name        string         `json:"name" gorm:"not null"`
-	Description string         `json:"description"`
-	Price       float64        `json:"price" gorm:"not null"`
-	Category    string         `json:"category" gorm:"not null"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	Name           string         `json:"name" gorm:"not null"`
+	Description    string         `json:"description"`
+	Price          float64        `json:"price" gorm:"not null"`
+	Currency       string         `json:"currency" gorm:"size:3;not null;default:USD"`
+	Category       string         `json:"category" gorm:"not null"`
+	Barcode        *string        `json:"barcode" gorm:"uniqueIndex"`
+	FeaturedWeight float64        `json:"featured_weight" gorm:"not null;default:0"`
+	WeightGrams    int            `json:"weight_grams"`
+	DimensionsCM   Dimensions     `json:"dimensions_cm" gorm:"embedded;embeddedPrefix:dimensions_cm_"`
+	Stock          int            `json:"stock" gorm:"not null;default:0;check:stock >= 0"`
+	AvailableFrom  *time.Time     `json:"available_from"`
+	AvailableUntil *time.Time     `json:"available_until"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Dimensions represents the physical size of a product in centimeters
+type Dimensions struct {
+	Length float64 `json:"length"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Replenishment records that a product's stock fell to its reorder point and
+// how many units should be ordered from the supplier
+type Replenishment struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	ProductID      uint      `json:"product_id" gorm:"not null;index"`
+	Quantity       int       `json:"quantity" gorm:"not null"`
+	StockAtTrigger int       `json:"stock_at_trigger" gorm:"not null"`
+	CreatedAt      time.Time `json:"created_at"`
 }
@@ -0,0 +1,201 @@
+// Package openapi builds an OpenAPI 3.0 document from a table of operations.
+// Request and response schemas are derived from the DTO structs themselves,
+// so the document changes whenever a DTO does.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Operation describes one method on one path
+type Operation struct {
+	Method  string
+	Path    string
+	Summary string
+	Params  []Param
+	// Body is a value of the request DTO type, or nil when the operation
+	// takes no body
+	Body      interface{}
+	Responses []Response
+	// Auth marks operations that require a bearer JWT when JWT_SECRET is set
+	Auth bool
+}
+
+// Param is a query or path parameter
+type Param struct {
+	Name        string
+	In          string // "query" or "path"
+	Type        string // "string", "integer", "number" or "boolean"
+	Required    bool
+	Description string
+}
+
+// Query describes an optional query parameter
+func Query(name, typ, description string) Param {
+	return Param{Name: name, In: "query", Type: typ, Description: description}
+}
+
+// Response is one possible response of an operation
+type Response struct {
+	Status      int
+	Description string
+	// Body is a value of the response DTO type, OneOf for alternatives, or
+	// nil when the response has no JSON body
+	Body interface{}
+}
+
+// oneOf lists alternative response bodies
+type oneOf []interface{}
+
+// OneOf describes a body that is one of several DTO types, for endpoints
+// whose response shape depends on the query
+func OneOf(bodies ...interface{}) interface{} {
+	return oneOf(bodies)
+}
+
+// Document is an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components components                       `json:"components"`
+}
+
+// Info identifies the API
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes"`
+}
+
+type securityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat"`
+}
+
+type operation struct {
+	Summary     string                `json:"summary,omitempty"`
+	Parameters  []parameter           `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Build assembles the document for ops, collecting every DTO they reference
+// under components/schemas
+func Build(info Info, ops []Operation) *Document {
+	g := newGenerator()
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]map[string]*operation),
+	}
+
+	for _, op := range ops {
+		o := &operation{Summary: op.Summary, Responses: make(map[string]response)}
+		for _, p := range op.Params {
+			in := p.In
+			if in == "" {
+				in = "query"
+			}
+			o.Parameters = append(o.Parameters, parameter{
+				Name:        p.Name,
+				In:          in,
+				Required:    p.Required || in == "path",
+				Description: p.Description,
+				Schema:      &Schema{Type: p.Type},
+			})
+		}
+		if op.Body != nil {
+			o.RequestBody = &requestBody{
+				Required: true,
+				Content:  map[string]mediaType{"application/json": {Schema: g.bodySchema(op.Body)}},
+			}
+		}
+		for _, r := range op.Responses {
+			resp := response{Description: r.Description}
+			if resp.Description == "" {
+				resp.Description = http.StatusText(r.Status)
+			}
+			if r.Body != nil {
+				resp.Content = map[string]mediaType{"application/json": {Schema: g.bodySchema(r.Body)}}
+			}
+			o.Responses[strconv.Itoa(r.Status)] = resp
+		}
+		if op.Auth {
+			o.Security = []map[string][]string{{"bearerAuth": {}}}
+		}
+
+		if doc.Paths[op.Path] == nil {
+			doc.Paths[op.Path] = make(map[string]*operation)
+		}
+		doc.Paths[op.Path][strings.ToLower(op.Method)] = o
+	}
+
+	doc.Components = components{
+		Schemas: g.schemas,
+		SecuritySchemes: map[string]securityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		},
+	}
+	return doc
+}
+
+// bodySchema returns the schema of a request or response body
+func (g *generator) bodySchema(body interface{}) *Schema {
+	if alternatives, ok := body.(oneOf); ok {
+		s := &Schema{}
+		for _, alt := range alternatives {
+			s.OneOf = append(s.OneOf, g.bodySchema(alt))
+		}
+		return s
+	}
+	return g.schemaOf(body)
+}
+
+// Handler serves doc as JSON. The document is encoded once up front.
+func Handler(doc *Document) http.HandlerFunc {
+	data, err := json.Marshal(doc)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			http.Error(w, "OpenAPI document unavailable", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}
+}
@@ -22,30 +22,27 @@ const (
 )
 
 // Request/Response messages
-type CreateProductRequest struct {
+type GetProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
-	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateProductRequest) Reset() {
-	*x = CreateProductRequest{}
+func (x *GetProductRequest) Reset() {
+	*x = GetProductRequest{}
 	mi := &file_proto_product_proto_msgTypes[0]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateProductRequest) String() string {
+func (x *GetProductRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateProductRequest) ProtoMessage() {}
+func (*GetProductRequest) ProtoMessage() {}
 
-func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
+func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_product_proto_msgTypes[0]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -57,60 +54,40 @@ func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
-func (*CreateProductRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
+func (*GetProductRequest) Descriptor() ([]byte, []int) {
 	return file_proto_product_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *CreateProductRequest) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *CreateProductRequest) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
-}
-
-func (x *CreateProductRequest) GetPrice() float64 {
+func (x *GetProductRequest) GetId() uint32 {
 	if x != nil {
-		return x.Price
+		return x.Id
 	}
 	return 0
 }
 
-func (x *CreateProductRequest) GetCategory() string {
-	if x != nil {
-		return x.Category
-	}
-	return ""
-}
-
-type GetProductRequest struct {
+// Products that don't exist are absent from the response
+type GetProductsByIDsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Ids           []uint32               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProductRequest) Reset() {
-	*x = GetProductRequest{}
+func (x *GetProductsByIDsRequest) Reset() {
+	*x = GetProductsByIDsRequest{}
 	mi := &file_proto_product_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProductRequest) String() string {
+func (x *GetProductsByIDsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProductRequest) ProtoMessage() {}
+func (*GetProductsByIDsRequest) ProtoMessage() {}
 
-func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
+func (x *GetProductsByIDsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_product_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -122,38 +99,39 @@ func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
-func (*GetProductRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetProductsByIDsRequest.ProtoReflect.Descriptor instead.
+func (*GetProductsByIDsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_product_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *GetProductRequest) GetId() uint32 {
+func (x *GetProductsByIDsRequest) GetIds() []uint32 {
 	if x != nil {
-		return x.Id
+		return x.Ids
 	}
-	return 0
+	return nil
 }
 
-type GetAllProductsRequest struct {
+type GetProductsByIDsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*ProductResponse     `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAllProductsRequest) Reset() {
-	*x = GetAllProductsRequest{}
+func (x *GetProductsByIDsResponse) Reset() {
+	*x = GetProductsByIDsResponse{}
 	mi := &file_proto_product_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAllProductsRequest) String() string {
+func (x *GetProductsByIDsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAllProductsRequest) ProtoMessage() {}
+func (*GetProductsByIDsResponse) ProtoMessage() {}
 
-func (x *GetAllProductsRequest) ProtoReflect() protoreflect.Message {
+func (x *GetProductsByIDsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_product_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -165,32 +143,52 @@ func (x *GetAllProductsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAllProductsRequest.ProtoReflect.Descriptor instead.
-func (*GetAllProductsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetProductsByIDsResponse.ProtoReflect.Descriptor instead.
+func (*GetProductsByIDsResponse) Descriptor() ([]byte, []int) {
 	return file_proto_product_proto_rawDescGZIP(), []int{2}
 }
 
-type GetProductsByCategoryRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Category      string                 `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+func (x *GetProductsByIDsResponse) GetProducts() []*ProductResponse {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type CreateProductRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description    string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Price          float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Category       string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Barcode        string                 `protobuf:"bytes,5,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	FeaturedWeight float64                `protobuf:"fixed64,6,opt,name=featured_weight,json=featuredWeight,proto3" json:"featured_weight,omitempty"`
+	WeightGrams    int32                  `protobuf:"varint,7,opt,name=weight_grams,json=weightGrams,proto3" json:"weight_grams,omitempty"`
+	DimensionsCm   *Dimensions            `protobuf:"bytes,8,opt,name=dimensions_cm,json=dimensionsCm,proto3" json:"dimensions_cm,omitempty"`
+	Stock          int32                  `protobuf:"varint,9,opt,name=stock,proto3" json:"stock,omitempty"`
+	// RFC 3339 timestamps; empty means unbounded on that side
+	AvailableFrom  string `protobuf:"bytes,10,opt,name=available_from,json=availableFrom,proto3" json:"available_from,omitempty"`
+	AvailableUntil string `protobuf:"bytes,11,opt,name=available_until,json=availableUntil,proto3" json:"available_until,omitempty"`
+	// ISO 4217 code; USD when empty
+	Currency      string `protobuf:"bytes,12,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetProductsByCategoryRequest) Reset() {
-	*x = GetProductsByCategoryRequest{}
+func (x *CreateProductRequest) Reset() {
+	*x = CreateProductRequest{}
 	mi := &file_proto_product_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetProductsByCategoryRequest) String() string {
+func (x *CreateProductRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetProductsByCategoryRequest) ProtoMessage() {}
+func (*CreateProductRequest) ProtoMessage() {}
 
-func (x *GetProductsByCategoryRequest) ProtoReflect() protoreflect.Message {
+func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_product_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -202,154 +200,121 @@ func (x *GetProductsByCategoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetProductsByCategoryRequest.ProtoReflect.Descriptor instead.
-func (*GetProductsByCategoryRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
+func (*CreateProductRequest) Descriptor() ([]byte, []int) {
 	return file_proto_product_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *GetProductsByCategoryRequest) GetCategory() string {
+func (x *CreateProductRequest) GetName() string {
 	if x != nil {
-		return x.Category
+		return x.Name
 	}
 	return ""
 }
 
-type UpdateProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	Category      string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *UpdateProductRequest) Reset() {
-	*x = UpdateProductRequest{}
-	mi := &file_proto_product_proto_msgTypes[4]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *UpdateProductRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*UpdateProductRequest) ProtoMessage() {}
-
-func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[4]
+func (x *CreateProductRequest) GetDescription() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Description
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
-func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{4}
+	return ""
 }
 
-func (x *UpdateProductRequest) GetId() uint32 {
+func (x *CreateProductRequest) GetPrice() float64 {
 	if x != nil {
-		return x.Id
+		return x.Price
 	}
 	return 0
 }
 
-func (x *UpdateProductRequest) GetName() string {
+func (x *CreateProductRequest) GetCategory() string {
 	if x != nil {
-		return x.Name
+		return x.Category
 	}
 	return ""
 }
 
-func (x *UpdateProductRequest) GetDescription() string {
+func (x *CreateProductRequest) GetBarcode() string {
 	if x != nil {
-		return x.Description
+		return x.Barcode
 	}
 	return ""
 }
 
-func (x *UpdateProductRequest) GetPrice() float64 {
+func (x *CreateProductRequest) GetFeaturedWeight() float64 {
 	if x != nil {
-		return x.Price
+		return x.FeaturedWeight
 	}
 	return 0
 }
 
-func (x *UpdateProductRequest) GetCategory() string {
+func (x *CreateProductRequest) GetWeightGrams() int32 {
 	if x != nil {
-		return x.Category
+		return x.WeightGrams
 	}
-	return ""
-}
-
-type DeleteProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	return 0
 }
 
-func (x *DeleteProductRequest) Reset() {
-	*x = DeleteProductRequest{}
-	mi := &file_proto_product_proto_msgTypes[5]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *CreateProductRequest) GetDimensionsCm() *Dimensions {
+	if x != nil {
+		return x.DimensionsCm
+	}
+	return nil
 }
 
-func (x *DeleteProductRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *CreateProductRequest) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
 }
 
-func (*DeleteProductRequest) ProtoMessage() {}
-
-func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[5]
+func (x *CreateProductRequest) GetAvailableFrom() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.AvailableFrom
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use DeleteProductRequest.ProtoReflect.Descriptor instead.
-func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{5}
+func (x *CreateProductRequest) GetAvailableUntil() string {
+	if x != nil {
+		return x.AvailableUntil
+	}
+	return ""
 }
 
-func (x *DeleteProductRequest) GetId() uint32 {
+func (x *CreateProductRequest) GetCurrency() string {
 	if x != nil {
-		return x.Id
+		return x.Currency
 	}
-	return 0
+	return ""
 }
 
 type ProductResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	Category      string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description    string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price          float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Category       string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Barcode        string                 `protobuf:"bytes,8,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	FeaturedWeight float64                `protobuf:"fixed64,9,opt,name=featured_weight,json=featuredWeight,proto3" json:"featured_weight,omitempty"`
+	WeightGrams    int32                  `protobuf:"varint,10,opt,name=weight_grams,json=weightGrams,proto3" json:"weight_grams,omitempty"`
+	DimensionsCm   *Dimensions            `protobuf:"bytes,11,opt,name=dimensions_cm,json=dimensionsCm,proto3" json:"dimensions_cm,omitempty"`
+	Stock          int32                  `protobuf:"varint,12,opt,name=stock,proto3" json:"stock,omitempty"`
+	// RFC 3339 timestamps; empty means unbounded on that side
+	AvailableFrom  string `protobuf:"bytes,13,opt,name=available_from,json=availableFrom,proto3" json:"available_from,omitempty"`
+	AvailableUntil string `protobuf:"bytes,14,opt,name=available_until,json=availableUntil,proto3" json:"available_until,omitempty"`
+	// ISO 4217 code of price
+	Currency      string `protobuf:"bytes,15,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ProductResponse) Reset() {
 	*x = ProductResponse{}
-	mi := &file_proto_product_proto_msgTypes[6]
+	mi := &file_proto_product_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -361,7 +326,7 @@ func (x *ProductResponse) String() string {
 func (*ProductResponse) ProtoMessage() {}
 
 func (x *ProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[6]
+	mi := &file_proto_product_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -374,7 +339,7 @@ func (x *ProductResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductResponse.ProtoReflect.Descriptor instead.
 func (*ProductResponse) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{6}
+	return file_proto_product_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ProductResponse) GetId() uint32 {
@@ -426,72 +391,86 @@ func (x *ProductResponse) GetUpdatedAt() string {
 	return ""
 }
 
-type GetAllProductsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*ProductResponse     `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ProductResponse) GetBarcode() string {
+	if x != nil {
+		return x.Barcode
+	}
+	return ""
 }
 
-func (x *GetAllProductsResponse) Reset() {
-	*x = GetAllProductsResponse{}
-	mi := &file_proto_product_proto_msgTypes[7]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ProductResponse) GetFeaturedWeight() float64 {
+	if x != nil {
+		return x.FeaturedWeight
+	}
+	return 0
 }
 
-func (x *GetAllProductsResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ProductResponse) GetWeightGrams() int32 {
+	if x != nil {
+		return x.WeightGrams
+	}
+	return 0
 }
 
-func (*GetAllProductsResponse) ProtoMessage() {}
+func (x *ProductResponse) GetDimensionsCm() *Dimensions {
+	if x != nil {
+		return x.DimensionsCm
+	}
+	return nil
+}
 
-func (x *GetAllProductsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[7]
+func (x *ProductResponse) GetStock() int32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Stock
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use GetAllProductsResponse.ProtoReflect.Descriptor instead.
-func (*GetAllProductsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{7}
+func (x *ProductResponse) GetAvailableFrom() string {
+	if x != nil {
+		return x.AvailableFrom
+	}
+	return ""
 }
 
-func (x *GetAllProductsResponse) GetProducts() []*ProductResponse {
+func (x *ProductResponse) GetAvailableUntil() string {
 	if x != nil {
-		return x.Products
+		return x.AvailableUntil
 	}
-	return nil
+	return ""
 }
 
-type DeleteProductResponse struct {
+func (x *ProductResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Dimensions struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Length        float64                `protobuf:"fixed64,1,opt,name=length,proto3" json:"length,omitempty"`
+	Width         float64                `protobuf:"fixed64,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height        float64                `protobuf:"fixed64,3,opt,name=height,proto3" json:"height,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteProductResponse) Reset() {
-	*x = DeleteProductResponse{}
-	mi := &file_proto_product_proto_msgTypes[8]
+func (x *Dimensions) Reset() {
+	*x = Dimensions{}
+	mi := &file_proto_product_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteProductResponse) String() string {
+func (x *Dimensions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteProductResponse) ProtoMessage() {}
+func (*Dimensions) ProtoMessage() {}
 
-func (x *DeleteProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[8]
+func (x *Dimensions) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -502,41 +481,57 @@ func (x *DeleteProductResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteProductResponse.ProtoReflect.Descriptor instead.
-func (*DeleteProductResponse) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use Dimensions.ProtoReflect.Descriptor instead.
+func (*Dimensions) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Dimensions) GetLength() float64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *Dimensions) GetWidth() float64 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
 }
 
-func (x *DeleteProductResponse) GetSuccess() bool {
+func (x *Dimensions) GetHeight() float64 {
 	if x != nil {
-		return x.Success
+		return x.Height
 	}
-	return false
+	return 0
 }
 
 var File_proto_product_proto protoreflect.FileDescriptor
 
 const file_proto_product_proto_rawDesc = "" +
 	"\n" +
-	"\x13proto/product.proto\x12\aproduct\"~\n" +
+	"\x13proto/product.proto\x12\aproduct\"#\n" +
+	"\x11GetProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\"+\n" +
+	"\x17GetProductsByIDsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\rR\x03ids\"P\n" +
+	"\x18GetProductsByIDsResponse\x124\n" +
+	"\bproducts\x18\x01 \x03(\v2\x18.product.ProductResponseR\bproducts\"\xa0\x03\n" +
 	"\x14CreateProductRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
 	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x1a\n" +
-	"\bcategory\x18\x04 \x01(\tR\bcategory\"#\n" +
-	"\x11GetProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\rR\x02id\"\x17\n" +
-	"\x15GetAllProductsRequest\":\n" +
-	"\x1cGetProductsByCategoryRequest\x12\x1a\n" +
-	"\bcategory\x18\x01 \x01(\tR\bcategory\"\x8e\x01\n" +
-	"\x14UpdateProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1a\n" +
-	"\bcategory\x18\x05 \x01(\tR\bcategory\"&\n" +
-	"\x14DeleteProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\rR\x02id\"\xc7\x01\n" +
+	"\bcategory\x18\x04 \x01(\tR\bcategory\x12\x18\n" +
+	"\abarcode\x18\x05 \x01(\tR\abarcode\x12'\n" +
+	"\x0ffeatured_weight\x18\x06 \x01(\x01R\x0efeaturedWeight\x12!\n" +
+	"\fweight_grams\x18\a \x01(\x05R\vweightGrams\x128\n" +
+	"\rdimensions_cm\x18\b \x01(\v2\x13.product.DimensionsR\fdimensionsCm\x12\x14\n" +
+	"\x05stock\x18\t \x01(\x05R\x05stock\x12%\n" +
+	"\x0eavailable_from\x18\n" +
+	" \x01(\tR\ravailableFrom\x12'\n" +
+	"\x0favailable_until\x18\v \x01(\tR\x0eavailableUntil\x12\x1a\n" +
+	"\bcurrency\x18\f \x01(\tR\bcurrency\"\xe9\x03\n" +
 	"\x0fProductResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
@@ -546,19 +541,26 @@ const file_proto_product_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\tR\tupdatedAt\"N\n" +
-	"\x16GetAllProductsResponse\x124\n" +
-	"\bproducts\x18\x01 \x03(\v2\x18.product.ProductResponseR\bproducts\"1\n" +
-	"\x15DeleteProductResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\xec\x03\n" +
-	"\x0eProductService\x12H\n" +
-	"\rCreateProduct\x12\x1d.product.CreateProductRequest\x1a\x18.product.ProductResponse\x12B\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\x12\x18\n" +
+	"\abarcode\x18\b \x01(\tR\abarcode\x12'\n" +
+	"\x0ffeatured_weight\x18\t \x01(\x01R\x0efeaturedWeight\x12!\n" +
+	"\fweight_grams\x18\n" +
+	" \x01(\x05R\vweightGrams\x128\n" +
+	"\rdimensions_cm\x18\v \x01(\v2\x13.product.DimensionsR\fdimensionsCm\x12\x14\n" +
+	"\x05stock\x18\f \x01(\x05R\x05stock\x12%\n" +
+	"\x0eavailable_from\x18\r \x01(\tR\ravailableFrom\x12'\n" +
+	"\x0favailable_until\x18\x0e \x01(\tR\x0eavailableUntil\x12\x1a\n" +
+	"\bcurrency\x18\x0f \x01(\tR\bcurrency\"R\n" +
+	"\n" +
+	"Dimensions\x12\x16\n" +
+	"\x06length\x18\x01 \x01(\x01R\x06length\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\x01R\x05width\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x01R\x06height2\xf7\x01\n" +
+	"\x0eProductService\x12B\n" +
 	"\n" +
-	"GetProduct\x12\x1a.product.GetProductRequest\x1a\x18.product.ProductResponse\x12Q\n" +
-	"\x0eGetAllProducts\x12\x1e.product.GetAllProductsRequest\x1a\x1f.product.GetAllProductsResponse\x12_\n" +
-	"\x15GetProductsByCategory\x12%.product.GetProductsByCategoryRequest\x1a\x1f.product.GetAllProductsResponse\x12H\n" +
-	"\rUpdateProduct\x12\x1d.product.UpdateProductRequest\x1a\x18.product.ProductResponse\x12N\n" +
-	"\rDeleteProduct\x12\x1d.product.DeleteProductRequest\x1a\x1e.product.DeleteProductResponseB\x17Z\x15product-service/protob\x06proto3"
+	"GetProduct\x12\x1a.product.GetProductRequest\x1a\x18.product.ProductResponse\x12W\n" +
+	"\x10GetProductsByIDs\x12 .product.GetProductsByIDsRequest\x1a!.product.GetProductsByIDsResponse\x12H\n" +
+	"\rCreateProduct\x12\x1d.product.CreateProductRequest\x1a\x18.product.ProductResponseB\x17Z\x15product-service/protob\x06proto3"
 
 var (
 	file_proto_product_proto_rawDescOnce sync.Once
@@ -572,37 +574,30 @@ func file_proto_product_proto_rawDescGZIP() []byte {
 	return file_proto_product_proto_rawDescData
 }
 
-var file_proto_product_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_proto_product_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_proto_product_proto_goTypes = []any{
-	(*CreateProductRequest)(nil),         // 0: product.CreateProductRequest
-	(*GetProductRequest)(nil),            // 1: product.GetProductRequest
-	(*GetAllProductsRequest)(nil),        // 2: product.GetAllProductsRequest
-	(*GetProductsByCategoryRequest)(nil), // 3: product.GetProductsByCategoryRequest
-	(*UpdateProductRequest)(nil),         // 4: product.UpdateProductRequest
-	(*DeleteProductRequest)(nil),         // 5: product.DeleteProductRequest
-	(*ProductResponse)(nil),              // 6: product.ProductResponse
-	(*GetAllProductsResponse)(nil),       // 7: product.GetAllProductsResponse
-	(*DeleteProductResponse)(nil),        // 8: product.DeleteProductResponse
+	(*GetProductRequest)(nil),        // 0: product.GetProductRequest
+	(*GetProductsByIDsRequest)(nil),  // 1: product.GetProductsByIDsRequest
+	(*GetProductsByIDsResponse)(nil), // 2: product.GetProductsByIDsResponse
+	(*CreateProductRequest)(nil),     // 3: product.CreateProductRequest
+	(*ProductResponse)(nil),          // 4: product.ProductResponse
+	(*Dimensions)(nil),               // 5: product.Dimensions
 }
 var file_proto_product_proto_depIdxs = []int32{
-	6, // 0: product.GetAllProductsResponse.products:type_name -> product.ProductResponse
-	0, // 1: product.ProductService.CreateProduct:input_type -> product.CreateProductRequest
-	1, // 2: product.ProductService.GetProduct:input_type -> product.GetProductRequest
-	2, // 3: product.ProductService.GetAllProducts:input_type -> product.GetAllProductsRequest
-	3, // 4: product.ProductService.GetProductsByCategory:input_type -> product.GetProductsByCategoryRequest
-	4, // 5: product.ProductService.UpdateProduct:input_type -> product.UpdateProductRequest
-	5, // 6: product.ProductService.DeleteProduct:input_type -> product.DeleteProductRequest
-	6, // 7: product.ProductService.CreateProduct:output_type -> product.ProductResponse
-	6, // 8: product.ProductService.GetProduct:output_type -> product.ProductResponse
-	7, // 9: product.ProductService.GetAllProducts:output_type -> product.GetAllProductsResponse
-	7, // 10: product.ProductService.GetProductsByCategory:output_type -> product.GetAllProductsResponse
-	6, // 11: product.ProductService.UpdateProduct:output_type -> product.ProductResponse
-	8, // 12: product.ProductService.DeleteProduct:output_type -> product.DeleteProductResponse
-	7, // [7:13] is the sub-list for method output_type
-	1, // [1:7] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	4, // 0: product.GetProductsByIDsResponse.products:type_name -> product.ProductResponse
+	5, // 1: product.CreateProductRequest.dimensions_cm:type_name -> product.Dimensions
+	5, // 2: product.ProductResponse.dimensions_cm:type_name -> product.Dimensions
+	0, // 3: product.ProductService.GetProduct:input_type -> product.GetProductRequest
+	1, // 4: product.ProductService.GetProductsByIDs:input_type -> product.GetProductsByIDsRequest
+	3, // 5: product.ProductService.CreateProduct:input_type -> product.CreateProductRequest
+	4, // 6: product.ProductService.GetProduct:output_type -> product.ProductResponse
+	2, // 7: product.ProductService.GetProductsByIDs:output_type -> product.GetProductsByIDsResponse
+	4, // 8: product.ProductService.CreateProduct:output_type -> product.ProductResponse
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_proto_product_proto_init() }
@@ -616,7 +611,7 @@ func file_proto_product_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_product_proto_rawDesc), len(file_proto_product_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
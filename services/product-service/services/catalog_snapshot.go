@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"log"
+	"product-service/dto"
+	"product-service/models"
+	"sync"
+	"time"
+)
+
+// catalogSnapshot holds the last product list read successfully, so listings
+// can still be served read-only while the database is down
+type catalogSnapshot struct {
+	mu        sync.RWMutex
+	products  []dto.ProductResponse
+	takenAt   time.Time
+	populated bool
+}
+
+// StartCatalogSnapshot takes a snapshot of the catalog now and then every
+// CATALOG_SNAPSHOT_INTERVAL until ctx is done. It does nothing when the
+// snapshot is disabled.
+func (s *ProductService) StartCatalogSnapshot(ctx context.Context) {
+	interval := s.catalogInterval
+	if interval == 0 {
+		return
+	}
+	s.catalog = &catalogSnapshot{}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := s.refreshCatalog(ctx); err != nil {
+				log.Printf("Failed to refresh catalog snapshot: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// refreshCatalog replaces the snapshot with the current product list. A
+// failed read keeps the previous snapshot.
+func (s *ProductService) refreshCatalog(ctx context.Context) error {
+	var products []models.Product
+	if err := s.db.WithContext(ctx).Order("id").Find(&products).Error; err != nil {
+		return err
+	}
+
+	responses := make([]dto.ProductResponse, 0, len(products))
+	for _, product := range products {
+		responses = append(responses, *s.modelToResponse(&product))
+	}
+
+	s.catalog.mu.Lock()
+	defer s.catalog.mu.Unlock()
+	s.catalog.products = responses
+	s.catalog.takenAt = time.Now()
+	s.catalog.populated = true
+	return nil
+}
+
+// page returns the requested window of the snapshot marked stale, or false
+// if no snapshot has been taken yet
+func (c *catalogSnapshot) page(page dto.Pagination) (*dto.ProductListResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.populated {
+		return nil, false
+	}
+
+	start := min(page.Offset, len(c.products))
+	end := min(start+page.Limit, len(c.products))
+	items := make([]dto.ProductResponse, end-start)
+	copy(items, c.products[start:end])
+
+	takenAt := c.takenAt
+	return &dto.ProductListResponse{
+		Items:     items,
+		Total:     int64(len(c.products)),
+		Limit:     page.Limit,
+		Offset:    page.Offset,
+		Stale:     true,
+		StaleAsOf: &takenAt,
+	}, true
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"product-service/config"
+	"product-service/dto"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// failQueries makes every query on db fail with err from then on
+func failQueries(t *testing.T, db *gorm.DB, err error) {
+	t.Helper()
+	if err := db.Callback().Query().Before("gorm:query").Register("test:fail", func(tx *gorm.DB) {
+		tx.AddError(err)
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetAllProductsServesStaleSnapshot(t *testing.T) {
+	s, db := newTestService(t, config.Config{})
+	s.catalog = &catalogSnapshot{}
+	ctx := context.Background()
+	for _, name := range []string{"Lamp", "Desk", "Chair"} {
+		mustCreate(t, s, dto.CreateProductRequest{Name: name, Price: 10, Category: "home"})
+	}
+	if err := s.refreshCatalog(ctx); err != nil {
+		t.Fatalf("refreshCatalog: %v", err)
+	}
+
+	dbErr := errors.New("connection refused")
+	failQueries(t, db, dbErr)
+
+	got, err := s.GetAllProducts(ctx, dto.Pagination{Limit: 2, Offset: 1}, false)
+	if err != nil {
+		t.Fatalf("GetAllProducts with the database down: %v", err)
+	}
+	if !got.Stale || got.StaleAsOf == nil {
+		t.Errorf("stale = %v, stale_as_of = %v, want the page marked stale", got.Stale, got.StaleAsOf)
+	}
+	if got.Total != 3 || len(got.Items) != 2 || got.Items[0].Name != "Desk" || got.Items[1].Name != "Chair" {
+		t.Errorf("page = %+v, want Desk and Chair of 3", got)
+	}
+}
+
+func TestGetAllProductsWithoutSnapshot(t *testing.T) {
+	dbErr := errors.New("connection refused")
+
+	// Disabled snapshot
+	s, db := newTestService(t, config.Config{})
+	mustCreate(t, s, dto.CreateProductRequest{Name: "Lamp", Price: 10, Category: "home"})
+	failQueries(t, db, dbErr)
+	if _, err := s.GetAllProducts(context.Background(), dto.Pagination{Limit: 10}, false); !errors.Is(err, dbErr) {
+		t.Errorf("snapshot disabled: err = %v, want the database error", err)
+	}
+
+	// Enabled but not taken yet
+	s, db = newTestService(t, config.Config{})
+	s.catalog = &catalogSnapshot{}
+	failQueries(t, db, dbErr)
+	if _, err := s.GetAllProducts(context.Background(), dto.Pagination{Limit: 10}, false); !errors.Is(err, dbErr) {
+		t.Errorf("snapshot not taken yet: err = %v, want the database error", err)
+	}
+}
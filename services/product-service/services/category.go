@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"product-service/apperror"
+	"product-service/dto"
+	"product-service/models"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidCategory is returned when a category isn't in the allowlist
+var ErrInvalidCategory = apperror.Validation("invalid category")
+
+// Category casing modes selected with CATEGORY_CASE; any other value leaves
+// categories as given
+const (
+	categoryCaseLower = "lowercase"
+	categoryCaseTitle = "title"
+)
+
+// categoryAllowlist is the fixed set of categories products may use, in the
+// configured order and casing
+type categoryAllowlist struct {
+	names []string
+	// canonical maps categoryKey of each name to the name itself
+	canonical map[string]string
+}
+
+// categoryKey folds a category for case-insensitive comparison
+func categoryKey(category string) string {
+	return strings.ToLower(strings.Join(strings.Fields(category), " "))
+}
+
+// newCategoryAllowlist builds the allowlist from the configured categories.
+// No categories allows any; duplicates that differ only in casing keep the
+// first.
+func newCategoryAllowlist(categories []string) *categoryAllowlist {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	allowlist := &categoryAllowlist{canonical: make(map[string]string)}
+	for _, name := range categories {
+		name = strings.Join(strings.Fields(name), " ")
+		key := categoryKey(name)
+		if existing, ok := allowlist.canonical[key]; ok {
+			log.Printf("Duplicate category %q in ALLOWED_CATEGORIES, keeping %q", name, existing)
+			continue
+		}
+		allowlist.canonical[key] = name
+		allowlist.names = append(allowlist.names, name)
+	}
+	return allowlist
+}
+
+// normalizeCategory applies the configured casing so that "Electronics" and
+// "electronics" are stored and filtered as the same category. Surrounding
+// whitespace is trimmed and inner runs collapsed to one space. Categories in
+// the allowlist take its casing instead.
+func (s *ProductService) normalizeCategory(category string) string {
+	if s.allowedCategories != nil {
+		if name, ok := s.allowedCategories.canonical[categoryKey(category)]; ok {
+			return name
+		}
+	}
+
+	switch s.categoryCase {
+	case categoryCaseLower:
+		return strings.ToLower(strings.Join(strings.Fields(category), " "))
+	case categoryCaseTitle:
+		words := strings.Fields(category)
+		for i, word := range words {
+			first, size := utf8.DecodeRuneInString(word)
+			words[i] = string(unicode.ToTitle(first)) + strings.ToLower(word[size:])
+		}
+		return strings.Join(words, " ")
+	default:
+		return category
+	}
+}
+
+// canonicalCategory returns the category to store for a write. With an
+// allowlist configured, categories outside it are rejected with the valid
+// choices in the message.
+func (s *ProductService) canonicalCategory(category string) (string, error) {
+	if s.allowedCategories == nil {
+		return s.normalizeCategory(category), nil
+	}
+	name, ok := s.allowedCategories.canonical[categoryKey(category)]
+	if !ok {
+		return "", fmt.Errorf("%w %q, must be one of: %s", ErrInvalidCategory, category, strings.Join(s.allowedCategories.names, ", "))
+	}
+	return name, nil
+}
+
+// GetCategories returns the allowed categories, or the distinct categories
+// currently in use when no allowlist is configured
+func (s *ProductService) GetCategories(ctx context.Context) (*dto.CategoryListResponse, error) {
+	if s.allowedCategories != nil {
+		return &dto.CategoryListResponse{Categories: s.allowedCategories.names, Restricted: true}, nil
+	}
+
+	categories := []string{}
+	if err := s.db.WithContext(ctx).Model(&models.Product{}).Distinct("category").Order("category").Pluck("category", &categories).Error; err != nil {
+		return nil, err
+	}
+	return &dto.CategoryListResponse{Categories: categories}, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"product-service/config"
+	"product-service/dto"
+	"testing"
+)
+
+func TestNormalizeCategory(t *testing.T) {
+	tests := []struct {
+		mode     string
+		category string
+		want     string
+	}{
+		{"", "  Home  Office ", "  Home  Office "},
+		{categoryCaseLower, "Electronics", "electronics"},
+		{categoryCaseLower, "  Home  OFFICE ", "home office"},
+		{categoryCaseTitle, "electronics", "Electronics"},
+		{categoryCaseTitle, "  home  OFFICE ", "Home Office"},
+		{categoryCaseTitle, "élan vital", "Élan Vital"},
+	}
+	for _, tt := range tests {
+		s := &ProductService{categoryCase: tt.mode}
+		if got := s.normalizeCategory(tt.category); got != tt.want {
+			t.Errorf("normalizeCategory(%q) with mode %q = %q, want %q", tt.category, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestCategoryCaseMergesVariants(t *testing.T) {
+	for _, tt := range []struct {
+		mode string
+		want string
+	}{{categoryCaseLower, "electronics"}, {categoryCaseTitle, "Electronics"}} {
+		s, _ := newTestService(t, config.Config{CategoryCase: tt.mode})
+		ctx := context.Background()
+		for _, category := range []string{"Electronics", "electronics", " ELECTRONICS "} {
+			product := mustCreate(t, s, dto.CreateProductRequest{Name: "Radio", Price: 20, Category: category})
+			if product.Category != tt.want {
+				t.Errorf("mode %q: %q stored as %q, want %q", tt.mode, category, product.Category, tt.want)
+			}
+		}
+
+		for _, query := range []string{"ELECTRONICS", "electronics"} {
+			page, err := s.GetProductsByCategory(ctx, query, dto.Pagination{Limit: 10})
+			if err != nil {
+				t.Fatalf("GetProductsByCategory(%q): %v", query, err)
+			}
+			if page.Total != 3 {
+				t.Errorf("mode %q: filtering by %q found %d products, want 3", tt.mode, query, page.Total)
+			}
+		}
+
+		stats, err := s.GetPriceStatsByCategory(ctx)
+		if err != nil {
+			t.Fatalf("GetPriceStatsByCategory: %v", err)
+		}
+		if len(stats) != 1 || stats[0].Category != tt.want || stats[0].Count != 3 {
+			t.Errorf("mode %q: price stats = %+v, want one category %q of 3 products", tt.mode, stats, tt.want)
+		}
+	}
+}
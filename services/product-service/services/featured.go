@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"math"
+	"product-service/dto"
+	"product-service/models"
+	"sort"
+)
+
+const (
+	// DefaultFeaturedCount is how many featured products are returned by default
+	DefaultFeaturedCount = 5
+	// MaxFeaturedCount caps how many featured products may be requested
+	MaxFeaturedCount = 50
+)
+
+// GetFeaturedProducts returns up to count distinct products chosen at random
+// with probability proportional to their FeaturedWeight. Products with a zero
+// weight are never featured.
+func (s *ProductService) GetFeaturedProducts(ctx context.Context, count int) ([]dto.ProductResponse, error) {
+	var products []models.Product
+	if err := s.db.WithContext(ctx).Where("featured_weight > 0").Order("id").Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	// Weighted sampling without replacement (Efraimidis-Spirakis): give each
+	// product the key u^(1/w) and keep the count largest keys
+	keys := make([]float64, len(products))
+	s.rngMu.Lock()
+	for i, product := range products {
+		keys[i] = math.Pow(s.rng.Float64(), 1/product.FeaturedWeight)
+	}
+	s.rngMu.Unlock()
+
+	order := make([]int, len(products))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return keys[order[a]] > keys[order[b]] })
+
+	if count > len(order) {
+		count = len(order)
+	}
+
+	responses := make([]dto.ProductResponse, 0, count)
+	for _, i := range order[:count] {
+		responses = append(responses, *s.modelToResponse(&products[i]))
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"product-service/config"
+	"product-service/dto"
+	"testing"
+)
+
+func TestGetFeaturedProductsWeighting(t *testing.T) {
+	s, _ := newTestService(t, config.Config{FeaturedRandomSeed: 42})
+	weights := map[string]float64{"light": 1, "medium": 3, "heavy": 6, "hidden": 0}
+	ids := make(map[uint]string)
+	for _, name := range []string{"light", "medium", "heavy", "hidden"} {
+		product := mustCreate(t, s, dto.CreateProductRequest{Name: name, Price: 1, Category: "misc", FeaturedWeight: weights[name]})
+		ids[product.ID] = name
+	}
+
+	const runs = 5000
+	picks := make(map[string]int)
+	for i := 0; i < runs; i++ {
+		featured, err := s.GetFeaturedProducts(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("GetFeaturedProducts: %v", err)
+		}
+		if len(featured) != 1 {
+			t.Fatalf("got %d products, want 1", len(featured))
+		}
+		picks[ids[featured[0].ID]]++
+	}
+
+	if picks["hidden"] != 0 {
+		t.Errorf("product with zero weight was featured %d times", picks["hidden"])
+	}
+	// A single pick is proportional to weight: 10%, 30% and 60%
+	for name, want := range map[string]float64{"light": 0.1, "medium": 0.3, "heavy": 0.6} {
+		got := float64(picks[name]) / runs
+		if got < want-0.03 || got > want+0.03 {
+			t.Errorf("%s featured in %.3f of runs, want about %.2f", name, got, want)
+		}
+	}
+}
+
+func TestGetFeaturedProductsDistinct(t *testing.T) {
+	s, _ := newTestService(t, config.Config{FeaturedRandomSeed: 7})
+	for i, weight := range []float64{1, 5, 50, 0} {
+		mustCreate(t, s, dto.CreateProductRequest{Name: string(rune('a' + i)), Price: 1, Category: "misc", FeaturedWeight: weight})
+	}
+
+	for _, count := range []int{2, 3, 10} {
+		for i := 0; i < 100; i++ {
+			featured, err := s.GetFeaturedProducts(context.Background(), count)
+			if err != nil {
+				t.Fatalf("GetFeaturedProducts(%d): %v", count, err)
+			}
+			if want := min(count, 3); len(featured) != want {
+				t.Fatalf("GetFeaturedProducts(%d) returned %d products, want %d", count, len(featured), want)
+			}
+			seen := make(map[uint]bool)
+			for _, product := range featured {
+				if seen[product.ID] {
+					t.Fatalf("GetFeaturedProducts(%d) returned product %d twice", count, product.ID)
+				}
+				seen[product.ID] = true
+			}
+		}
+	}
+}
+
+func TestGetFeaturedProductsSeeded(t *testing.T) {
+	picks := func() []uint {
+		s, _ := newTestService(t, config.Config{FeaturedRandomSeed: 99})
+		for i := 0; i < 5; i++ {
+			mustCreate(t, s, dto.CreateProductRequest{Name: string(rune('a' + i)), Price: 1, Category: "misc", FeaturedWeight: float64(i + 1)})
+		}
+		var ids []uint
+		for i := 0; i < 10; i++ {
+			featured, err := s.GetFeaturedProducts(context.Background(), 2)
+			if err != nil {
+				t.Fatalf("GetFeaturedProducts: %v", err)
+			}
+			for _, product := range featured {
+				ids = append(ids, product.ID)
+			}
+		}
+		return ids
+	}
+
+	first, second := picks(), picks()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("same seed gave different selections: %v and %v", first, second)
+		}
+	}
+}
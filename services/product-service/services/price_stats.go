@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"product-service/dto"
+	"product-service/models"
+)
+
+// priceAggregate holds the SQL-computed price aggregates for one category
+type priceAggregate struct {
+	Category string
+	Count    int64
+	MinPrice float64
+	MaxPrice float64
+	AvgPrice float64
+}
+
+// categoryPrice is a single product price tagged with its category
+type categoryPrice struct {
+	Category string
+	Price    float64
+}
+
+// GetPriceStats returns price statistics for a single category. A category
+// without products yields zeroed statistics.
+func (s *ProductService) GetPriceStats(ctx context.Context, category string) (*dto.PriceStats, error) {
+	category = s.normalizeCategory(category)
+	stats, err := s.priceStats(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	if len(stats) == 0 {
+		return &dto.PriceStats{Category: category}, nil
+	}
+	return &stats[0], nil
+}
+
+// GetPriceStatsByCategory returns price statistics for every category
+func (s *ProductService) GetPriceStatsByCategory(ctx context.Context) ([]dto.PriceStats, error) {
+	return s.priceStats(ctx, "")
+}
+
+// priceStats computes min, max and average with SQL aggregates and the median
+// with a Go-side pass over the sorted prices, optionally for one category
+func (s *ProductService) priceStats(ctx context.Context, category string) ([]dto.PriceStats, error) {
+	aggQuery := s.db.WithContext(ctx).Model(&models.Product{}).
+		Select("category, COUNT(*) AS count, MIN(price) AS min_price, MAX(price) AS max_price, AVG(price) AS avg_price")
+	priceQuery := s.db.WithContext(ctx).Model(&models.Product{}).Select("category, price")
+	if category != "" {
+		aggQuery = aggQuery.Where("category = ?", category)
+		priceQuery = priceQuery.Where("category = ?", category)
+	}
+
+	var aggregates []priceAggregate
+	if err := aggQuery.Group("category").Order("category").Scan(&aggregates).Error; err != nil {
+		return nil, err
+	}
+
+	var prices []categoryPrice
+	if err := priceQuery.Order("category, price").Scan(&prices).Error; err != nil {
+		return nil, err
+	}
+
+	sorted := make(map[string][]float64)
+	for _, p := range prices {
+		sorted[p.Category] = append(sorted[p.Category], p.Price)
+	}
+
+	stats := make([]dto.PriceStats, 0, len(aggregates))
+	for _, agg := range aggregates {
+		stats = append(stats, dto.PriceStats{
+			Category: agg.Category,
+			Count:    agg.Count,
+			Min:      agg.MinPrice,
+			Max:      agg.MaxPrice,
+			Average:  agg.AvgPrice,
+			Median:   median(sorted[agg.Category]),
+		})
+	}
+
+	return stats, nil
+}
+
+// median returns the median of an ascending slice, averaging the two middle
+// values for an even count
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"product-service/config"
+	"product-service/dto"
+	"testing"
+)
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		sorted []float64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"single", []float64{7}, 7},
+		{"odd count", []float64{1, 3, 10}, 3},
+		{"even count", []float64{1, 3, 5, 10}, 4},
+		{"even count of two", []float64{2.5, 3.5}, 3},
+	}
+	for _, tt := range tests {
+		if got := median(tt.sorted); got != tt.want {
+			t.Errorf("%s: median(%v) = %v, want %v", tt.name, tt.sorted, got, tt.want)
+		}
+	}
+}
+
+func TestGetPriceStats(t *testing.T) {
+	s, _ := newTestService(t, config.Config{})
+	for _, p := range []struct {
+		category string
+		price    float64
+	}{
+		{"books", 12}, {"books", 8}, {"books", 30}, {"books", 10},
+		{"toys", 5}, {"toys", 25}, {"toys", 15},
+	} {
+		mustCreate(t, s, dto.CreateProductRequest{Name: p.category, Price: p.price, Category: p.category})
+	}
+	ctx := context.Background()
+
+	want := []dto.PriceStats{
+		{Category: "books", Count: 4, Min: 8, Max: 30, Average: 15, Median: 11},
+		{Category: "toys", Count: 3, Min: 5, Max: 25, Average: 15, Median: 15},
+	}
+	for _, w := range want {
+		got, err := s.GetPriceStats(ctx, w.Category)
+		if err != nil {
+			t.Fatalf("GetPriceStats(%q): %v", w.Category, err)
+		}
+		if *got != w {
+			t.Errorf("GetPriceStats(%q) = %+v, want %+v", w.Category, *got, w)
+		}
+	}
+
+	all, err := s.GetPriceStatsByCategory(ctx)
+	if err != nil {
+		t.Fatalf("GetPriceStatsByCategory: %v", err)
+	}
+	if len(all) != len(want) {
+		t.Fatalf("GetPriceStatsByCategory returned %d categories, want %d", len(all), len(want))
+	}
+	for i := range want {
+		if all[i] != want[i] {
+			t.Errorf("GetPriceStatsByCategory()[%d] = %+v, want %+v", i, all[i], want[i])
+		}
+	}
+
+	empty, err := s.GetPriceStats(ctx, "garden")
+	if err != nil {
+		t.Fatalf("GetPriceStats(empty category): %v", err)
+	}
+	if *empty != (dto.PriceStats{Category: "garden"}) {
+		t.Errorf("GetPriceStats(empty category) = %+v, want zeroed stats", *empty)
+	}
+}
@@ -1,45 +1,148 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"product-service/apperror"
+	"product-service/config"
 	"product-service/dto"
+	"product-service/events"
 	"product-service/models"
+	"strings"
+	"sync"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// ErrInsufficientStock is returned when a stock adjustment would go below zero
+var ErrInsufficientStock = apperror.Conflict("insufficient stock")
+
 // ProductService handles product business logic
 type ProductService struct {
-	db *gorm.DB
+	db              *gorm.DB
+	events          events.Publisher
+	reorder         reorderPolicy
+	orderServiceURL string
+
+	// categoryCase is the casing applied to categories; empty keeps them as given
+	categoryCase string
+	// allowedCategories restricts the categories products may use; nil
+	// allows any
+	allowedCategories *categoryAllowlist
+
+	// catalog is the fallback for listings while the database is down,
+	// refreshed every catalogInterval; nil when the snapshot is disabled
+	catalog         *catalogSnapshot
+	catalogInterval time.Duration
+
+	// rng drives featured product sampling; guarded by rngMu
+	rng   *rand.Rand
+	rngMu sync.Mutex
 }
 
 // NewProductService creates a new product service
-func NewProductService(db *gorm.DB) *ProductService {
-	return &ProductService{db: db}
+func NewProductService(db *gorm.DB, cfg config.Config) *ProductService {
+	return &ProductService{
+		db:                db,
+		events:            events.LogPublisher{},
+		reorder:           reorderPolicy{point: cfg.ReorderPoint, quantity: cfg.ReorderQuantity},
+		orderServiceURL:   cfg.OrderServiceURL,
+		categoryCase:      cfg.CategoryCase,
+		allowedCategories: newCategoryAllowlist(cfg.AllowedCategories),
+		catalogInterval:   cfg.CatalogSnapshotInterval,
+		rng:               rand.New(rand.NewSource(cfg.FeaturedRandomSeed)),
+	}
 }
 
 // CreateProduct creates a new product
-func (s *ProductService) CreateProduct(req dto.CreateProductRequest) (*dto.ProductResponse, error) {
-	product := models.Product{
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
-		Category:    req.Category,
+func (s *ProductService) CreateProduct(ctx context.Context, req dto.CreateProductRequest) (*dto.ProductResponse, error) {
+	product, err := s.newProduct(req)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.db.Create(&product).Error; err != nil {
+	if err := s.db.WithContext(ctx).Create(&product).Error; err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperror.Conflict("product with this barcode already exists")
+		}
 		return nil, err
 	}
 
 	return s.modelToResponse(&product), nil
 }
 
-// GetProduct retrieves a product by ID
-func (s *ProductService) GetProduct(id uint) (*dto.ProductResponse, error) {
+// MaxBulkProducts caps how many products a single bulk create may insert
+const MaxBulkProducts = 100
+
+// CreateProducts creates several products with one batch insert inside a
+// transaction and returns them with their IDs. Every item is checked before
+// anything is written, so one invalid item rejects the whole batch.
+func (s *ProductService) CreateProducts(ctx context.Context, reqs []dto.CreateProductRequest) ([]dto.ProductResponse, error) {
+	products := make([]models.Product, len(reqs))
+	for i, req := range reqs {
+		product, err := s.newProduct(req)
+		if err != nil {
+			return nil, apperror.Validation("item %d: %w", i, err)
+		}
+		products[i] = product
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&products).Error
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperror.Conflict("a product with one of these barcodes already exists")
+		}
+		return nil, err
+	}
+
+	responses := make([]dto.ProductResponse, 0, len(products))
+	for i := range products {
+		responses = append(responses, *s.modelToResponse(&products[i]))
+	}
+	return responses, nil
+}
+
+// newProduct builds the model for a create request, canonicalizing its
+// category
+func (s *ProductService) newProduct(req dto.CreateProductRequest) (models.Product, error) {
+	category, err := s.canonicalCategory(req.Category)
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	return models.Product{
+		Name:           req.Name,
+		Description:    req.Description,
+		Price:          req.Price,
+		Currency:       req.Currency,
+		Category:       category,
+		Barcode:        barcodeOrNil(req.Barcode),
+		FeaturedWeight: req.FeaturedWeight,
+		WeightGrams:    req.WeightGrams,
+		Stock:          req.Stock,
+		AvailableFrom:  req.AvailableFrom,
+		AvailableUntil: req.AvailableUntil,
+		DimensionsCM: models.Dimensions{
+			Length: req.DimensionsCM.Length,
+			Width:  req.DimensionsCM.Width,
+			Height: req.DimensionsCM.Height,
+		},
+	}, nil
+}
+
+// GetProduct retrieves a product by ID. Soft-deleted products are found only
+// with includeDeleted.
+func (s *ProductService) GetProduct(ctx context.Context, id uint, includeDeleted bool) (*dto.ProductResponse, error) {
 	var product models.Product
-	if err := s.db.First(&product, id).Error; err != nil {
+	if err := scopeDeleted(s.db.WithContext(ctx), includeDeleted).First(&product, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
+			return nil, apperror.NotFound("product not found")
 		}
 		return nil, err
 	}
@@ -47,42 +150,112 @@ func (s *ProductService) GetProduct(id uint) (*dto.ProductResponse, error) {
 	return s.modelToResponse(&product), nil
 }
 
-// GetAllProducts retrieves all products
-func (s *ProductService) GetAllProducts() ([]dto.ProductResponse, error) {
+// MaxBatchIDs caps how many products a single batch fetch may request
+const MaxBatchIDs = 100
+
+// GetProductsByIDs retrieves the products with the given IDs in one query,
+// ordered by ID. IDs that don't exist are simply absent from the result.
+func (s *ProductService) GetProductsByIDs(ctx context.Context, ids []uint) ([]dto.ProductResponse, error) {
 	var products []models.Product
-	if err := s.db.Find(&products).Error; err != nil {
+	if err := s.db.WithContext(ctx).Where("id IN ?", ids).Order("id").Find(&products).Error; err != nil {
 		return nil, err
 	}
 
-	var responses []dto.ProductResponse
+	responses := make([]dto.ProductResponse, 0, len(products))
 	for _, product := range products {
 		responses = append(responses, *s.modelToResponse(&product))
 	}
-
 	return responses, nil
 }
 
+// GetProductByBarcode retrieves a product by its UPC/EAN barcode
+func (s *ProductService) GetProductByBarcode(ctx context.Context, barcode string) (*dto.ProductResponse, error) {
+	var product models.Product
+	if err := s.db.WithContext(ctx).Where("barcode = ?", barcode).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("product not found")
+		}
+		return nil, err
+	}
+
+	return s.modelToResponse(&product), nil
+}
+
+// GetAllProducts retrieves a page of products, including soft-deleted ones
+// with includeDeleted. When the database fails and the catalog snapshot is
+// enabled, a page without deleted products is served from the snapshot and
+// marked stale instead.
+func (s *ProductService) GetAllProducts(ctx context.Context, page dto.Pagination, includeDeleted bool) (*dto.ProductListResponse, error) {
+	products, err := s.listProducts(scopeDeleted(s.db.WithContext(ctx), includeDeleted).Model(&models.Product{}), page)
+	if err == nil || s.catalog == nil || includeDeleted {
+		return products, err
+	}
+
+	stale, ok := s.catalog.page(page)
+	if !ok {
+		return nil, err
+	}
+	log.Printf("Serving product listing from catalog snapshot: %v", err)
+	return stale, nil
+}
+
 // GetProductsByCategory retrieves products by category
-func (s *ProductService) GetProductsByCategory(category string) ([]dto.ProductResponse, error) {
+func (s *ProductService) GetProductsByCategory(ctx context.Context, category string, page dto.Pagination) (*dto.ProductListResponse, error) {
+	return s.listProducts(s.db.WithContext(ctx).Model(&models.Product{}).Where("category = ?", s.normalizeCategory(category)), page)
+}
+
+// likeEscaper escapes LIKE wildcards so a search matches them literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SearchProducts retrieves products whose name contains query, ignoring
+// case, optionally restricted to a category
+func (s *ProductService) SearchProducts(ctx context.Context, query, category string, page dto.Pagination) (*dto.ProductListResponse, error) {
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(query)) + "%"
+	db := s.db.WithContext(ctx).Model(&models.Product{}).Where(`LOWER(name) LIKE ? ESCAPE '\'`, pattern)
+	if category != "" {
+		db = db.Where("category = ?", s.normalizeCategory(category))
+	}
+	return s.listProducts(db, page)
+}
+
+// listProducts counts the rows matched by query and returns the requested
+// page of them, ordered by ID so pages are stable
+func (s *ProductService) listProducts(query *gorm.DB, page dto.Pagination) (*dto.ProductListResponse, error) {
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
 	var products []models.Product
-	if err := s.db.Where("category = ?", category).Find(&products).Error; err != nil {
+	if err := query.Order("id").Limit(page.Limit).Offset(page.Offset).Find(&products).Error; err != nil {
 		return nil, err
 	}
 
-	var responses []dto.ProductResponse
+	responses := make([]dto.ProductResponse, 0, len(products))
 	for _, product := range products {
 		responses = append(responses, *s.modelToResponse(&product))
 	}
 
-	return responses, nil
+	return &dto.ProductListResponse{
+		Items:  responses,
+		Total:  total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}, nil
 }
 
-// UpdateProduct updates an existing product
-func (s *ProductService) UpdateProduct(id uint, req dto.UpdateProductRequest) (*dto.ProductResponse, error) {
+// UpdateProduct replaces an existing product's details. Stock is not
+// written, so adjustments made since the product was read are kept.
+func (s *ProductService) UpdateProduct(ctx context.Context, id uint, req dto.UpdateProductRequest) (*dto.ProductResponse, error) {
+	category, err := s.canonicalCategory(req.Category)
+	if err != nil {
+		return nil, err
+	}
+
 	var product models.Product
-	if err := s.db.First(&product, id).Error; err != nil {
+	if err := s.db.WithContext(ctx).First(&product, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
+			return nil, apperror.NotFound("product not found")
 		}
 		return nil, err
 	}
@@ -90,41 +263,309 @@ func (s *ProductService) UpdateProduct(id uint, req dto.UpdateProductRequest) (*
 	product.Name = req.Name
 	product.Description = req.Description
 	product.Price = req.Price
-	product.Category = req.Category
+	if req.Currency != "" {
+		product.Currency = req.Currency
+	}
+	product.Category = category
+	product.Barcode = barcodeOrNil(req.Barcode)
+	product.FeaturedWeight = req.FeaturedWeight
+	product.WeightGrams = req.WeightGrams
+	product.AvailableFrom = req.AvailableFrom
+	product.AvailableUntil = req.AvailableUntil
+	product.DimensionsCM = models.Dimensions{
+		Length: req.DimensionsCM.Length,
+		Width:  req.DimensionsCM.Width,
+		Height: req.DimensionsCM.Height,
+	}
 
-	if err := s.db.Save(&product).Error; err != nil {
+	if err := s.db.WithContext(ctx).Omit("stock").Save(&product).Error; err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperror.Conflict("product with this barcode already exists")
+		}
 		return nil, err
 	}
 
 	return s.modelToResponse(&product), nil
 }
 
+// PatchProduct updates only the fields set in req, leaving the rest of the
+// product untouched
+func (s *ProductService) PatchProduct(ctx context.Context, id uint, req dto.PatchProductRequest) (*dto.ProductResponse, error) {
+	var product models.Product
+	if err := s.db.WithContext(ctx).First(&product, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("product not found")
+		}
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Price != nil {
+		updates["price"] = *req.Price
+	}
+	if req.Currency != nil {
+		updates["currency"] = *req.Currency
+	}
+	if req.Category != nil {
+		category, err := s.canonicalCategory(*req.Category)
+		if err != nil {
+			return nil, err
+		}
+		updates["category"] = category
+	}
+	if req.Barcode != nil {
+		updates["barcode"] = barcodeOrNil(*req.Barcode)
+	}
+	if req.FeaturedWeight != nil {
+		updates["featured_weight"] = *req.FeaturedWeight
+	}
+	if req.WeightGrams != nil {
+		updates["weight_grams"] = *req.WeightGrams
+	}
+	if req.DimensionsCM != nil {
+		updates["dimensions_cm_length"] = req.DimensionsCM.Length
+		updates["dimensions_cm_width"] = req.DimensionsCM.Width
+		updates["dimensions_cm_height"] = req.DimensionsCM.Height
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.WithContext(ctx).Model(&product).Updates(updates).Error; err != nil {
+			if isUniqueViolation(err) {
+				return nil, apperror.Conflict("product with this barcode already exists")
+			}
+			return nil, err
+		}
+		if err := s.db.WithContext(ctx).First(&product, id).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return s.modelToResponse(&product), nil
+}
+
+// AdjustStock atomically changes a product's stock by delta. The update is
+// guarded in SQL so concurrent adjustments can never drive stock negative.
+// When a decrease takes stock to the reorder point, a replenishment is
+// recorded in the same transaction and product.reorder_needed is published.
+func (s *ProductService) AdjustStock(ctx context.Context, id uint, delta int) (*dto.ProductResponse, error) {
+	var (
+		product       models.Product
+		replenishment *models.Replenishment
+	)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Product{}).
+			Where("id = ? AND stock + ? >= 0", id, delta).
+			Update("stock", gorm.Expr("stock + ?", delta))
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if err := tx.First(&product, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.NotFound("product not found")
+			}
+			return err
+		}
+
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("%w: %d in stock", ErrInsufficientStock, product.Stock)
+		}
+
+		// The updated row stays locked until commit, so product.Stock is
+		// exactly the result of this adjustment
+		if s.reorder.crossed(product.Stock-delta, product.Stock) {
+			var err error
+			replenishment, err = s.recordReplenishment(tx, &product)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if replenishment != nil {
+		s.publishReorderNeeded(ctx, replenishment)
+	}
+	return s.modelToResponse(&product), nil
+}
+
+// Ping checks that the database accepts connections within timeout
+func (s *ProductService) Ping(ctx context.Context, timeout time.Duration) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
 // DeleteProduct deletes a product by ID
-func (s *ProductService) DeleteProduct(id uint) error {
+func (s *ProductService) DeleteProduct(ctx context.Context, id uint) error {
+	var product models.Product
+	if err := s.db.WithContext(ctx).First(&product, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("product not found")
+		}
+		return err
+	}
+
+	if err := s.db.WithContext(ctx).Delete(&product).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// BulkAssignCategory sets the category of every listed product in a single
+// transaction, reporting how many were updated and which IDs don't exist
+func (s *ProductService) BulkAssignCategory(ctx context.Context, req dto.BulkCategoryRequest) (*dto.BulkCategoryResponse, error) {
+	category, err := s.canonicalCategory(req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.BulkCategoryResponse{MissingIDs: []uint{}}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var foundIDs []uint
+		if err := tx.Model(&models.Product{}).Where("id IN ?", req.IDs).Pluck("id", &foundIDs).Error; err != nil {
+			return err
+		}
+
+		found := make(map[uint]bool, len(foundIDs))
+		for _, id := range foundIDs {
+			found[id] = true
+		}
+		for _, id := range req.IDs {
+			if !found[id] {
+				result.MissingIDs = append(result.MissingIDs, id)
+				found[id] = true // report each missing ID once
+			}
+		}
+
+		if len(foundIDs) == 0 {
+			return nil
+		}
+
+		res := tx.Model(&models.Product{}).Where("id IN ?", foundIDs).Update("category", category)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Updated = res.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RestoreProduct undoes a soft delete. Restoring a product that isn't
+// deleted leaves it unchanged.
+func (s *ProductService) RestoreProduct(ctx context.Context, id uint) (*dto.ProductResponse, error) {
+	var product models.Product
+	if err := s.db.WithContext(ctx).Unscoped().First(&product, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("product not found")
+		}
+		return nil, err
+	}
+
+	if product.DeletedAt.Valid {
+		if err := s.db.WithContext(ctx).Unscoped().Model(&product).Update("deleted_at", nil).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return s.modelToResponse(&product), nil
+}
+
+// scopeDeleted widens query to soft-deleted rows when includeDeleted is set
+func scopeDeleted(query *gorm.DB, includeDeleted bool) *gorm.DB {
+	if includeDeleted {
+		return query.Unscoped()
+	}
+	return query
+}
+
+// HardDeleteProduct permanently removes a product, including one that has
+// already been soft-deleted
+func (s *ProductService) HardDeleteProduct(ctx context.Context, id uint) error {
 	var product models.Product
-	if err := s.db.First(&product, id).Error; err != nil {
+	if err := s.db.WithContext(ctx).Unscoped().First(&product, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("product not found")
+			return apperror.NotFound("product not found")
 		}
 		return err
 	}
 
-	if err := s.db.Delete(&product).Error; err != nil {
+	if err := s.db.WithContext(ctx).Unscoped().Delete(&product).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// barcodeOrNil maps an empty barcode to NULL so products without one don't
+// collide on the unique index
+func barcodeOrNil(barcode string) *string {
+	if barcode == "" {
+		return nil
+	}
+	return &barcode
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation.
+// database.ConnectDB has GORM translate it from whichever driver is in use.
+func isUniqueViolation(err error) bool {
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
 // modelToResponse converts a Product model to ProductResponse DTO
 func (s *ProductService) modelToResponse(product *models.Product) *dto.ProductResponse {
+	var barcode string
+	if product.Barcode != nil {
+		barcode = *product.Barcode
+	}
+
 	return &dto.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-		Category:    product.Category,
-		CreatedAt:   product.CreatedAt,
-		UpdatedAt:   product.UpdatedAt,
+		ID:             product.ID,
+		Name:           product.Name,
+		Description:    product.Description,
+		Price:          product.Price,
+		Currency:       product.Currency,
+		Category:       product.Category,
+		Barcode:        barcode,
+		FeaturedWeight: product.FeaturedWeight,
+		WeightGrams:    product.WeightGrams,
+		Stock:          product.Stock,
+		AvailableFrom:  product.AvailableFrom,
+		AvailableUntil: product.AvailableUntil,
+		DimensionsCM: dto.Dimensions{
+			Length: product.DimensionsCM.Length,
+			Width:  product.DimensionsCM.Width,
+			Height: product.DimensionsCM.Height,
+		},
+		CreatedAt: product.CreatedAt,
+		UpdatedAt: product.UpdatedAt,
+		DeletedAt: deletedAt(product.DeletedAt),
+	}
+}
+
+// deletedAt returns when a product was soft-deleted, or nil
+func deletedAt(deleted gorm.DeletedAt) *time.Time {
+	if !deleted.Valid {
+		return nil
 	}
+	return &deleted.Time
 }
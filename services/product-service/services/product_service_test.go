@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"product-service/apperror"
+	"product-service/config"
+	"product-service/dto"
+	"product-service/models"
+	"reflect"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestService returns a service configured with cfg and backed by a fresh
+// in-memory SQLite database
+func newTestService(t *testing.T, cfg config.Config) (*ProductService, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:         logger.Discard,
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	// Every connection to :memory: is a separate database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.Product{}, &models.Replenishment{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return NewProductService(db, cfg), db
+}
+
+// hasCode reports whether err is an apperror with code
+func hasCode(err error, code apperror.Code) bool {
+	var appErr *apperror.Error
+	return errors.As(err, &appErr) && appErr.Code == code
+}
+
+// mustCreate inserts a product through the service
+func mustCreate(t *testing.T, s *ProductService, req dto.CreateProductRequest) *dto.ProductResponse {
+	t.Helper()
+	product, err := s.CreateProduct(context.Background(), req)
+	if err != nil {
+		t.Fatalf("create product %q: %v", req.Name, err)
+	}
+	return product
+}
+
+func TestDeleteProductIsRecoverable(t *testing.T) {
+	s, db := newTestService(t, config.Config{})
+	product := mustCreate(t, s, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+
+	if err := s.DeleteProduct(context.Background(), product.ID); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+	if _, err := s.GetProduct(context.Background(), product.ID, false); !hasCode(err, apperror.CodeNotFound) {
+		t.Errorf("GetProduct after soft delete: err = %v, want not_found", err)
+	}
+	var row models.Product
+	if err := db.Unscoped().First(&row, product.ID).Error; err != nil {
+		t.Fatalf("Unscoped().First after soft delete: %v", err)
+	}
+	if !row.DeletedAt.Valid {
+		t.Error("soft-deleted row has no deleted_at")
+	}
+
+	if _, err := s.RestoreProduct(context.Background(), product.ID); err != nil {
+		t.Fatalf("RestoreProduct: %v", err)
+	}
+	if _, err := s.GetProduct(context.Background(), product.ID, false); err != nil {
+		t.Errorf("GetProduct after restore: %v", err)
+	}
+}
+
+func TestHardDeleteProductRemovesRow(t *testing.T) {
+	for _, softFirst := range []bool{false, true} {
+		s, db := newTestService(t, config.Config{})
+		product := mustCreate(t, s, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+
+		if softFirst {
+			if err := s.DeleteProduct(context.Background(), product.ID); err != nil {
+				t.Fatalf("DeleteProduct: %v", err)
+			}
+		}
+		if err := s.HardDeleteProduct(context.Background(), product.ID); err != nil {
+			t.Fatalf("HardDeleteProduct (soft deleted first: %v): %v", softFirst, err)
+		}
+
+		var row models.Product
+		if err := db.Unscoped().First(&row, product.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("Unscoped().First after hard delete (soft deleted first: %v): err = %v, want ErrRecordNotFound", softFirst, err)
+		}
+		if _, err := s.RestoreProduct(context.Background(), product.ID); !hasCode(err, apperror.CodeNotFound) {
+			t.Errorf("RestoreProduct after hard delete: err = %v, want not_found", err)
+		}
+	}
+}
+
+func TestBulkAssignCategory(t *testing.T) {
+	s, _ := newTestService(t, config.Config{})
+	lamp := mustCreate(t, s, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+	desk := mustCreate(t, s, dto.CreateProductRequest{Name: "Desk", Price: 149, Category: "home"})
+	chair := mustCreate(t, s, dto.CreateProductRequest{Name: "Chair", Price: 89, Category: "home"})
+
+	got, err := s.BulkAssignCategory(context.Background(), dto.BulkCategoryRequest{IDs: []uint{lamp.ID, desk.ID}, Category: "office"})
+	if err != nil {
+		t.Fatalf("BulkAssignCategory: %v", err)
+	}
+	if got.Updated != 2 || len(got.MissingIDs) != 0 {
+		t.Errorf("result = %+v, want 2 updated and no missing IDs", got)
+	}
+
+	for _, tc := range []struct {
+		id   uint
+		want string
+	}{{lamp.ID, "office"}, {desk.ID, "office"}, {chair.ID, "home"}} {
+		product, err := s.GetProduct(context.Background(), tc.id, false)
+		if err != nil {
+			t.Fatalf("GetProduct(%d): %v", tc.id, err)
+		}
+		if product.Category != tc.want {
+			t.Errorf("product %d category = %q, want %q", tc.id, product.Category, tc.want)
+		}
+	}
+}
+
+func TestBulkAssignCategoryMissingIDs(t *testing.T) {
+	s, _ := newTestService(t, config.Config{})
+	lamp := mustCreate(t, s, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+
+	got, err := s.BulkAssignCategory(context.Background(), dto.BulkCategoryRequest{IDs: []uint{lamp.ID, 98, 99, 98}, Category: "office"})
+	if err != nil {
+		t.Fatalf("BulkAssignCategory: %v", err)
+	}
+	if got.Updated != 1 {
+		t.Errorf("updated = %d, want 1", got.Updated)
+	}
+	if want := []uint{98, 99}; !reflect.DeepEqual(got.MissingIDs, want) {
+		t.Errorf("missing IDs = %v, want %v", got.MissingIDs, want)
+	}
+
+	got, err = s.BulkAssignCategory(context.Background(), dto.BulkCategoryRequest{IDs: []uint{98}, Category: "office"})
+	if err != nil {
+		t.Fatalf("BulkAssignCategory with only missing IDs: %v", err)
+	}
+	if got.Updated != 0 || !reflect.DeepEqual(got.MissingIDs, []uint{98}) {
+		t.Errorf("result = %+v, want nothing updated and 98 missing", got)
+	}
+}
+
+func TestBulkAssignCategoryRejectsUnknownCategory(t *testing.T) {
+	s, _ := newTestService(t, config.Config{AllowedCategories: []string{"Home", "Office"}})
+	ctx := context.Background()
+	lamp := mustCreate(t, s, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home"})
+
+	if _, err := s.BulkAssignCategory(ctx, dto.BulkCategoryRequest{IDs: []uint{lamp.ID}, Category: "garden"}); !errors.Is(err, ErrInvalidCategory) {
+		t.Errorf("err = %v, want ErrInvalidCategory", err)
+	}
+	product, err := s.GetProduct(ctx, lamp.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.Category != "Home" {
+		t.Errorf("category = %q, want it unchanged as %q", product.Category, "Home")
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"log"
+	"product-service/events"
+	"product-service/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// reorderPolicy decides when a stock decrease should trigger replenishment.
+// A zero quantity disables automatic replenishment.
+type reorderPolicy struct {
+	point    int
+	quantity int
+}
+
+// crossed reports whether stock moving from before to after fell to or below
+// the reorder point. Only the crossing triggers, so further decreases while
+// already below the point don't create duplicate replenishments.
+func (p reorderPolicy) crossed(before, after int) bool {
+	return p.quantity > 0 && before > p.point && after <= p.point
+}
+
+// reorderNeeded is the payload of the product.reorder_needed event
+type reorderNeeded struct {
+	ReplenishmentID uint `json:"replenishment_id"`
+	ProductID       uint `json:"product_id"`
+	Stock           int  `json:"stock"`
+	ReorderPoint    int  `json:"reorder_point"`
+	ReorderQuantity int  `json:"reorder_quantity"`
+}
+
+// recordReplenishment creates the replenishment for a product whose stock
+// just crossed the reorder point, within the stock adjustment's transaction
+func (s *ProductService) recordReplenishment(tx *gorm.DB, product *models.Product) (*models.Replenishment, error) {
+	replenishment := models.Replenishment{
+		ProductID:      product.ID,
+		Quantity:       s.reorder.quantity,
+		StockAtTrigger: product.Stock,
+	}
+	if err := tx.Create(&replenishment).Error; err != nil {
+		return nil, err
+	}
+	return &replenishment, nil
+}
+
+// publishReorderNeeded emits product.reorder_needed for a committed
+// replenishment. Publishing is best effort; the replenishment row remains the
+// source of truth if it fails.
+func (s *ProductService) publishReorderNeeded(ctx context.Context, replenishment *models.Replenishment) {
+	err := s.events.Publish(ctx, events.Event{
+		Name:       events.ReorderNeeded,
+		OccurredAt: time.Now().UTC(),
+		Data: reorderNeeded{
+			ReplenishmentID: replenishment.ID,
+			ProductID:       replenishment.ProductID,
+			Stock:           replenishment.StockAtTrigger,
+			ReorderPoint:    s.reorder.point,
+			ReorderQuantity: replenishment.Quantity,
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to publish %s for product %d: %v", events.ReorderNeeded, replenishment.ProductID, err)
+	}
+}
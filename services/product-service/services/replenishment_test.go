@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"product-service/config"
+	"product-service/dto"
+	"product-service/events"
+	"product-service/models"
+	"testing"
+)
+
+// recordingPublisher keeps every published event
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event events.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestAdjustStockTriggersReplenishment(t *testing.T) {
+	s, db := newTestService(t, config.Config{ReorderPoint: 5, ReorderQuantity: 40})
+	published := &recordingPublisher{}
+	s.events = published
+	ctx := context.Background()
+	product := mustCreate(t, s, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home", Stock: 10})
+
+	// 10 -> 7 stays above the point, 7 -> 4 crosses it, 4 -> 2 is already
+	// below, and restocking to 12 then selling to 4 crosses it again
+	steps := []struct {
+		delta    int
+		triggers bool
+	}{{-3, false}, {-3, true}, {-2, false}, {10, false}, {-8, true}}
+	var want int
+	for _, step := range steps {
+		if _, err := s.AdjustStock(ctx, product.ID, step.delta); err != nil {
+			t.Fatalf("AdjustStock(%d): %v", step.delta, err)
+		}
+		if step.triggers {
+			want++
+		}
+		if len(published.events) != want {
+			t.Fatalf("after AdjustStock(%d): %d events published, want %d", step.delta, len(published.events), want)
+		}
+	}
+
+	var replenishments []models.Replenishment
+	if err := db.Order("id").Find(&replenishments).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(replenishments) != 2 {
+		t.Fatalf("%d replenishments recorded, want 2", len(replenishments))
+	}
+	for i, r := range replenishments {
+		if r.ProductID != product.ID || r.Quantity != 40 || r.StockAtTrigger != 4 {
+			t.Errorf("replenishment %d = %+v, want product %d, quantity 40, stock 4", i, r, product.ID)
+		}
+
+		event := published.events[i]
+		data, ok := event.Data.(reorderNeeded)
+		if event.Name != events.ReorderNeeded || !ok {
+			t.Fatalf("event %d = %+v, want %s", i, event, events.ReorderNeeded)
+		}
+		wantData := reorderNeeded{ReplenishmentID: r.ID, ProductID: product.ID, Stock: 4, ReorderPoint: 5, ReorderQuantity: 40}
+		if data != wantData {
+			t.Errorf("event %d data = %+v, want %+v", i, data, wantData)
+		}
+	}
+}
+
+func TestAdjustStockReplenishmentDisabled(t *testing.T) {
+	s, db := newTestService(t, config.Config{ReorderPoint: 5})
+	published := &recordingPublisher{}
+	s.events = published
+	product := mustCreate(t, s, dto.CreateProductRequest{Name: "Lamp", Price: 19.99, Category: "home", Stock: 10})
+
+	if _, err := s.AdjustStock(context.Background(), product.ID, -10); err != nil {
+		t.Fatalf("AdjustStock: %v", err)
+	}
+
+	var count int64
+	if err := db.Model(&models.Replenishment{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 || len(published.events) != 0 {
+		t.Errorf("with no reorder quantity: %d replenishments and %d events, want none", count, len(published.events))
+	}
+}
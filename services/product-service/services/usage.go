@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"product-service/apperror"
+	"product-service/dto"
+	"product-service/middleware"
+	"product-service/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// usageSampleSize caps how many recent orders and category siblings a
+	// usage response includes
+	usageSampleSize = 10
+)
+
+var orderClient = &http.Client{Timeout: 3 * time.Second}
+
+// GetCategorySiblings returns the other products in the product's category,
+// up to usageSampleSize of them, with the total count
+func (s *ProductService) GetCategorySiblings(ctx context.Context, id uint) (*dto.ProductListResponse, error) {
+	var product models.Product
+	if err := s.db.WithContext(ctx).First(&product, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("product not found")
+		}
+		return nil, err
+	}
+
+	query := s.db.WithContext(ctx).Model(&models.Product{}).
+		Where("category = ? AND id <> ?", product.Category, product.ID)
+	return s.listProducts(query, dto.Pagination{Limit: usageSampleSize})
+}
+
+// FetchProductOrders asks the order service how many orders reference a
+// product and for the most recent of them. The request ID carried by ctx is
+// forwarded so both services log under the same ID.
+func (s *ProductService) FetchProductOrders(ctx context.Context, productID uint) (*dto.ProductOrderUsage, error) {
+	url := fmt.Sprintf("%s/orders/search?product_id=%d&sort=-created_at&limit=%d", s.orderServiceURL, productID, usageSampleSize)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if id := middleware.RequestIDFromContext(ctx); id != "" {
+		req.Header.Set(middleware.RequestIDHeader, id)
+	}
+
+	resp, err := orderClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch orders: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("order service returned status %d", resp.StatusCode)
+	}
+
+	var page struct {
+		Items []dto.OrderResponse `json:"items"`
+		Total int64               `json:"total"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode orders: %v", err)
+	}
+
+	usage := &dto.ProductOrderUsage{Total: page.Total, Recent: make([]dto.OrderResponse, 0, len(page.Items))}
+	for _, order := range page.Items {
+		if order.ProductID == productID {
+			usage.Recent = append(usage.Recent, order)
+		}
+	}
+	return usage, nil
+}
@@ -0,0 +1,132 @@
+// Package config loads the service's settings from environment variables
+// once at startup. Unset variables take their defaults; malformed ones are
+// reported together by Load so the service can refuse to start.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+)
+
+// Config holds the user service's settings
+type Config struct {
+	Port     int    // PORT
+	LogLevel string // LOG_LEVEL: debug, info, warn or error
+	Database Database
+
+	// PIIFields names the fields masked in log lines besides email addresses
+	// (PII_FIELDS, comma-separated)
+	PIIFields []string
+
+	OrderServiceURL string // ORDER_SERVICE_URL
+
+	HTTP HTTP
+
+	CacheControlUsers  string // CACHE_CONTROL_USERS
+	CacheControlHealth string // CACHE_CONTROL_HEALTH
+}
+
+// Load reads the configuration from the environment
+func Load() (Config, error) {
+	var l loader
+	cfg := Config{
+		Port:     l.port("PORT", 8080),
+		LogLevel: l.logLevel("LOG_LEVEL"),
+		Database: l.database("user_service"),
+
+		PIIFields: l.list("PII_FIELDS"),
+
+		OrderServiceURL: l.url("ORDER_SERVICE_URL", "http://localhost:8082"),
+
+		HTTP: l.http(),
+
+		CacheControlUsers:  l.string("CACHE_CONTROL_USERS", "no-store"),
+		CacheControlHealth: l.string("CACHE_CONTROL_HEALTH", "no-store"),
+	}
+	return cfg, l.err()
+}
+
+// Database holds the PostgreSQL connection settings
+type Database struct {
+	Host     string // DB_HOST
+	Port     int    // DB_PORT
+	User     string // DB_USER
+	Password string // DB_PASSWORD
+	Name     string // DB_NAME
+	SSLMode  string // DB_SSLMODE
+
+	// ConnectAttempts is how many times to try connecting at startup
+	// (DB_CONNECT_ATTEMPTS), ConnectRetryDelay the wait between attempts
+	// (DB_CONNECT_RETRY_DELAY)
+	ConnectAttempts   int
+	ConnectRetryDelay time.Duration
+}
+
+// DSN returns the connection string for the database
+func (d Database) DSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode)
+}
+
+// HTTP holds the settings of the HTTP server and its middleware
+type HTTP struct {
+	RequireIdempotencyKey bool   // REQUIRE_IDEMPOTENCY_KEY
+	JWTSecret             string // JWT_SECRET; empty disables authentication
+	MaxHeaderBytes        int    // MAX_HEADER_BYTES
+	MaxBodyBytes          int64  // MAX_BODY_BYTES
+	MaxRepeatedValues     int    // MAX_REPEATED_VALUES
+	AllowedOrigins        string // ALLOWED_ORIGINS
+	TrailingSlashMode     string // TRAILING_SLASH_MODE: rewrite or redirect
+
+	// RateLimitRPS is the per-client rate (RATE_LIMIT_RPS); zero disables
+	// rate limiting. RateLimitBurst defaults to the rate rounded up.
+	RateLimitRPS        float64
+	RateLimitBurst      int  // RATE_LIMIT_BURST
+	RateLimitTrustProxy bool // RATE_LIMIT_TRUST_PROXY
+}
+
+func (l *loader) http() HTTP {
+	cfg := HTTP{
+		RequireIdempotencyKey: l.bool("REQUIRE_IDEMPOTENCY_KEY", false),
+		JWTSecret:             l.string("JWT_SECRET", ""),
+		MaxHeaderBytes:        l.int("MAX_HEADER_BYTES", http.DefaultMaxHeaderBytes, 1),
+		MaxBodyBytes:          int64(l.int("MAX_BODY_BYTES", 1<<20, 1)),
+		MaxRepeatedValues:     l.int("MAX_REPEATED_VALUES", 20, 1),
+		AllowedOrigins:        l.string("ALLOWED_ORIGINS", ""),
+		TrailingSlashMode:     l.oneOf("TRAILING_SLASH_MODE", "rewrite", "rewrite", "redirect"),
+		RateLimitRPS:          l.rate("RATE_LIMIT_RPS"),
+		RateLimitTrustProxy:   l.bool("RATE_LIMIT_TRUST_PROXY", false),
+	}
+	cfg.RateLimitBurst = l.int("RATE_LIMIT_BURST", int(math.Ceil(cfg.RateLimitRPS)), 1)
+	return cfg
+}
+
+func (l *loader) database(defaultName string) Database {
+	return Database{
+		Host:     l.string("DB_HOST", "localhost"),
+		Port:     l.port("DB_PORT", 5432),
+		User:     l.string("DB_USER", "postgres"),
+		Password: l.string("DB_PASSWORD", "password"),
+		Name:     l.string("DB_NAME", defaultName),
+		SSLMode:  l.oneOf("DB_SSLMODE", "disable", "disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
+
+		ConnectAttempts:   l.int("DB_CONNECT_ATTEMPTS", 10, 1),
+		ConnectRetryDelay: l.duration("DB_CONNECT_RETRY_DELAY", 2*time.Second),
+	}
+}
+
+// errInvalid wraps every error Load reports
+var errInvalid = errors.New("invalid configuration")
+
+// fail records that key has a malformed value
+func (l *loader) fail(key, value, reason string) {
+	l.errs = append(l.errs, fmt.Errorf("%w: %s=%q %s", errInvalid, key, value, reason))
+}
+
+// err joins everything recorded by fail, or returns nil
+func (l *loader) err() error {
+	return errors.Join(l.errs...)
+}
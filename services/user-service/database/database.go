@@ -0,0 +1,96 @@
+package database
+
+import (
+	"log"
+	"time"
+
+	"user-service/config"
+	"user-service/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// DB is the global database instance
+var DB *gorm.DB
+
+// gormLogger reports only errors and slow queries, through the standard log
+// package so they come out as JSON like every other line. Queries are logged
+// with placeholders instead of their values, which can be personal data.
+var gormLogger = logger.New(log.Default(), logger.Config{
+	SlowThreshold:             200 * time.Millisecond,
+	LogLevel:                  logger.Warn,
+	IgnoreRecordNotFoundError: true,
+	ParameterizedQueries:      true,
+})
+
+// ConnectDB establishes connection to PostgreSQL database. A database that
+// isn't accepting connections yet, as when it starts alongside the service,
+// is retried up to cfg.ConnectAttempts times before the service gives up.
+func ConnectDB(cfg config.Database) {
+	var err error
+	for attempt := 1; ; attempt++ {
+		DB, err = open(cfg.DSN())
+		if err == nil {
+			break
+		}
+		if attempt >= cfg.ConnectAttempts {
+			log.Fatalf("Failed to connect to database after %d attempts: %v", attempt, err)
+		}
+		log.Printf("Database not ready (attempt %d/%d), retrying in %s: %v", attempt, cfg.ConnectAttempts, cfg.ConnectRetryDelay, err)
+		time.Sleep(cfg.ConnectRetryDelay)
+	}
+
+	log.Println("Database connected successfully")
+}
+
+// open connects once and pings the database to confirm the connection is
+// usable
+func open(dsn string) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: gormLogger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// MigrateDB runs database migrations
+func MigrateDB() {
+	err := DB.AutoMigrate(&models.User{})
+	if err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+	log.Println("Database migration completed")
+}
+
+// SeedDB inserts the sample users when the users table is empty
+func SeedDB() {
+	var count int64
+	if err := DB.Model(&models.User{}).Count(&count).Error; err != nil {
+		log.Fatal("Failed to count users:", err)
+	}
+	if count > 0 {
+		return
+	}
+
+	users := []models.User{
+		{Name: "John Doe", Email: "john@example.com"},
+		{Name: "Jane Smith", Email: "jane@example.com"},
+	}
+	if err := DB.Create(&users).Error; err != nil {
+		log.Fatal("Failed to seed users:", err)
+	}
+	log.Println("Seeded sample users")
+}
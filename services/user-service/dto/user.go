@@ -0,0 +1,50 @@
+package dto
+
+import "time"
+
+// CreateUserRequest represents the request payload for creating a user
+type CreateUserRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password,omitempty" validate:"omitempty,min=8"`
+}
+
+// UpdateUserRequest represents the request payload for updating a user
+type UpdateUserRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+// VerifyCredentialsRequest represents the request payload for checking a
+// user's email and password
+type VerifyCredentialsRequest struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// UserResponse represents the response payload for user data
+type UserResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrderResponse represents order data fetched from the order service
+type OrderResponse struct {
+	ID         uint      `json:"id"`
+	UserID     uint      `json:"user_id"`
+	ProductID  uint      `json:"product_id"`
+	Quantity   uint      `json:"quantity"`
+	TotalPrice float64   `json:"total_price"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// UserWithOrdersResponse represents a user with their orders embedded
+type UserWithOrdersResponse struct {
+	*UserResponse
+	Orders  []OrderResponse `json:"orders"` // null when the order service is unavailable
+	Warning string          `json:"warning,omitempty"`
+}
@@ -1,5 +1,21 @@
 module user-service
 
-go 1.21
+go 1.22
 
-require ()
+require (
+	github.com/jackc/pgx/v5 v5.4.3
+	golang.org/x/crypto v0.14.0
+	golang.org/x/time v0.10.0
+	gorm.io/driver/postgres v1.5.4
+	gorm.io/driver/sqlite v1.5.4
+	gorm.io/gorm v1.25.5
+)
+
+require (
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	github.com/mattn/go-sqlite3 v1.14.17 // indirect
+	golang.org/x/text v0.13.0 // indirect
+)
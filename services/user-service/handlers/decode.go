@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// decodeJSON decodes the request body into v, writing a 400 or 413 and
+// returning false when it can't. Fields v doesn't declare are rejected so a
+// typo such as "emial" fails instead of leaving a zero value, and a body cut
+// off by middleware.LimitBody is reported as too large.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err == nil {
+		return true
+	}
+
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		http.Error(w, fmt.Sprintf("Request body too large, limit is %d bytes", maxErr.Limit), http.StatusRequestEntityTooLarge)
+		return false
+	}
+	// encoding/json has no typed error for unknown fields
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		http.Error(w, "Unknown field "+field, http.StatusBadRequest)
+		return false
+	}
+	http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	return false
+}
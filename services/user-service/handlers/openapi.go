@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"user-service/dto"
+	"user-service/openapi"
+)
+
+// errorResponses documents error statuses; the user service answers errors
+// with a plain-text message
+func errorResponses(statuses ...int) []openapi.Response {
+	responses := make([]openapi.Response, len(statuses))
+	for i, status := range statuses {
+		responses[i] = openapi.Response{Status: status, Description: http.StatusText(status) + " (plain-text message)"}
+	}
+	return responses
+}
+
+// okResponses documents a 200 response with body
+func okResponses(body interface{}, errors ...int) []openapi.Response {
+	return append([]openapi.Response{{Status: http.StatusOK, Body: body}}, errorResponses(errors...)...)
+}
+
+// Operations describes every route the user service registers. Add new
+// routes here alongside their registration in main so GET /openapi.json
+// stays complete.
+func Operations() []openapi.Operation {
+	idParam := openapi.Param{Name: "id", Type: "integer", Required: true, Description: "User ID"}
+	pathID := openapi.Param{Name: "id", In: "path", Type: "integer", Description: "User ID"}
+
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/users",
+			Summary: "Get a user by ?id=, optionally with their orders, or all users",
+			Params: []openapi.Param{
+				openapi.Query("id", "integer", "User ID; omit to list users"),
+				openapi.Query("include", "string", "orders to embed the user's orders"),
+				openapi.Query("tz", "string", "IANA zone to render timestamps in, e.g. America/New_York"),
+			},
+			Responses: okResponses(openapi.OneOf(dto.UserResponse{}, dto.UserWithOrdersResponse{}, []dto.UserResponse{}), 400, 404),
+		},
+		{
+			Method: http.MethodPost, Path: "/users",
+			Summary: "Create a user",
+			Params:  []openapi.Param{{Name: "Idempotency-Key", In: "header", Type: "string", Description: "Required when REQUIRE_IDEMPOTENCY_KEY is enabled; repeated keys are not deduplicated"}},
+			Body:    dto.CreateUserRequest{},
+			Responses: append([]openapi.Response{{Status: http.StatusCreated, Body: dto.UserResponse{}}},
+				errorResponses(400, 409, 413)...),
+		},
+		{
+			Method: http.MethodPut, Path: "/users", Auth: true,
+			Summary:   "Update a user",
+			Params:    []openapi.Param{idParam},
+			Body:      dto.UpdateUserRequest{},
+			Responses: okResponses(dto.UserResponse{}, 400, 401, 404, 409, 413),
+		},
+		{
+			Method: http.MethodDelete, Path: "/users", Auth: true,
+			Summary: "Delete a user",
+			Params:  []openapi.Param{idParam},
+			Responses: append([]openapi.Response{{Status: http.StatusNoContent}},
+				errorResponses(400, 401, 404)...),
+		},
+		{
+			Method: http.MethodGet, Path: "/users/{id}",
+			Summary: "Get a user, optionally with their orders",
+			Params: []openapi.Param{
+				pathID,
+				openapi.Query("include", "string", "orders to embed the user's orders"),
+				openapi.Query("tz", "string", "IANA zone to render timestamps in, e.g. America/New_York"),
+			},
+			Responses: okResponses(openapi.OneOf(dto.UserResponse{}, dto.UserWithOrdersResponse{}), 400, 404),
+		},
+		{
+			Method: http.MethodPut, Path: "/users/{id}", Auth: true,
+			Summary:   "Update a user",
+			Params:    []openapi.Param{pathID},
+			Body:      dto.UpdateUserRequest{},
+			Responses: okResponses(dto.UserResponse{}, 400, 401, 404, 409, 413),
+		},
+		{
+			Method: http.MethodDelete, Path: "/users/{id}", Auth: true,
+			Summary: "Delete a user",
+			Params:  []openapi.Param{pathID},
+			Responses: append([]openapi.Response{{Status: http.StatusNoContent}},
+				errorResponses(400, 401, 404)...),
+		},
+		{
+			Method: http.MethodPost, Path: "/users/verify-credentials",
+			Summary:   "Check an email and password, returning the user on a match",
+			Body:      dto.VerifyCredentialsRequest{},
+			Responses: okResponses(dto.UserResponse{}, 400, 401, 413),
+		},
+		{
+			Method: http.MethodGet, Path: "/health",
+			Summary:   "Liveness check",
+			Responses: []openapi.Response{{Status: http.StatusOK, Description: "Plain-text status"}},
+		},
+		{
+			Method: http.MethodGet, Path: "/openapi.json",
+			Summary:   "This document",
+			Responses: []openapi.Response{{Status: http.StatusOK, Description: "OpenAPI 3.0 document"}},
+		},
+	}
+}
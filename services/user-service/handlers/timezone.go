@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+	"user-service/dto"
+)
+
+// parseTimezone resolves the optional ?tz= query parameter. A nil location
+// means timestamps are returned as stored (UTC).
+func parseTimezone(r *http.Request) (*time.Location, error) {
+	tz := r.URL.Query().Get("tz")
+	if tz == "" {
+		return nil, nil
+	}
+	return time.LoadLocation(tz)
+}
+
+// localizeUser converts the user's timestamps to the given location
+func localizeUser(user *dto.UserResponse, loc *time.Location) {
+	if loc == nil || user == nil {
+		return
+	}
+	user.CreatedAt = user.CreatedAt.In(loc)
+}
+
+// localizeUsers converts the timestamps of every user in the slice
+func localizeUsers(users []dto.UserResponse, loc *time.Location) {
+	for i := range users {
+		localizeUser(&users[i], loc)
+	}
+}
+
+// localizeOrders converts the timestamps of every order in the slice
+func localizeOrders(orders []dto.OrderResponse, loc *time.Location) {
+	if loc == nil {
+		return
+	}
+	for i := range orders {
+		orders[i].CreatedAt = orders[i].CreatedAt.In(loc)
+		orders[i].UpdatedAt = orders[i].UpdatedAt.In(loc)
+	}
+}
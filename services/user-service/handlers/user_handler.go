@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"user-service/dto"
+	"user-service/logging"
+	"user-service/services"
+)
+
+// minPasswordLength is the shortest password accepted on create
+const minPasswordLength = 8
+
+// UserHandler handles HTTP requests for user operations
+type UserHandler struct {
+	userService *services.UserService
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userService *services.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+// CreateUser handles POST /users
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req dto.CreateUserRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if req.Name == "" || req.Email == "" {
+		logging.PrintfContext(r.Context(), "Rejected user request: name=%q email=%q", req.Name, req.Email)
+		http.Error(w, "Name and email are required", http.StatusBadRequest)
+		return
+	}
+
+	if !validEmail(req.Email) {
+		logging.PrintfContext(r.Context(), "Rejected user request: email=%q", req.Email)
+		http.Error(w, "invalid email format", http.StatusBadRequest)
+		return
+	}
+
+	if req.Password != "" && len(req.Password) < minPasswordLength {
+		http.Error(w, fmt.Sprintf("Password must be at least %d characters", minPasswordLength), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userService.CreateUser(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, services.ErrEmailTaken) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+// GetUser handles GET /users and GET /users/{id}
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	loc, err := parseTimezone(r)
+	if err != nil {
+		http.Error(w, "Invalid timezone", http.StatusBadRequest)
+		return
+	}
+
+	idStr := resourceID(r)
+	if idStr == "" {
+		// Return all users
+		users, err := h.userService.GetAllUsers(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		localizeUsers(users, loc)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(users)
+		return
+	}
+
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userService.GetUser(r.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	localizeUser(user, loc)
+
+	if r.URL.Query().Get("include") == "orders" {
+		result := dto.UserWithOrdersResponse{UserResponse: user}
+		orders, err := h.userService.FetchUserOrders(r.Context(), uint(id))
+		if err != nil {
+			logging.PrintfContext(r.Context(), "Failed to embed orders for user %d: %v", id, err)
+			result.Warning = "orders unavailable: order service could not be reached"
+		} else {
+			localizeOrders(orders, loc)
+			result.Orders = orders
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// UpdateUser handles PUT /users/{id} and PUT /users?id=
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := resourceID(r)
+	if idStr == "" {
+		http.Error(w, "User ID is required", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.UpdateUserRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if req.Name == "" || req.Email == "" {
+		logging.PrintfContext(r.Context(), "Rejected user request: name=%q email=%q", req.Name, req.Email)
+		http.Error(w, "Name and email are required", http.StatusBadRequest)
+		return
+	}
+
+	if !validEmail(req.Email) {
+		logging.PrintfContext(r.Context(), "Rejected user request: email=%q", req.Email)
+		http.Error(w, "invalid email format", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userService.UpdateUser(r.Context(), uint(id), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			http.Error(w, "User not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrEmailTaken):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// DeleteUser handles DELETE /users/{id} and DELETE /users?id=
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := resourceID(r)
+	if idStr == "" {
+		http.Error(w, "User ID is required", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userService.DeleteUser(r.Context(), uint(id)); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resourceID returns the ID a request addresses: the {id} path segment of
+// routes like GET /users/{id}, or else the ?id= query parameter the older
+// routes take
+func resourceID(r *http.Request) string {
+	if id := r.PathValue("id"); id != "" {
+		return id
+	}
+	return r.URL.Query().Get("id")
+}
+
+// VerifyCredentials handles POST /users/verify-credentials
+func (h *UserHandler) VerifyCredentials(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req dto.VerifyCredentialsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if req.Email == "" || req.Password == "" {
+		http.Error(w, "Email and password are required", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userService.VerifyCredentials(r.Context(), req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			logging.PrintfContext(r.Context(), "Failed credential check for email=%q", req.Email)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// Health handles GET /health
+func (h *UserHandler) Health(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "User Service is healthy")
+}
+
+// validEmail reports whether email is a bare address such as
+// "jane@example.com" (display names like "Jane <jane@example.com>" are rejected)
+func validEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"user-service/config"
+	"user-service/dto"
+	"user-service/models"
+	"user-service/services"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestHandler returns a handler backed by a fresh in-memory SQLite
+// database that fetches orders from orderServiceURL
+func newTestHandler(t *testing.T, orderServiceURL string) *UserHandler {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	// Every connection to :memory: is a separate database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.User{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return NewUserHandler(services.NewUserService(db, config.Config{OrderServiceURL: orderServiceURL}))
+}
+
+// createUser inserts a user through the service, bypassing HTTP
+func createUser(t *testing.T, h *UserHandler, req dto.CreateUserRequest) *dto.UserResponse {
+	t.Helper()
+	user, err := h.userService.CreateUser(context.Background(), req)
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// serve runs handler on a request and returns the recorded response
+func serve(handler http.HandlerFunc, method, target string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, target, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+// captureLog redirects the standard logger into a buffer for the rest of
+// the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestGetUserIncludeOrders(t *testing.T) {
+	orderService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orders" || r.URL.Query().Get("user_id") != "1" {
+			t.Errorf("order service got %s, want /orders?user_id=1", r.URL)
+		}
+		// The second order belongs to someone else and must be filtered out
+		fmt.Fprint(w, `{"items":[
+			{"id":10,"user_id":1,"product_id":3,"quantity":2,"total_price":20,"status":"pending"},
+			{"id":11,"user_id":2,"product_id":3,"quantity":1,"total_price":10,"status":"pending"},
+			{"id":12,"user_id":1,"product_id":4,"quantity":1,"total_price":5,"status":"completed"}
+		]}`)
+	}))
+	defer orderService.Close()
+
+	h := newTestHandler(t, orderService.URL)
+	user := createUser(t, h, dto.CreateUserRequest{Name: "Jane", Email: "jane@example.com"})
+
+	rec := serve(h.GetUser, http.MethodGet, fmt.Sprintf("/users?id=%d&include=orders", user.ID), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var got dto.UserWithOrdersResponse
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.UserResponse == nil || got.Email != "jane@example.com" {
+		t.Errorf("user = %+v, want jane@example.com", got.UserResponse)
+	}
+	if len(got.Orders) != 2 || got.Orders[0].ID != 10 || got.Orders[1].ID != 12 {
+		t.Errorf("orders = %+v, want orders 10 and 12", got.Orders)
+	}
+	if got.Warning != "" {
+		t.Errorf("warning = %q, want none", got.Warning)
+	}
+}
+
+func TestGetUserIncludeOrdersServiceDown(t *testing.T) {
+	orderService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	orderService.Close()
+
+	h := newTestHandler(t, orderService.URL)
+	user := createUser(t, h, dto.CreateUserRequest{Name: "Jane", Email: "jane@example.com"})
+
+	rec := serve(h.GetUser, http.MethodGet, fmt.Sprintf("/users?id=%d&include=orders", user.ID), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var got map[string]json.RawMessage
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if string(got["email"]) != `"jane@example.com"` {
+		t.Errorf("email = %s, want the user still returned", got["email"])
+	}
+	if string(got["orders"]) != "null" {
+		t.Errorf("orders = %s, want null", got["orders"])
+	}
+	if len(got["warning"]) == 0 {
+		t.Error("no warning in degraded response")
+	}
+}
+
+func TestVerifyCredentials(t *testing.T) {
+	h := newTestHandler(t, "")
+	createUser(t, h, dto.CreateUserRequest{Name: "Jane", Email: "jane@example.com", Password: "correct-horse"})
+	createUser(t, h, dto.CreateUserRequest{Name: "Nopass", Email: "nopass@example.com"})
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		want     int
+	}{
+		{"correct password", "jane@example.com", "correct-horse", http.StatusOK},
+		{"email case ignored", "Jane@Example.com", "correct-horse", http.StatusOK},
+		{"wrong password", "jane@example.com", "wrong-horse", http.StatusUnauthorized},
+		{"unknown email", "ghost@example.com", "correct-horse", http.StatusUnauthorized},
+		{"user without password", "nopass@example.com", "", http.StatusBadRequest},
+		{"user without password, any guess", "nopass@example.com", "dummy-password", http.StatusUnauthorized},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serve(h.VerifyCredentials, http.MethodPost, "/users/verify-credentials",
+				dto.VerifyCredentialsRequest{Email: tc.email, Password: tc.password})
+			if rec.Code != tc.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tc.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestPasswordHashNeverReturned(t *testing.T) {
+	h := newTestHandler(t, "")
+	create := serve(h.CreateUser, http.MethodPost, "/users",
+		dto.CreateUserRequest{Name: "Jane", Email: "jane@example.com", Password: "correct-horse"})
+	if create.Code != http.StatusCreated {
+		t.Fatalf("create status = %d: %s", create.Code, create.Body)
+	}
+
+	responses := map[string]*httptest.ResponseRecorder{
+		"create": create,
+		"get":    serve(h.GetUser, http.MethodGet, "/users?id=1", nil),
+		"list":   serve(h.GetUser, http.MethodGet, "/users", nil),
+		"verify": serve(h.VerifyCredentials, http.MethodPost, "/users/verify-credentials",
+			dto.VerifyCredentialsRequest{Email: "jane@example.com", Password: "correct-horse"}),
+	}
+	for name, rec := range responses {
+		body := strings.ToLower(rec.Body.String())
+		if strings.Contains(body, "password") || strings.Contains(body, "$2a$") {
+			t.Errorf("%s response exposes the password hash: %s", name, rec.Body)
+		}
+	}
+}
+
+func TestRejectedEmailIsMaskedInLog(t *testing.T) {
+	h := newTestHandler(t, "")
+	logs := captureLog(t)
+
+	rec := serve(h.CreateUser, http.MethodPost, "/users",
+		dto.CreateUserRequest{Name: "Jane", Email: "Jane Doe <jane.doe@example.com>"})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	rec = serve(h.VerifyCredentials, http.MethodPost, "/users/verify-credentials",
+		dto.VerifyCredentialsRequest{Email: "john@example.com", Password: "guess"})
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	out := logs.String()
+	for _, want := range []string{"<j***@example.com>", `email="j***@example.com"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q does not contain %q", out, want)
+		}
+	}
+	for _, leaked := range []string{"jane.doe@", "john@"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("log %q leaks %q", out, leaked)
+		}
+	}
+}
+
+func TestUserRoutesByPath(t *testing.T) {
+	h := newTestHandler(t, "")
+	createUser(t, h, dto.CreateUserRequest{Name: "Jane", Email: "jane@example.com"})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users", h.GetUser)
+	mux.HandleFunc("GET /users/{id}", h.GetUser)
+	mux.HandleFunc("PUT /users/{id}", h.UpdateUser)
+	mux.HandleFunc("DELETE /users/{id}", h.DeleteUser)
+	mux.HandleFunc("POST /users/verify-credentials", h.VerifyCredentials)
+
+	tests := []struct {
+		method, target string
+		body           interface{}
+		want           int
+	}{
+		{http.MethodGet, "/users/1", nil, http.StatusOK},
+		{http.MethodGet, "/users?id=1", nil, http.StatusOK},
+		{http.MethodGet, "/users/abc", nil, http.StatusBadRequest},
+		{http.MethodPut, "/users/1", dto.UpdateUserRequest{Name: "Jane Doe", Email: "jane@example.com"}, http.StatusOK},
+		{http.MethodPost, "/users/verify-credentials", map[string]string{}, http.StatusBadRequest},
+		{http.MethodDelete, "/users/1", nil, http.StatusNoContent},
+		{http.MethodGet, "/users/1", nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if rec := serve(mux.ServeHTTP, tt.method, tt.target, tt.body); rec.Code != tt.want {
+			t.Errorf("%s %s: status = %d, want %d: %s", tt.method, tt.target, rec.Code, tt.want, rec.Body)
+		}
+	}
+}
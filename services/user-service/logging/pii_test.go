@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMaskEmail(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"john@example.com", "j***@example.com"},
+		{"j@example.com", "j***@example.com"},
+		{"first.last+tag@mail.example.org", "f***@mail.example.org"},
+		{"not-an-email", "not-an-email"},
+		{"@example.com", "@example.com"},
+	}
+	for _, tc := range tests {
+		if got := MaskEmail(tc.in); got != tc.want {
+			t.Errorf("MaskEmail(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	saved := piiFieldPatterns
+	piiFieldPatterns = compileFieldPatterns([]string{"phone"})
+	t.Cleanup(func() { piiFieldPatterns = saved })
+
+	tests := []struct {
+		in, want string
+	}{
+		{"rejected email=\"john@example.com\"", "rejected email=\"j***@example.com\""},
+		{"from jane@example.com to joe@example.org", "from j***@example.com to j***@example.org"},
+		{`{"name":"Jane","phone":"555-0100"}`, `{"name":"Jane","phone":"***"}`},
+		{"phone=555-0100 name=Jane", "phone=*** name=Jane"},
+		{`phone="555 0100"`, "phone=***"},
+		{"telephone=555-0100", "telephone=555-0100"},
+	}
+	for _, tc := range tests {
+		if got := Mask(tc.in); got != tc.want {
+			t.Errorf("Mask(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestPrintfMasksEmails(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	Printf("Rejected user request: email=%q", "john@example.com")
+	if out := buf.String(); !strings.Contains(out, "j***@example.com") || strings.Contains(out, "john@") {
+		t.Errorf("log line %q, want the email masked", out)
+	}
+}
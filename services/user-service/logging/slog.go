@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"user-service/middleware"
+)
+
+// ParseLevel maps a LOG_LEVEL value (debug, info, warn or error) to a slog
+// level. Anything else yields info and false.
+func ParseLevel(value string) (slog.Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info", "":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	default:
+		return slog.LevelInfo, false
+	}
+}
+
+// Setup installs a JSON logger writing to stderr at the given level as the
+// default slog logger. Output from the standard log package is routed through
+// it too, so every line the service writes is a JSON object. piiFields are
+// masked by Mask and Printf along with email addresses.
+func Setup(level string, piiFields []string) {
+	piiFieldPatterns = compileFieldPatterns(piiFields)
+	lvl, ok := ParseLevel(level)
+	handler := contextHandler{slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: lvl})}
+	slog.SetDefault(slog.New(handler))
+	if !ok {
+		slog.Warn("invalid LOG_LEVEL, using info", "value", level)
+	}
+}
+
+// contextHandler adds the request ID carried by the record's context, if any
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := middleware.RequestIDFromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -1,267 +1,124 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
-	"strconv"
-	"sync"
-	"time"
+	"os"
+	_ "time/tzdata" // embed zone data so ?tz= works in minimal images
+	"user-service/config"
+	"user-service/database"
+	"user-service/handlers"
+	"user-service/logging"
+	"user-service/middleware"
+	"user-service/openapi"
+	"user-service/services"
 )
 
-// User represents a user in our system
-type User struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-}
-
-// UserService handles user operations
-type UserService struct {
-	users  map[int]*User
-	nextID int
-	mutex  sync.RWMutex
-}
-
-// NewUserService creates a new user service
-func NewUserService() *UserService {
-	return &UserService{
-		users:  make(map[int]*User),
-		nextID: 1,
-	}
-}
-
-// CreateUser creates a new user
-func (us *UserService) CreateUser(name, email string) *User {
-	us.mutex.Lock()
-	defer us.mutex.Unlock()
-
-	user := &User{
-		ID:        us.nextID,
-		Name:      name,
-		Email:     email,
-		CreatedAt: time.Now(),
-	}
-
-	us.users[us.nextID] = user
-	us.nextID++
-
-	return user
-}
-
-// GetUser retrieves a user by ID
-func (us *UserService) GetUser(id int) (*User, bool) {
-	us.mutex.RLock()
-	defer us.mutex.RUnlock()
-
-	user, exists := us.users[id]
-	return user, exists
-}
-
-// GetAllUsers retrieves all users
-func (us *UserService) GetAllUsers() []*User {
-	us.mutex.RLock()
-	defer us.mutex.RUnlock()
-
-	users := make([]*User, 0, len(us.users))
-	for _, user := range us.users {
-		users = append(users, user)
-	}
-
-	return users
-}
-
-// UpdateUser updates an existing user
-func (us *UserService) UpdateUser(id int, name, email string) (*User, bool) {
-	us.mutex.Lock()
-	defer us.mutex.Unlock()
-
-	user, exists := us.users[id]
-	if !exists {
-		return nil, false
-	}
-
-	user.Name = name
-	user.Email = email
-
-	return user, true
-}
-
-// DeleteUser deletes a user by ID
-func (us *UserService) DeleteUser(id int) bool {
-	us.mutex.Lock()
-	defer us.mutex.Unlock()
-
-	_, exists := us.users[id]
-	if !exists {
-		return false
-	}
-
-	delete(us.users, id)
-	return true
-}
-
-// HTTP handlers
-func (us *UserService) handleCreateUser(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req struct {
-		Name  string `json:"name"`
-		Email string `json:"email"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
-		return
-	}
-
-	if req.Name == "" || req.Email == "" {
-		http.Error(w, "Name and email are required", http.StatusBadRequest)
-		return
-	}
-
-	user := us.CreateUser(req.Name, req.Email)
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(user)
-}
-
-func (us *UserService) handleGetUser(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	idStr := r.URL.Query().Get("id")
-	if idStr == "" {
-		// Return all users
-		users := us.GetAllUsers()
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(users)
-		return
-	}
-
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
-	}
-
-	user, exists := us.GetUser(id)
-	if !exists {
-		http.Error(w, "User not found", http.StatusNotFound)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(user)
-}
-
-func (us *UserService) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	idStr := r.URL.Query().Get("id")
-	if idStr == "" {
-		http.Error(w, "User ID is required", http.StatusBadRequest)
-		return
-	}
-
-	id, err := strconv.Atoi(idStr)
+func main() {
+	// Every setting comes from the environment; refuse to start on a
+	// malformed one rather than run with a surprising default
+	cfg, err := config.Load()
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
-	}
-
-	var req struct {
-		Name  string `json:"name"`
-		Email string `json:"email"`
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
-		return
-	}
-
-	if req.Name == "" || req.Email == "" {
-		http.Error(w, "Name and email are required", http.StatusBadRequest)
-		return
-	}
-
-	user, exists := us.UpdateUser(id, req.Name, req.Email)
-	if !exists {
-		http.Error(w, "User not found", http.StatusNotFound)
-		return
-	}
+	// JSON logs at the configured level (debug, info, warn or error), with
+	// emails and PII_FIELDS masked
+	logging.Setup(cfg.LogLevel, cfg.PIIFields)
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(user)
-}
+	// Connect to database
+	database.ConnectDB(cfg.Database)
+	database.MigrateDB()
+	database.SeedDB()
 
-func (us *UserService) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+	// Initialize services
+	userService := services.NewUserService(database.DB, cfg)
+	userHandler := handlers.NewUserHandler(userService)
 
-	idStr := r.URL.Query().Get("id")
-	if idStr == "" {
-		http.Error(w, "User ID is required", http.StatusBadRequest)
-		return
-	}
+	// Cache policies; user data is personal so it must not be cached
+	usersCacheControl := cfg.CacheControlUsers
+	healthCacheControl := cfg.CacheControlHealth
 
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
-		return
-	}
+	// When enabled, creates must carry an Idempotency-Key header
+	requireIdempotencyKey := cfg.HTTP.RequireIdempotencyKey
 
-	success := us.DeleteUser(id)
-	if !success {
-		http.Error(w, "User not found", http.StatusNotFound)
-		return
+	// Routes wrapped in auth require a bearer JWT signed with JWT_SECRET;
+	// while it is unset they stay open
+	jwtSecret := []byte(cfg.HTTP.JWTSecret)
+	if len(jwtSecret) == 0 {
+		slog.Warn("JWT_SECRET is not set, authentication is disabled")
 	}
-
-	w.WriteHeader(http.StatusNoContent)
-}
-
-func main() {
-	userService := NewUserService()
-
-	// Add some sample data
-	userService.CreateUser("John Doe", "john@example.com")
-	userService.CreateUser("Jane Smith", "jane@example.com")
+	auth := func(next http.HandlerFunc) http.HandlerFunc { return middleware.RequireAuth(jwtSecret, next) }
+	updateUser := auth(userHandler.UpdateUser)
+	deleteUser := auth(userHandler.DeleteUser)
 
 	// Set up routes
-	http.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/users", middleware.CacheControl(usersCacheControl, middleware.RequireIdempotencyKey(requireIdempotencyKey, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
-			userService.handleCreateUser(w, r)
+			userHandler.CreateUser(w, r)
 		case http.MethodGet:
-			userService.handleGetUser(w, r)
+			userHandler.GetUser(w, r)
 		case http.MethodPut:
-			userService.handleUpdateUser(w, r)
+			updateUser(w, r)
 		case http.MethodDelete:
-			userService.handleDeleteUser(w, r)
+			deleteUser(w, r)
 		default:
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
-	})
+	})))
 
-	// Health check endpoint
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprint(w, "User Service is healthy")
-	})
+	// A single user can also be addressed by path, e.g. GET /users/42. The
+	// fixed route below carries a method so the mux ranks it above {id}.
+	http.HandleFunc("GET /users/{id}", middleware.CacheControl(usersCacheControl, userHandler.GetUser))
+	http.HandleFunc("PUT /users/{id}", updateUser)
+	http.HandleFunc("DELETE /users/{id}", deleteUser)
+
+	http.HandleFunc("POST /users/verify-credentials", userHandler.VerifyCredentials)
+
+	// Machine-readable description of the routes registered here
+	apiDoc := openapi.Build(openapi.Info{Title: "User Service", Version: "1.0.0"}, handlers.Operations())
+	http.HandleFunc("/openapi.json", openapi.Handler(apiDoc))
 
-	fmt.Println("User Service starting on port 8080...")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	// Health check endpoint
+	http.HandleFunc("/health", middleware.CacheControl(healthCacheControl, userHandler.Health))
+
+	// Header size cap enforced by net/http (431 when exceeded), and how many
+	// times a single header or query parameter may repeat
+	maxHeaderBytes := cfg.HTTP.MaxHeaderBytes
+	maxRepeated := cfg.HTTP.MaxRepeatedValues
+
+	// Request bodies larger than this are refused with 413
+	maxBodyBytes := cfg.HTTP.MaxBodyBytes
+
+	// Browser origins allowed to call the service, comma-separated or "*"
+	allowedOrigins := cfg.HTTP.AllowedOrigins
+
+	// Per-client rate limit; RATE_LIMIT_RPS unset disables it
+	var limiter *middleware.RateLimiter
+	if cfg.HTTP.RateLimitRPS > 0 {
+		limiter = middleware.NewRateLimiter(cfg.HTTP.RateLimitRPS, cfg.HTTP.RateLimitBurst, cfg.HTTP.RateLimitTrustProxy)
+	}
+
+	// "rewrite" (default) or "redirect" for paths with a trailing slash
+	trailingSlashMode := cfg.HTTP.TrailingSlashMode
+	handler := middleware.Chain(http.DefaultServeMux.ServeHTTP,
+		middleware.RequestID,
+		middleware.LogRequests,
+		middleware.Recover,
+		func(next http.HandlerFunc) http.HandlerFunc { return middleware.CORS(allowedOrigins, next) },
+		limiter.Limit,
+		func(next http.HandlerFunc) http.HandlerFunc { return middleware.LimitRepeated(maxRepeated, next) },
+		func(next http.HandlerFunc) http.HandlerFunc { return middleware.LimitBody(maxBodyBytes, next) },
+		func(next http.HandlerFunc) http.HandlerFunc { return middleware.TrailingSlash(trailingSlashMode, next) },
+	)
+
+	slog.Info("User Service starting", "port", cfg.Port)
+	server := &http.Server{Addr: fmt.Sprintf(":%d", cfg.Port), Handler: handler, MaxHeaderBytes: maxHeaderBytes}
+	if err := server.ListenAndServe(); err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,17 @@
+package middleware
+
+import "net/http"
+
+// RequireIdempotencyKey rejects POST requests that lack an Idempotency-Key
+// header when enabled. It only checks that the header is present; a repeated
+// key is not deduplicated, which would take a store shared by every replica
+// of the service.
+func RequireIdempotencyKey(enabled bool, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if enabled && r.Method == http.MethodPost && r.Header.Get("Idempotency-Key") == "" {
+			http.Error(w, "Idempotency-Key header is required", http.StatusBadRequest)
+			return
+		}
+		next(w, r)
+	}
+}
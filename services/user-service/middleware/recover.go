@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover turns a panic in next into a 500 JSON error response and logs it
+// with its stack trace, so one failing request can't take down the server.
+// http.ErrAbortHandler is re-panicked since net/http uses it to abort
+// responses deliberately.
+func Recover(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if err, ok := p.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(p)
+			}
+
+			slog.ErrorContext(r.Context(), "panic serving request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"panic", p,
+				"stack", string(debug.Stack()),
+			)
+			// The status line can only be written once; if the handler got
+			// that far, the client sees a truncated response instead
+			if rec.status != 0 {
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":{"code":"internal","message":"internal server error"}}` + "\n"))
+		}()
+		next(rec, r)
+	}
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// User represents a user in our system
+type User struct {
+	ID    uint   `json:"id" gorm:"primaryKey"`
+	Name  string `json:"name" gorm:"not null"`
+	Email string `json:"email" gorm:"not null;uniqueIndex"`
+	// PasswordHash is the bcrypt hash of the user's password; empty when the
+	// user was created without one and so can't authenticate
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"-"`
+}
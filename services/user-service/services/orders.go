@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"user-service/dto"
+	"user-service/middleware"
+)
+
+// maxEmbeddedOrders caps how many orders are inlined into a user response
+const maxEmbeddedOrders = 50
+
+var orderClient = &http.Client{Timeout: 3 * time.Second}
+
+// FetchUserOrders fetches the orders placed by a user from the order service,
+// returning at most maxEmbeddedOrders of them. The request ID carried by ctx
+// is forwarded so both services log under the same ID.
+func (s *UserService) FetchUserOrders(ctx context.Context, userID uint) ([]dto.OrderResponse, error) {
+	url := fmt.Sprintf("%s/orders?user_id=%d&limit=%d", s.orderServiceURL, userID, maxEmbeddedOrders)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if id := middleware.RequestIDFromContext(ctx); id != "" {
+		req.Header.Set(middleware.RequestIDHeader, id)
+	}
+
+	resp, err := orderClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch orders: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("order service returned status %d", resp.StatusCode)
+	}
+
+	var page struct {
+		Items []dto.OrderResponse `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode orders: %v", err)
+	}
+
+	// Filter locally as well in case the order service ignores user_id
+	userOrders := make([]dto.OrderResponse, 0, len(page.Items))
+	for _, order := range page.Items {
+		if order.UserID != userID {
+			continue
+		}
+		userOrders = append(userOrders, order)
+		if len(userOrders) == maxEmbeddedOrders {
+			break
+		}
+	}
+
+	return userOrders, nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"user-service/config"
+	"user-service/dto"
+	"user-service/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUserNotFound is returned when no user has the requested ID
+	ErrUserNotFound = errors.New("user not found")
+	// ErrEmailTaken is returned when another user already has the email
+	ErrEmailTaken = errors.New("email already in use")
+	// ErrInvalidCredentials is returned when an email and password don't match
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// dummyPasswordHash is compared against when no usable hash exists, so that
+// unknown emails take as long to reject as wrong passwords
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+// UserService handles business logic for users
+type UserService struct {
+	db              *gorm.DB
+	orderServiceURL string
+}
+
+// NewUserService creates a new user service
+func NewUserService(db *gorm.DB, cfg config.Config) *UserService {
+	return &UserService{db: db, orderServiceURL: cfg.OrderServiceURL}
+}
+
+// CreateUser creates a new user
+func (s *UserService) CreateUser(ctx context.Context, req dto.CreateUserRequest) (*dto.UserResponse, error) {
+	taken, err := s.emailTaken(ctx, req.Email, 0)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrEmailTaken
+	}
+
+	user := models.User{
+		Name:  req.Name,
+		Email: req.Email,
+	}
+
+	if req.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = string(hash)
+	}
+
+	if err := s.db.WithContext(ctx).Create(&user).Error; err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrEmailTaken
+		}
+		return nil, err
+	}
+
+	return s.modelToResponse(&user), nil
+}
+
+// GetUser retrieves a user by ID
+func (s *UserService) GetUser(ctx context.Context, id uint) (*dto.UserResponse, error) {
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return s.modelToResponse(&user), nil
+}
+
+// GetAllUsers retrieves all users
+func (s *UserService) GetAllUsers(ctx context.Context) ([]dto.UserResponse, error) {
+	var users []models.User
+	if err := s.db.WithContext(ctx).Order("id").Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.UserResponse, 0, len(users))
+	for _, user := range users {
+		responses = append(responses, *s.modelToResponse(&user))
+	}
+
+	return responses, nil
+}
+
+// UpdateUser updates an existing user
+func (s *UserService) UpdateUser(ctx context.Context, id uint, req dto.UpdateUserRequest) (*dto.UserResponse, error) {
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	taken, err := s.emailTaken(ctx, req.Email, id)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrEmailTaken
+	}
+
+	user.Name = req.Name
+	user.Email = req.Email
+
+	if err := s.db.WithContext(ctx).Save(&user).Error; err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrEmailTaken
+		}
+		return nil, err
+	}
+
+	return s.modelToResponse(&user), nil
+}
+
+// DeleteUser deletes a user by ID
+func (s *UserService) DeleteUser(ctx context.Context, id uint) error {
+	result := s.db.WithContext(ctx).Delete(&models.User{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// VerifyCredentials returns the user with the given email when password
+// matches their stored bcrypt hash, and ErrInvalidCredentials otherwise
+func (s *UserService) VerifyCredentials(ctx context.Context, email, password string) (*dto.UserResponse, error) {
+	var user models.User
+	err := s.db.WithContext(ctx).Where("LOWER(email) = LOWER(?)", email).First(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Always run a bcrypt comparison so the response time doesn't reveal
+	// whether the email exists or has a password set
+	hasPassword := user.PasswordHash != ""
+	hash := dummyPasswordHash
+	if hasPassword {
+		hash = []byte(user.PasswordHash)
+	}
+	matches := bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil
+	if !hasPassword || !matches {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.modelToResponse(&user), nil
+}
+
+// emailTaken reports whether a user other than exceptID already has the
+// email, compared case-insensitively
+func (s *UserService) emailTaken(ctx context.Context, email string, exceptID uint) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&models.User{}).
+		Where("LOWER(email) = LOWER(?) AND id <> ?", email, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// modelToResponse converts a User model to UserResponse DTO
+func (s *UserService) modelToResponse(user *models.User) *dto.UserResponse {
+	return &dto.UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+	}
+}